@@ -4,5 +4,6 @@ go 1.25.5
 
 require (
 	github.com/hexagon-codes/toolkit v0.0.6
+	go.yaml.in/yaml/v3 v3.0.4
 	golang.org/x/sync v0.19.0
 )
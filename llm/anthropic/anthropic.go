@@ -20,6 +20,12 @@ const (
 	defaultBaseURL   = "https://api.anthropic.com/v1"
 	defaultModel     = "claude-sonnet-4-20250514"
 	anthropicVersion = "2023-06-01"
+
+	// minThinkingBudget extended thinking 允许的最小预算
+	minThinkingBudget = 1024
+
+	// minThinkingTopP extended thinking 开启时 top_p 允许的最小值
+	minThinkingTopP = 0.95
 )
 
 // Provider 实现 Anthropic Claude LLM 提供者
@@ -28,6 +34,7 @@ type Provider struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
+	catalog    *llm.ModelCatalog
 }
 
 // Option 是 Provider 的配置选项
@@ -74,6 +81,7 @@ func New(apiKey string, opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.catalog = llm.NewModelCatalog(curatedModels, p.fetchModels, 0)
 
 	return p
 }
@@ -85,6 +93,16 @@ func (p *Provider) Name() string {
 
 // Complete 执行非流式补全请求
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if req.N > 1 {
+		// 不支持原生 n 参数，通过并发请求模拟
+		return llm.CompleteChoices(ctx, p, req)
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -120,11 +138,24 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 		return nil, err
 	}
 
-	return p.parseResponse(&result, systemPrompt), nil
+	response := p.parseResponse(&result, systemPrompt)
+	if req.Reasoning.Excluded() {
+		response.StripReasoning()
+	}
+	return response, nil
 }
 
 // Stream 执行流式补全请求
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if req.N > 1 {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -159,54 +190,9 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 }
 
 // Models 返回可用模型列表
+// ListModels 成功后返回远端目录，否则返回内置列表
 func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
-		{
-			ID:          "claude-opus-4-20250514",
-			Name:        "Claude Opus 4",
-			Description: "Most capable Claude model for complex tasks",
-			MaxTokens:   200000,
-			InputCost:   15.00,
-			OutputCost:  75.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "claude-sonnet-4-20250514",
-			Name:        "Claude Sonnet 4",
-			Description: "Best balance of intelligence and speed",
-			MaxTokens:   200000,
-			InputCost:   3.00,
-			OutputCost:  15.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "claude-3-5-sonnet-20241022",
-			Name:        "Claude 3.5 Sonnet",
-			Description: "High performance with improved speed",
-			MaxTokens:   200000,
-			InputCost:   3.00,
-			OutputCost:  15.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "claude-3-5-haiku-20241022",
-			Name:        "Claude 3.5 Haiku",
-			Description: "Fast and cost-effective",
-			MaxTokens:   200000,
-			InputCost:   0.80,
-			OutputCost:  4.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "claude-3-opus-20240229",
-			Name:        "Claude 3 Opus",
-			Description: "Previous generation flagship model",
-			MaxTokens:   200000,
-			InputCost:   15.00,
-			OutputCost:  75.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-	}
+	return p.catalog.Models()
 }
 
 // CountTokens 计算消息的 Token 数量（简化实现）
@@ -271,10 +257,34 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 	if req.TopP != nil {
 		payload["top_p"] = *req.TopP
 	}
+	if req.TopK != nil {
+		payload["top_k"] = *req.TopK
+	}
 	if len(req.Stop) > 0 {
 		payload["stop_sequences"] = req.Stop
 	}
 
+	// extended thinking：budget_tokens 必须 >= 1024 且小于 max_tokens；
+	// 开启后 API 不接受 temperature/top_k，top_p 只能取 [0.95, 1]
+	if req.Reasoning.Enabled() {
+		delete(payload, "temperature")
+		delete(payload, "top_k")
+		if req.TopP != nil {
+			payload["top_p"] = min(max(*req.TopP, minThinkingTopP), 1)
+		}
+		budget := req.Reasoning.Budget()
+		if budget < minThinkingBudget {
+			budget = minThinkingBudget
+		}
+		payload["thinking"] = map[string]any{
+			"type":          "enabled",
+			"budget_tokens": budget,
+		}
+		if maxTokens := payload["max_tokens"].(int); maxTokens <= budget {
+			payload["max_tokens"] = budget + maxTokens
+		}
+	}
+
 	// 工具支持
 	if len(req.Tools) > 0 {
 		tools := make([]map[string]any, len(req.Tools))
@@ -288,6 +298,10 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 		payload["tools"] = tools
 	}
 
+	if err := llm.MergeExtraBody(payload, req.ExtraBody, "system"); err != nil {
+		return nil, "", err
+	}
+
 	body, err := json.Marshal(payload)
 	return body, systemPrompt, err
 }
@@ -306,25 +320,23 @@ func convertRole(role llm.Role) string {
 
 // Anthropic API 响应结构
 type anthropicResponse struct {
-	ID           string             `json:"id"`
-	Type         string             `json:"type"`
-	Role         string             `json:"role"`
-	Model        string             `json:"model"`
-	Content      []anthropicContent `json:"content"`
-	StopReason   string             `json:"stop_reason"`
-	StopSequence string             `json:"stop_sequence"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	ID           string              `json:"id"`
+	Type         string              `json:"type"`
+	Role         string              `json:"role"`
+	Model        string              `json:"model"`
+	Content      []anthropicContent  `json:"content"`
+	StopReason   string              `json:"stop_reason"`
+	StopSequence string              `json:"stop_sequence"`
+	Usage        streamx.ClaudeUsage `json:"usage"`
 }
 
 type anthropicContent struct {
-	Type  string `json:"type"`
-	Text  string `json:"text,omitempty"`
-	ID    string `json:"id,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Input any    `json:"input,omitempty"`
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Thinking string `json:"thinking,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Input    any    `json:"input,omitempty"`
 }
 
 // parseResponse 解析响应
@@ -333,11 +345,7 @@ func (p *Provider) parseResponse(resp *anthropicResponse, _ string) *llm.Complet
 		ID:           resp.ID,
 		Model:        resp.Model,
 		FinishReason: resp.StopReason,
-		Usage: llm.Usage{
-			PromptTokens:     resp.Usage.InputTokens,
-			CompletionTokens: resp.Usage.OutputTokens,
-			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
-		},
+		Usage:        resp.Usage.ToUsage(),
 	}
 
 	// 处理内容
@@ -345,6 +353,9 @@ func (p *Provider) parseResponse(resp *anthropicResponse, _ string) *llm.Complet
 		switch content.Type {
 		case "text":
 			result.Content += content.Text
+		case "thinking":
+			// extended thinking；redacted_thinking 为加密内容，不暴露
+			result.Reasoning += content.Thinking
 		case "tool_use":
 			args, _ := json.Marshal(content.Input)
 			result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
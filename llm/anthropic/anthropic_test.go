@@ -0,0 +1,71 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func buildPayload(t *testing.T, req llm.CompletionRequest) map[string]any {
+	t.Helper()
+	req.Messages = []llm.Message{llm.UserMessage("hi")}
+	body, _, err := New("key").buildRequestBody(req, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody error: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	return payload
+}
+
+func TestBuildRequestBody_ThinkingSampling(t *testing.T) {
+	temperature, topP, topK := 0.3, 0.5, 40
+	payload := buildPayload(t, llm.CompletionRequest{
+		MaxTokens:   1000,
+		Temperature: &temperature,
+		TopP:        &topP,
+		TopK:        &topK,
+		Reasoning:   &llm.ReasoningConfig{BudgetTokens: 2048},
+	})
+
+	thinking, _ := payload["thinking"].(map[string]any)
+	if thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2048) {
+		t.Errorf("thinking = %v", payload["thinking"])
+	}
+	// max_tokens 必须大于 budget_tokens
+	if payload["max_tokens"] != float64(3048) {
+		t.Errorf("max_tokens = %v, want 3048", payload["max_tokens"])
+	}
+	if _, ok := payload["temperature"]; ok {
+		t.Errorf("temperature should be dropped with thinking, got %v", payload["temperature"])
+	}
+	if _, ok := payload["top_k"]; ok {
+		t.Errorf("top_k should be dropped with thinking, got %v", payload["top_k"])
+	}
+	if payload["top_p"] != 0.95 {
+		t.Errorf("top_p = %v, want 0.95", payload["top_p"])
+	}
+}
+
+func TestBuildRequestBody_MinThinkingBudget(t *testing.T) {
+	payload := buildPayload(t, llm.CompletionRequest{Reasoning: &llm.ReasoningConfig{Effort: llm.ReasoningEffortMinimal, BudgetTokens: 100}})
+	if thinking, _ := payload["thinking"].(map[string]any); thinking["budget_tokens"] != float64(minThinkingBudget) {
+		t.Errorf("thinking = %v, want budget %d", payload["thinking"], minThinkingBudget)
+	}
+}
+
+func TestBuildRequestBody_ReasoningUnspecified(t *testing.T) {
+	temperature, topK := 0.3, 40
+	for _, cfg := range []*llm.ReasoningConfig{nil, {Exclude: true}, {Effort: llm.ReasoningEffortNone}} {
+		payload := buildPayload(t, llm.CompletionRequest{Temperature: &temperature, TopK: &topK, Reasoning: cfg})
+		if _, ok := payload["thinking"]; ok {
+			t.Errorf("%+v: thinking should be absent, got %v", cfg, payload["thinking"])
+		}
+		if payload["temperature"] != 0.3 || payload["top_k"] != float64(40) || payload["max_tokens"] != float64(4096) {
+			t.Errorf("%+v: sampling params changed: %v", cfg, payload)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ListModels 调用 /models 获取可用模型，并与内置元数据合并（带缓存）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.catalog.List(ctx)
+}
+
+// fetchModels 请求 /models 端点（单页最多 1000 个）
+func (p *Provider) fetchModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models?limit=1000", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("anthropic api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("anthropic api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	var result struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	models := make([]llm.ModelInfo, len(result.Data))
+	for i, m := range result.Data {
+		models[i] = llm.ModelInfo{ID: m.ID, Name: m.DisplayName}
+	}
+	return models, nil
+}
+
+// 确保实现了 ModelLister 接口
+var _ llm.ModelLister = (*Provider)(nil)
+
+// curatedModels 内置模型元数据（上下文长度、特性、价格）
+var curatedModels = []llm.ModelInfo{
+	{
+		ID:          "claude-opus-4-20250514",
+		Name:        "Claude Opus 4",
+		Description: "Most capable Claude model for complex tasks",
+		MaxTokens:   200000,
+		InputCost:   15.00,
+		OutputCost:  75.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+	},
+	{
+		ID:          "claude-sonnet-4-20250514",
+		Name:        "Claude Sonnet 4",
+		Description: "Best balance of intelligence and speed",
+		MaxTokens:   200000,
+		InputCost:   3.00,
+		OutputCost:  15.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+	},
+	{
+		ID:          "claude-3-5-sonnet-20241022",
+		Name:        "Claude 3.5 Sonnet",
+		Description: "High performance with improved speed",
+		MaxTokens:   200000,
+		InputCost:   3.00,
+		OutputCost:  15.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+	{
+		ID:          "claude-3-5-haiku-20241022",
+		Name:        "Claude 3.5 Haiku",
+		Description: "Fast and cost-effective",
+		MaxTokens:   200000,
+		InputCost:   0.80,
+		OutputCost:  4.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+	{
+		ID:          "claude-3-opus-20240229",
+		Name:        "Claude 3 Opus",
+		Description: "Previous generation flagship model",
+		MaxTokens:   200000,
+		InputCost:   15.00,
+		OutputCost:  75.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+}
@@ -20,16 +20,13 @@
 package ark
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
 	"github.com/hexagon-codes/ai-core/streamx"
 	"github.com/hexagon-codes/toolkit/net/httpx"
 )
@@ -47,6 +44,7 @@ type Provider struct {
 	model      string
 	endpointID string // 火山引擎的端点 ID (可选)
 	httpClient *http.Client
+	chat       *openai.Provider // 对话与批量接口的兼容实现，New 中创建
 }
 
 // Option 是 Provider 的配置选项
@@ -106,6 +104,11 @@ func New(apiKey string, opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.chat = openai.NewCompatible(profile, p.apiKey,
+		openai.WithBaseURL(p.baseURL),
+		openai.WithModel(p.model),
+		openai.WithHTTPClient(p.httpClient),
+	)
 
 	return p
 }
@@ -116,84 +119,56 @@ func (p *Provider) Name() string {
 }
 
 // Complete 执行非流式补全请求
+// 不支持原生 n 参数，n>1 时通过并发请求模拟
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
-	if req.Model == "" {
-		req.Model = p.model
-	}
-
-	body, err := p.buildRequestBody(req, false)
-	if err != nil {
-		return nil, err
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-
-	p.setHeaders(httpReq)
-
-	resp, err := p.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("ark request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("ark api error: %s (failed to read body: %v)", resp.Status, readErr)
-		}
-		return nil, fmt.Errorf("ark api error: %s, body: %s", resp.Status, string(bodyBytes))
-	}
-
-	var result arkResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	return p.parseResponse(&result), nil
+	return p.compatible().Complete(ctx, p.resolveModel(req))
 }
 
 // Stream 执行流式补全请求
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
-	if req.Model == "" {
-		req.Model = p.model
-	}
-
-	body, err := p.buildRequestBody(req, true)
-	if err != nil {
-		return nil, err
-	}
+	return p.compatible().Stream(ctx, p.resolveModel(req))
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
+// Models 返回可用模型列表
+func (p *Provider) Models() []llm.ModelInfo {
+	return p.compatible().Models()
+}
 
-	p.setHeaders(httpReq)
+// ListModels 获取远端模型目录并与内置元数据合并（带缓存）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.compatible().ListModels(ctx)
+}
 
-	resp, err := p.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("ark request failed: %w", err)
-	}
+// CountTokens 计算消息的 Token 数量（简化实现）
+func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
+	return p.compatible().CountTokens(messages)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if readErr != nil {
-			return nil, fmt.Errorf("ark api error: %s (failed to read body: %v)", resp.Status, readErr)
-		}
-		return nil, fmt.Errorf("ark api error: %s, body: %s", resp.Status, string(bodyBytes))
+// resolveModel 设置了端点 ID 时，使用端点 ID 作为 model
+func (p *Provider) resolveModel(req llm.CompletionRequest) llm.CompletionRequest {
+	if p.endpointID != "" {
+		req.Model = p.endpointID
 	}
+	return req
+}
 
-	// 豆包使用 OpenAI 兼容格式
-	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.OpenAIFormat), nil
+// compatible 返回指向同一端点、按方舟 Profile 配置的 OpenAI Provider
+func (p *Provider) compatible() *openai.Provider {
+	return p.chat
 }
 
-// Models 返回可用模型列表
-func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
+// profile 火山方舟的差异：
+//   - 不支持 n、logprobs、seed、user、top_k、repetition_penalty
+//   - 深度思考模型（doubao-seed 系列）使用 thinking 开关，不支持预算
+var profile = openai.Profile{
+	Name:          "ark",
+	BaseURL:       defaultBaseURL,
+	DefaultModel:  defaultModel,
+	APIKeyEnv:     []string{"ARK_API_KEY", "VOLC_ACCESSKEY"},
+	Unsupported:   []string{"n", "logprobs", "seed", "user", "top_k", "repetition_penalty"},
+	Reasoning:     openai.ThinkingToggle,
+	CharsPerToken: 2.5, // 中英混合
+	Models: []llm.ModelInfo{
 		{
 			ID:          "doubao-pro-32k",
 			Name:        "Doubao Pro 32K",
@@ -239,6 +214,15 @@ func (p *Provider) Models() []llm.ModelInfo {
 			OutputCost:  1.00,
 			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
 		},
+		{
+			ID:          "doubao-seed-1-6-250615",
+			Name:        "Doubao Seed 1.6",
+			Description: "豆包深度思考模型，支持开关思考模式",
+			MaxTokens:   262144,
+			InputCost:   0.80,
+			OutputCost:  8.00,
+			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
 		{
 			ID:          "doubao-vision-pro-32k",
 			Name:        "Doubao Vision Pro 32K",
@@ -257,142 +241,11 @@ func (p *Provider) Models() []llm.ModelInfo {
 			OutputCost:  2.00,
 			Features:    []string{llm.FeatureStreaming},
 		},
-	}
+	},
 }
 
-// CountTokens 计算消息的 Token 数量（简化实现）
-func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
-	// 简化估算：中文约 1.5 字符一个 token，英文约 4 字符一个 token
-	// 这里使用保守估计
-	var total int
-	for _, msg := range messages {
-		// 假设混合语言，使用 2.5 字符每 token
-		total += len(msg.Content) * 2 / 5
-	}
-	return total, nil
-}
-
-// setHeaders 设置请求头
-func (p *Provider) setHeaders(req *http.Request) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
-}
-
-// buildRequestBody 构建请求体
-func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]byte, error) {
-	// 如果设置了端点 ID，使用端点 ID 作为 model
-	model := req.Model
-	if p.endpointID != "" {
-		model = p.endpointID
-	}
-
-	payload := map[string]any{
-		"model":    model,
-		"messages": convertMessages(req.Messages),
-		"stream":   stream,
-	}
-
-	if len(req.Tools) > 0 {
-		payload["tools"] = req.Tools
-	}
-	if req.ToolChoice != nil {
-		payload["tool_choice"] = req.ToolChoice
-	}
-	if req.MaxTokens > 0 {
-		payload["max_tokens"] = req.MaxTokens
-	}
-	if req.Temperature != nil {
-		payload["temperature"] = *req.Temperature
-	}
-	if req.TopP != nil {
-		payload["top_p"] = *req.TopP
-	}
-	if len(req.Stop) > 0 {
-		payload["stop"] = req.Stop
-	}
-
-	// ResponseFormat 支持（兼容 OpenAI 格式）
-	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
-		payload["response_format"] = map[string]any{"type": "json_object"}
-	}
-
-	return json.Marshal(payload)
-}
-
-// convertMessages 转换消息格式
-func convertMessages(messages []llm.Message) []map[string]any {
-	result := make([]map[string]any, len(messages))
-	for i, msg := range messages {
-		m := map[string]any{
-			"role":    string(msg.Role),
-			"content": msg.Content,
-		}
-		if msg.Name != "" {
-			m["name"] = msg.Name
-		}
-		result[i] = m
-	}
-	return result
-}
-
-// 豆包 API 响应结构（兼容 OpenAI 格式）
-type arkResponse struct {
-	ID      string `json:"id"`
-	Model   string `json:"model"`
-	Created int64  `json:"created"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string `json:"role"`
-			Content   string `json:"content"`
-			ToolCalls []struct {
-				ID       string `json:"id"`
-				Type     string `json:"type"`
-				Function struct {
-					Name      string `json:"name"`
-					Arguments string `json:"arguments"`
-				} `json:"function"`
-			} `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-}
-
-// parseResponse 解析响应
-func (p *Provider) parseResponse(resp *arkResponse) *llm.CompletionResponse {
-	result := &llm.CompletionResponse{
-		ID:      resp.ID,
-		Model:   resp.Model,
-		Created: resp.Created,
-		Usage: llm.Usage{
-			PromptTokens:     resp.Usage.PromptTokens,
-			CompletionTokens: resp.Usage.CompletionTokens,
-			TotalTokens:      resp.Usage.TotalTokens,
-		},
-	}
-
-	if len(resp.Choices) > 0 {
-		choice := resp.Choices[0]
-		result.Content = choice.Message.Content
-		result.FinishReason = choice.FinishReason
-
-		for _, tc := range choice.Message.ToolCalls {
-			result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
-				ID:        tc.ID,
-				Type:      tc.Type,
-				Name:      tc.Function.Name,
-				Arguments: tc.Function.Arguments,
-			})
-		}
-	}
-
-	return result
-}
-
-// 确保实现了 Provider 接口
-var _ llm.Provider = (*Provider)(nil)
+// 确保实现了 Provider、ModelLister 接口
+var (
+	_ llm.Provider    = (*Provider)(nil)
+	_ llm.ModelLister = (*Provider)(nil)
+)
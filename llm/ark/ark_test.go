@@ -0,0 +1,108 @@
+package ark
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+const chatReply = `{"id":"c1","model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+
+// recorder 记录每次请求的请求体
+type recorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+}
+
+func (rec *recorder) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		rec.mu.Lock()
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+		io.WriteString(w, chatReply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRegistry_EndpointIDAndQuirks(t *testing.T) {
+	t.Setenv("ARK_ENDPOINT_ID", "")
+	rec := &recorder{}
+	srv := rec.server(t)
+
+	p, err := llm.NewProvider(llm.ProviderConfig{
+		Type:    "ark",
+		APIKey:  "k",
+		BaseURL: srv.URL,
+		Options: map[string]string{"endpoint_id": "ep-20250101-abc"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+
+	seed, topK := 7, 40
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{
+		Model:     "doubao-seed-1-6-250615",
+		Messages:  llm.NewMessages("", "hi"),
+		Seed:      &seed,
+		TopK:      &topK,
+		User:      "u1",
+		Reasoning: &llm.ReasoningConfig{Effort: llm.ReasoningEffortNone},
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+
+	body := rec.bodies[0]
+	if body["model"] != "ep-20250101-abc" {
+		t.Errorf("model = %v, want endpoint ID", body["model"])
+	}
+	for _, key := range []string{"seed", "top_k", "user"} {
+		if _, ok := body[key]; ok {
+			t.Errorf("unsupported %s should be dropped", key)
+		}
+	}
+	if thinking, _ := body["thinking"].(map[string]any); thinking["type"] != "disabled" {
+		t.Errorf("thinking = %v", body["thinking"])
+	}
+}
+
+func TestComplete_NoReasoningAndEmulatedN(t *testing.T) {
+	t.Setenv("ARK_ENDPOINT_ID", "")
+	rec := &recorder{}
+	srv := rec.server(t)
+	p := New("k", WithBaseURL(srv.URL))
+
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{Messages: llm.NewMessages("", "hi"), N: 2})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if len(resp.Choices) != 2 || len(rec.bodies) != 2 {
+		t.Fatalf("choices = %d, requests = %d, want 2 each", len(resp.Choices), len(rec.bodies))
+	}
+	for _, body := range rec.bodies {
+		if body["model"] != defaultModel {
+			t.Errorf("model = %v", body["model"])
+		}
+		if _, ok := body["n"]; ok {
+			t.Error("n should not be sent")
+		}
+		if _, ok := body["thinking"]; ok {
+			t.Error("thinking should not be sent without a reasoning config")
+		}
+	}
+
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{Messages: llm.NewMessages("", "hi"), Logprobs: true})
+	var unsupported *llm.UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("logprobs err = %v, want *llm.UnsupportedError", err)
+	}
+}
@@ -0,0 +1,39 @@
+package ark
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// CreateBatch 创建批量任务
+//
+// 火山方舟的 Batch API 与 OpenAI 一致，文件、任务接口与单行请求体均复用 openai 实现，
+// 仅在编码前做端点 ID 映射。
+func (p *Provider) CreateBatch(ctx context.Context, req llm.BatchRequest) (*llm.BatchJob, error) {
+	items := make([]llm.BatchItem, len(req.Items))
+	for i, item := range req.Items {
+		item.Request = p.resolveModel(item.Request)
+		items[i] = item
+	}
+	req.Items = items
+	return p.compatible().CreateBatch(ctx, req)
+}
+
+// GetBatch 查询批量任务状态
+func (p *Provider) GetBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	return p.compatible().GetBatch(ctx, batchID)
+}
+
+// GetBatchResults 下载批量任务结果，按 CustomID 索引
+func (p *Provider) GetBatchResults(ctx context.Context, batchID string) (map[string]llm.BatchResult, error) {
+	return p.compatible().GetBatchResults(ctx, batchID)
+}
+
+// CancelBatch 取消批量任务
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	return p.compatible().CancelBatch(ctx, batchID)
+}
+
+// 确保豆包 Provider 实现了 BatchProvider 接口
+var _ llm.BatchProvider = (*Provider)(nil)
@@ -0,0 +1,20 @@
+package ark
+
+import "github.com/hexagon-codes/ai-core/llm"
+
+// 注册为 "ark"，Options 支持 endpoint_id（火山引擎推理端点 ID）
+func init() {
+	llm.RegisterProvider("ark", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		var opts []Option
+		if cfg.BaseURL != "" {
+			opts = append(opts, WithBaseURL(cfg.BaseURL))
+		}
+		if cfg.Model != "" {
+			opts = append(opts, WithModel(cfg.Model))
+		}
+		if id := cfg.Option("endpoint_id"); id != "" {
+			opts = append(opts, WithEndpointID(id))
+		}
+		return New(cfg.APIKey, opts...), nil
+	})
+}
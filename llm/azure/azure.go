@@ -0,0 +1,298 @@
+// Package azure provides Azure OpenAI LLM provider implementation.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
+	"github.com/hexagon-codes/ai-core/streamx"
+	"github.com/hexagon-codes/toolkit/net/httpx"
+)
+
+const (
+	defaultAPIVersion = "2024-10-21"
+	defaultModel      = "gpt-4o"
+)
+
+// TokenSource 提供 Microsoft Entra ID 访问令牌
+//
+// 实现方负责缓存与刷新，每次请求都会调用 Token。
+// 可直接适配 azidentity 的凭据：
+//
+//	azure.TokenSourceFunc(func(ctx context.Context) (string, error) {
+//	    tk, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+//	        Scopes: []string{"https://cognitiveservices.azure.com/.default"},
+//	    })
+//	    return tk.Token, err
+//	})
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc 函数适配器
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token 实现 TokenSource 接口
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// Provider 实现 Azure OpenAI LLM 提供者
+//
+// 请求格式与 OpenAI 一致，区别在于：
+//   - URL 按部署（deployment）划分：{endpoint}/openai/deployments/{deployment}/chat/completions?api-version=...
+//   - 认证使用 api-key 请求头，或 Entra ID 的 Bearer 令牌
+//   - 内容过滤命中时返回 400 content_filter 错误，正常响应附带 content_filter_results 标注
+//
+// 每个部署复用一个 openai.Provider，URL 与认证由自定义 Transport 改写。
+type Provider struct {
+	apiKey      string
+	endpoint    string
+	apiVersion  string
+	model       string
+	deployments map[string]string // 模型名 -> 部署名
+	tokenSource TokenSource
+	httpClient  *http.Client
+
+	mu      sync.Mutex
+	clients map[string]*openai.Provider // 部署名 -> Provider
+}
+
+// Option 是 Provider 的配置选项
+type Option func(*Provider)
+
+// WithEndpoint 设置资源端点（如 "https://my-resource.openai.azure.com"）
+func WithEndpoint(endpoint string) Option {
+	return func(p *Provider) {
+		p.endpoint = endpoint
+	}
+}
+
+// WithAPIVersion 设置 api-version 查询参数
+func WithAPIVersion(version string) Option {
+	return func(p *Provider) {
+		p.apiVersion = version
+	}
+}
+
+// WithModel 设置默认模型
+func WithModel(model string) Option {
+	return func(p *Provider) {
+		p.model = model
+	}
+}
+
+// WithDeployment 设置模型到部署名的映射
+// 未映射的模型直接作为部署名使用
+func WithDeployment(model, deployment string) Option {
+	return func(p *Provider) {
+		p.deployments[model] = deployment
+	}
+}
+
+// WithTokenSource 使用 Entra ID 令牌认证，设置后忽略 apiKey
+func WithTokenSource(ts TokenSource) Option {
+	return func(p *Provider) {
+		p.tokenSource = ts
+	}
+}
+
+// WithHTTPClient 设置 HTTP 客户端
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.httpClient = client
+	}
+}
+
+// New 创建 Azure OpenAI Provider
+// apiKey 可以为空，会从环境变量 AZURE_OPENAI_API_KEY 读取；
+// 端点与 api-version 默认读取 AZURE_OPENAI_ENDPOINT、AZURE_OPENAI_API_VERSION
+func New(apiKey string, opts ...Option) *Provider {
+	if apiKey == "" {
+		apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
+	}
+
+	p := &Provider{
+		apiKey:      apiKey,
+		endpoint:    os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		apiVersion:  os.Getenv("AZURE_OPENAI_API_VERSION"),
+		model:       defaultModel,
+		deployments: make(map[string]string),
+		httpClient:  httpx.RawClient(httpx.WithResponseHeaderTimeout(120 * time.Second)),
+		clients:     make(map[string]*openai.Provider),
+	}
+	if p.apiVersion == "" {
+		p.apiVersion = defaultAPIVersion
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.endpoint = strings.TrimRight(p.endpoint, "/")
+
+	return p
+}
+
+// Name 返回提供者名称
+func (p *Provider) Name() string {
+	return "azure"
+}
+
+// Complete 执行非流式补全请求
+// 响应中的 content_filter_results 标注写入 CompletionResponse.ContentFilter
+func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+
+	var raw bytes.Buffer
+	resp, err := p.client(req.Model).Complete(context.WithValue(ctx, captureKey{}, &raw), req)
+	if err != nil {
+		return nil, err
+	}
+	resp.ContentFilter = parseFilterAnnotations(raw.Bytes())
+	return resp, nil
+}
+
+// Stream 执行流式补全请求
+func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+	return p.client(req.Model).Stream(ctx, req)
+}
+
+// Models 返回已配置部署的模型列表
+// 与 OpenAI 同名的模型沿用其能力描述
+func (p *Provider) Models() []llm.ModelInfo {
+	known := make(map[string]llm.ModelInfo)
+	for _, m := range openai.New("").Models() {
+		known[m.ID] = m
+	}
+
+	ids := []string{p.model}
+	for model := range p.deployments {
+		if model != p.model {
+			ids = append(ids, model)
+		}
+	}
+
+	models := make([]llm.ModelInfo, 0, len(ids))
+	for _, id := range ids {
+		info, ok := known[id]
+		if !ok {
+			info = llm.ModelInfo{ID: id, Name: id, Features: []string{llm.FeatureStreaming}}
+		}
+		info.Description = "Azure deployment " + p.deployment(id)
+		models = append(models, info)
+	}
+	return models
+}
+
+// CountTokens 计算消息的 Token 数量（简化实现）
+func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
+	return p.client(p.model).CountTokens(messages)
+}
+
+// deployment 返回模型对应的部署名
+func (p *Provider) deployment(model string) string {
+	if d, ok := p.deployments[model]; ok {
+		return d
+	}
+	return model
+}
+
+// client 返回部署对应的 OpenAI Provider，按部署缓存
+func (p *Provider) client(model string) *openai.Provider {
+	deployment := p.deployment(model)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[deployment]; ok {
+		return c
+	}
+
+	base := p.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := openai.New("-", // 占位，认证头由 transport 覆盖
+		openai.WithBaseURL(p.endpoint+"/openai/deployments/"+url.PathEscape(deployment)),
+		openai.WithModel(model),
+		openai.WithHTTPClient(&http.Client{
+			Transport: &transport{provider: p, base: base},
+			Timeout:   p.httpClient.Timeout,
+		}),
+	)
+	p.clients[deployment] = c
+	return c
+}
+
+// captureKey 上下文键，值为 *bytes.Buffer，用于截留响应体以解析标注
+type captureKey struct{}
+
+// transport 将 OpenAI 格式请求改写为 Azure 格式
+type transport struct {
+	provider *Provider
+	base     http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	out := req.Clone(ctx)
+
+	q := out.URL.Query()
+	q.Set("api-version", t.provider.apiVersion)
+	out.URL.RawQuery = q.Encode()
+
+	out.Header.Del("Authorization")
+	if ts := t.provider.tokenSource; ts != nil {
+		token, err := ts.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("azure token source: %w", err)
+		}
+		out.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		out.Header.Set("api-key", t.provider.apiKey)
+	}
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusBadRequest {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		if filterErr := parseContentFilterError(body); filterErr != nil {
+			return nil, filterErr
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+
+	if buf, ok := ctx.Value(captureKey{}).(*bytes.Buffer); ok && resp.StatusCode == http.StatusOK {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.TeeReader(resp.Body, buf), resp.Body}
+	}
+	return resp, nil
+}
+
+// 确保实现了 Provider 接口
+var _ llm.Provider = (*Provider)(nil)
@@ -0,0 +1,67 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestComplete_DeploymentURLAndAnnotations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/prod-gpt4o/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-06-01" {
+			t.Errorf("api-version = %q", v)
+		}
+		if r.Header.Get("api-key") != "secret" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected auth headers: %v", r.Header)
+		}
+		io.WriteString(w, `{"id":"c1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop",
+			"content_filter_results":{"hate":{"filtered":false,"severity":"low"}}}],
+			"prompt_filter_results":[{"prompt_index":0,"content_filter_results":{"violence":{"filtered":false,"severity":"medium"},"jailbreak":{"filtered":false,"detected":false}}}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("secret", WithEndpoint(srv.URL+"/"), WithAPIVersion("2024-06-01"), WithDeployment("gpt-4o", "prod-gpt4o"))
+	resp, err := p.Complete(context.Background(), llm.CompletionRequest{Messages: llm.NewMessages("", "hello")})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.Content != "hi" {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	cf := resp.ContentFilter
+	if cf == nil || cf.Prompt.Flagged || cf.Prompt.CategoryScores["violence"] < 0.6 || cf.Completion.CategoryScores["hate"] == 0 {
+		t.Errorf("unexpected content filter annotations: %+v", cf)
+	}
+}
+
+func TestComplete_ContentFilterErrorAndToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer entra-token" || r.Header.Get("api-key") != "" {
+			t.Errorf("unexpected auth headers: %v", r.Header)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":{"code":"content_filter","message":"The response was filtered","status":400,
+			"innererror":{"code":"ResponsibleAIPolicyViolation","content_filter_result":{"violence":{"filtered":true,"severity":"high"}}}}}`)
+	}))
+	defer srv.Close()
+
+	p := New("", WithEndpoint(srv.URL), WithTokenSource(TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return "entra-token", nil
+	})))
+	_, err := p.Complete(context.Background(), llm.CompletionRequest{Messages: llm.NewMessages("", "bad")})
+	if !errors.Is(err, llm.ErrContentFlagged) {
+		t.Fatalf("err = %v, want ErrContentFlagged", err)
+	}
+	var filterErr *ContentFilterError
+	if !errors.As(err, &filterErr) || !filterErr.Result.Categories["violence"] {
+		t.Errorf("unexpected ContentFilterError: %+v", filterErr)
+	}
+}
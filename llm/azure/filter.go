@@ -0,0 +1,141 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ContentFilterError Azure 内容过滤拦截错误
+//
+// 请求的输入（或输出）触发 Azure 内容过滤策略时返回，
+// 可通过 errors.Is(err, llm.ErrContentFlagged) 判断。
+type ContentFilterError struct {
+	// Code 错误码（通常为 "content_filter"）
+	Code string
+
+	// Message 错误信息
+	Message string
+
+	// Result 各类别的判定
+	Result llm.ModerationResult
+}
+
+// Error 实现 error 接口
+func (e *ContentFilterError) Error() string {
+	return fmt.Sprintf("azure: content filtered (%s): %s", e.Code, e.Message)
+}
+
+// Is 使 errors.Is(err, llm.ErrContentFlagged) 成立
+func (e *ContentFilterError) Is(target error) bool {
+	return target == llm.ErrContentFlagged
+}
+
+// filterCategory Azure 单个类别的判定
+// 有害内容类别返回 severity，jailbreak/protected_material 等返回 detected
+type filterCategory struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity,omitempty"`
+	Detected bool   `json:"detected,omitempty"`
+}
+
+// filterSeverity 严重程度到分数的映射
+var filterSeverity = map[string]float64{
+	"safe":   0,
+	"low":    1.0 / 3,
+	"medium": 2.0 / 3,
+	"high":   1,
+}
+
+// toModerationResult 将 Azure 的类别判定转换为统一的审核结果
+func toModerationResult(categories map[string]filterCategory) llm.ModerationResult {
+	result := llm.ModerationResult{
+		Categories:     make(map[string]bool, len(categories)),
+		CategoryScores: make(map[string]float64, len(categories)),
+	}
+	for name, c := range categories {
+		result.Categories[name] = c.Filtered
+		if score, ok := filterSeverity[c.Severity]; ok {
+			result.CategoryScores[name] = score
+		} else if c.Detected {
+			result.CategoryScores[name] = 1
+		}
+		if c.Filtered {
+			result.Flagged = true
+		}
+	}
+	return result
+}
+
+// parseContentFilterError 从 400 响应体中识别内容过滤错误，非过滤错误返回 nil
+func parseContentFilterError(body []byte) *ContentFilterError {
+	var resp struct {
+		Error struct {
+			Code       string `json:"code"`
+			Message    string `json:"message"`
+			InnerError *struct {
+				Code                string                    `json:"code"`
+				ContentFilterResult map[string]filterCategory `json:"content_filter_result"`
+			} `json:"innererror"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil
+	}
+
+	e := resp.Error
+	inner := e.InnerError
+	if e.Code != "content_filter" && (inner == nil || inner.Code != "ResponsibleAIPolicyViolation") {
+		return nil
+	}
+
+	filterErr := &ContentFilterError{Code: e.Code, Message: e.Message}
+	if inner != nil {
+		filterErr.Result = toModerationResult(inner.ContentFilterResult)
+	}
+	filterErr.Result.Flagged = true
+	return filterErr
+}
+
+// parseFilterAnnotations 解析正常响应中的内容过滤标注，未返回标注时为 nil
+func parseFilterAnnotations(body []byte) *llm.ContentFilterResults {
+	var resp struct {
+		PromptFilterResults []struct {
+			ContentFilterResults map[string]filterCategory `json:"content_filter_results"`
+		} `json:"prompt_filter_results"`
+		Choices []struct {
+			ContentFilterResults map[string]filterCategory `json:"content_filter_results"`
+		} `json:"choices"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &resp) != nil {
+		return nil
+	}
+
+	prompt := make(map[string]filterCategory)
+	for _, r := range resp.PromptFilterResults {
+		for name, c := range r.ContentFilterResults {
+			// 多段输入时合并为最严格的判定
+			if existing, ok := prompt[name]; ok {
+				c.Filtered = c.Filtered || existing.Filtered
+				c.Detected = c.Detected || existing.Detected
+				if filterSeverity[existing.Severity] > filterSeverity[c.Severity] {
+					c.Severity = existing.Severity
+				}
+			}
+			prompt[name] = c
+		}
+	}
+	var completion map[string]filterCategory
+	if len(resp.Choices) > 0 {
+		completion = resp.Choices[0].ContentFilterResults
+	}
+	if len(prompt) == 0 && len(completion) == 0 {
+		return nil
+	}
+
+	return &llm.ContentFilterResults{
+		Prompt:     toModerationResult(prompt),
+		Completion: toModerationResult(completion),
+	}
+}
@@ -0,0 +1,34 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// 注册为 "azure"，Options 支持：
+//   - endpoint: 资源端点（BaseURL 亦可）
+//   - api_version: api-version 查询参数
+//   - deployments: 模型到部署名的映射，如 "gpt-4o=prod-4o,gpt-4o-mini=prod-mini"
+func init() {
+	llm.RegisterProvider("azure", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		var opts []Option
+		if endpoint := cfg.Option("endpoint"); endpoint != "" {
+			opts = append(opts, WithEndpoint(endpoint))
+		} else if cfg.BaseURL != "" {
+			opts = append(opts, WithEndpoint(cfg.BaseURL))
+		}
+		if v := cfg.Option("api_version"); v != "" {
+			opts = append(opts, WithAPIVersion(v))
+		}
+		if cfg.Model != "" {
+			opts = append(opts, WithModel(cfg.Model))
+		}
+		for _, pair := range strings.Split(cfg.Option("deployments"), ",") {
+			if model, deployment, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+				opts = append(opts, WithDeployment(model, deployment))
+			}
+		}
+		return New(cfg.APIKey, opts...), nil
+	})
+}
@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultModelCatalogTTL 远端模型目录的默认缓存时间
+const DefaultModelCatalogTTL = time.Hour
+
+// ModelFetchFunc 从远端获取模型列表
+// 返回的 ModelInfo 通常只有 ID，厂商提供的字段（如上下文长度）可一并填写
+type ModelFetchFunc func(ctx context.Context) ([]ModelInfo, error)
+
+// ModelCatalog 带缓存的模型目录，供实现 ModelLister 的 Provider 复用
+//
+// 远端列表决定有哪些模型可用，内置元数据（curated）补充上下文长度、特性与价格。
+// 从未成功拉取时 Models 返回内置列表。
+type ModelCatalog struct {
+	curated []ModelInfo
+	fetch   ModelFetchFunc
+	ttl     time.Duration
+
+	mu        sync.RWMutex
+	models    []ModelInfo
+	fetchedAt time.Time
+}
+
+// NewModelCatalog 创建模型目录，ttl <= 0 时使用 DefaultModelCatalogTTL
+func NewModelCatalog(curated []ModelInfo, fetch ModelFetchFunc, ttl time.Duration) *ModelCatalog {
+	if ttl <= 0 {
+		ttl = DefaultModelCatalogTTL
+	}
+	return &ModelCatalog{curated: curated, fetch: fetch, ttl: ttl}
+}
+
+// Models 返回最近一次合并的目录，未拉取过时返回内置列表
+func (c *ModelCatalog) Models() []ModelInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	src := c.models
+	if src == nil {
+		src = c.curated
+	}
+	models := make([]ModelInfo, len(src))
+	copy(models, src)
+	return models
+}
+
+// List 返回模型目录，缓存过期时从远端刷新
+// 拉取失败时返回错误，已有缓存保持不变
+func (c *ModelCatalog) List(ctx context.Context) ([]ModelInfo, error) {
+	c.mu.RLock()
+	fresh := c.models != nil && time.Since(c.fetchedAt) < c.ttl
+	c.mu.RUnlock()
+	if fresh {
+		return c.Models(), nil
+	}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c.Models(), nil
+}
+
+// Refresh 立即从远端拉取并合并模型目录
+func (c *ModelCatalog) Refresh(ctx context.Context) error {
+	remote, err := c.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	merged := MergeModels(remote, c.curated)
+
+	c.mu.Lock()
+	c.models = merged
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// Invalidate 使缓存过期，下次 List 时重新拉取（Models 仍返回旧目录）
+func (c *ModelCatalog) Invalidate() {
+	c.mu.Lock()
+	c.fetchedAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// MergeModels 以远端列表为准，用内置元数据补全模型信息
+//
+// 匹配规则：先按 ID 精确匹配，其次按最长前缀匹配
+// （"gpt-4o-2024-08-06" 沿用 "gpt-4o"，"llama3.1:8b" 沿用 "llama3.1" 的元数据）。
+// 远端已提供的非零字段（名称、描述、上下文长度）优先保留；
+// 无内置元数据的模型按名称推断特性。
+func MergeModels(remote, curated []ModelInfo) []ModelInfo {
+	merged := make([]ModelInfo, 0, len(remote))
+	for _, m := range remote {
+		base, ok := lookupCurated(curated, m.ID)
+		if !ok {
+			base = ModelInfo{Features: InferModelFeatures(m.ID)}
+		}
+		base.ID = m.ID
+		if m.Name != "" {
+			base.Name = m.Name
+		}
+		if base.Name == "" {
+			base.Name = m.ID
+		}
+		if m.Description != "" {
+			base.Description = m.Description
+		}
+		if m.MaxTokens > 0 {
+			base.MaxTokens = m.MaxTokens
+		}
+		if m.InputCost > 0 || m.OutputCost > 0 {
+			base.InputCost, base.OutputCost = m.InputCost, m.OutputCost
+		}
+		if len(m.Features) > 0 {
+			base.Features = m.Features
+		}
+		merged = append(merged, base)
+	}
+	return merged
+}
+
+// lookupCurated 查找模型的内置元数据
+func lookupCurated(curated []ModelInfo, id string) (ModelInfo, bool) {
+	best := -1
+	for i, m := range curated {
+		if m.ID == id {
+			return m, true
+		}
+		prefixed := strings.HasPrefix(id, m.ID+"-") || strings.HasPrefix(id, m.ID+":")
+		if prefixed && (best < 0 || len(m.ID) > len(curated[best].ID)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ModelInfo{}, false
+	}
+	m := curated[best]
+	m.Description = ""
+	return m, true
+}
+
+// InferModelFeatures 按模型名推断特性（用于缺少元数据的新模型）
+func InferModelFeatures(id string) []string {
+	lower := strings.ToLower(id)
+	if strings.Contains(lower, "embed") {
+		return []string{FeatureEmbedding}
+	}
+	features := []string{FeatureStreaming}
+	for _, kw := range []string{"vision", "-vl", "4o", "gemini", "claude"} {
+		if strings.Contains(lower, kw) {
+			features = append(features, FeatureVision)
+			break
+		}
+	}
+	reasoning := strings.HasPrefix(lower, "o1") || strings.HasPrefix(lower, "o3") || strings.HasPrefix(lower, "o4")
+	for _, kw := range []string{"reason", "think", "-r1", "qwq"} {
+		reasoning = reasoning || strings.Contains(lower, kw)
+	}
+	if reasoning {
+		features = append(features, FeatureReasoning)
+	}
+	return features
+}
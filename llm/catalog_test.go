@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMergeModels(t *testing.T) {
+	curated := []ModelInfo{
+		{ID: "gpt-4o", Name: "GPT-4o", Description: "flagship", MaxTokens: 128000, InputCost: 2.5, Features: []string{FeatureVision}},
+		{ID: "gpt-4o-mini", Name: "GPT-4o Mini", MaxTokens: 128000, InputCost: 0.15},
+		{ID: "llama3.1", Name: "Llama 3.1", MaxTokens: 131072},
+	}
+	merged := MergeModels([]ModelInfo{
+		{ID: "gpt-4o"},
+		{ID: "gpt-4o-mini-2024-07-18"},
+		{ID: "llama3.1:8b", MaxTokens: 8192},
+		{ID: "brand-new-reasoner"},
+	}, curated)
+
+	if len(merged) != 4 {
+		t.Fatalf("merged = %+v", merged)
+	}
+	if m := merged[0]; m.Description != "flagship" || m.InputCost != 2.5 {
+		t.Errorf("exact match = %+v", m)
+	}
+	if m := merged[1]; m.InputCost != 0.15 || m.Name != "GPT-4o Mini" || m.Description != "" {
+		t.Errorf("longest prefix match = %+v", m)
+	}
+	if m := merged[2]; m.MaxTokens != 8192 || m.Name != "Llama 3.1" {
+		t.Errorf("tag match = %+v", m)
+	}
+	if m := merged[3]; m.Name != m.ID || !slices.Contains(m.Features, FeatureReasoning) {
+		t.Errorf("inferred = %+v", m)
+	}
+}
+
+func TestModelCatalog(t *testing.T) {
+	calls := 0
+	var fail bool
+	c := NewModelCatalog([]ModelInfo{{ID: "a"}}, func(ctx context.Context) ([]ModelInfo, error) {
+		calls++
+		if fail {
+			return nil, errors.New("down")
+		}
+		return []ModelInfo{{ID: "a"}, {ID: "b"}}, nil
+	}, time.Hour)
+
+	if got := c.Models(); len(got) != 1 {
+		t.Errorf("before fetch = %+v", got)
+	}
+	if got, err := c.List(context.Background()); err != nil || len(got) != 2 {
+		t.Fatalf("List = %+v, %v", got, err)
+	}
+	c.List(context.Background())
+	if calls != 1 {
+		t.Errorf("fetch calls = %d, want cached", calls)
+	}
+
+	fail = true
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Error("Refresh should return fetch error")
+	}
+	if got := c.Models(); len(got) != 2 {
+		t.Errorf("failed refresh should keep cache, got %+v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// CompleteChoices 通过并发请求模拟 n>1 的多选项生成
+//
+// 用于不支持原生 n 参数的 Provider：发起 req.N 个 N=1 的并发请求，
+// 将各自的首个选项按顺序合并为 Choices，Usage 为所有请求之和。
+// 任一请求失败则取消其余请求并返回该错误。
+// req.N <= 1 时等价于直接调用 p.Complete。
+func CompleteChoices(ctx context.Context, p Provider, req CompletionRequest) (*CompletionResponse, error) {
+	n := req.N
+	if n <= 1 {
+		return p.Complete(ctx, req)
+	}
+	req.N = 1
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		errOnce   sync.Once
+		firstErr  error
+		responses = make([]*CompletionResponse, n)
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := p.Complete(ctx, req)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = resp
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	merged := &CompletionResponse{
+		ID:      responses[0].ID,
+		Model:   responses[0].Model,
+		Created: responses[0].Created,
+	}
+	choices := make([]Choice, n)
+	for i, resp := range responses {
+		choices[i] = resp.firstChoice()
+		choices[i].Index = i
+		merged.Usage.Add(resp.Usage)
+	}
+	merged.SetChoices(choices)
+	return merged, nil
+}
+
+// SetChoices 设置全部选项，并将 Choices[0] 同步到顶层字段
+func (r *CompletionResponse) SetChoices(choices []Choice) {
+	r.Choices = choices
+	if len(choices) == 0 {
+		return
+	}
+	first := choices[0]
+	r.Content = first.Content
+	r.Reasoning = first.Reasoning
+	r.ToolCalls = first.ToolCalls
+	r.FinishReason = first.FinishReason
+	r.Logprobs = first.Logprobs
+}
+
+// StripReasoning 清除响应及各选项中的推理内容
+func (r *CompletionResponse) StripReasoning() {
+	r.Reasoning = ""
+	for i := range r.Choices {
+		r.Choices[i].Reasoning = ""
+	}
+}
+
+// firstChoice 返回首个选项，未填充 Choices 时由顶层字段构造
+func (r *CompletionResponse) firstChoice() Choice {
+	if len(r.Choices) > 0 {
+		return r.Choices[0]
+	}
+	return Choice{
+		Content:      r.Content,
+		Reasoning:    r.Reasoning,
+		ToolCalls:    r.ToolCalls,
+		FinishReason: r.FinishReason,
+		Logprobs:     r.Logprobs,
+	}
+}
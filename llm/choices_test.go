@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCompleteChoices_EmulatesN(t *testing.T) {
+	mock := &mockProvider{
+		name: "test",
+		completeResp: &CompletionResponse{
+			ID:           "resp-1",
+			Content:      "hello",
+			FinishReason: "stop",
+			Usage:        Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		},
+	}
+
+	resp, err := CompleteChoices(context.Background(), mock, CompletionRequest{N: 3})
+	if err != nil {
+		t.Fatalf("CompleteChoices error: %v", err)
+	}
+	if got := mock.callCount.Load(); got != 3 {
+		t.Errorf("call count = %d, want 3", got)
+	}
+	if len(resp.Choices) != 3 {
+		t.Fatalf("len(Choices) = %d, want 3", len(resp.Choices))
+	}
+	for i, c := range resp.Choices {
+		if c.Index != i || c.Content != "hello" || c.FinishReason != "stop" {
+			t.Errorf("Choices[%d] = %+v", i, c)
+		}
+	}
+	if resp.Content != "hello" {
+		t.Errorf("Content = %q, want %q", resp.Content, "hello")
+	}
+	if resp.Usage.TotalTokens != 45 || resp.Usage.PromptTokens != 30 {
+		t.Errorf("usage should be summed, got %+v", resp.Usage)
+	}
+}
+
+func TestCompleteChoices_PropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	mock := &mockProvider{name: "test", completeErr: wantErr}
+
+	if _, err := CompleteChoices(context.Background(), mock, CompletionRequest{N: 2}); !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestUnsupportedError_Is(t *testing.T) {
+	var err error = &UnsupportedError{Provider: "anthropic", Feature: "logprobs"}
+	if !errors.Is(err, ErrUnsupported) {
+		t.Error("UnsupportedError should match ErrUnsupported")
+	}
+	if err.Error() != "anthropic: logprobs is not supported" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
@@ -0,0 +1,85 @@
+// Package compat 提供常见 OpenAI 兼容厂商的 Profile 与按厂商名构建 Provider 的注册表
+//
+// 每个厂商只是一份 openai.Profile 配置，新增厂商无需编写代码：
+//
+//	p, err := compat.New("moonshot", "", openai.WithModel("kimi-k2-0905-preview"))
+//
+// 也可以注册自定义厂商（如私有部署的 vLLM 集群）：
+//
+//	compat.Register(openai.Profile{
+//	    Name:         "my-vllm",
+//	    BaseURL:      "http://10.0.0.8:8000/v1",
+//	    DefaultModel: "Qwen/Qwen3-32B",
+//	    Reasoning:    compat.ChatTemplateThinking,
+//	})
+package compat
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
+)
+
+var (
+	mu       sync.RWMutex
+	profiles = make(map[string]openai.Profile)
+)
+
+func init() {
+	for _, p := range []openai.Profile{moonshot, zhipu, minimax, siliconflow, vllm, llamacpp} {
+		Register(p)
+	}
+}
+
+// Register 注册（或覆盖）厂商 Profile
+// 同时以厂商名注册 llm.ProviderFactory，可通过 llm.NewProvider 或配置文件构建
+func Register(profile openai.Profile) {
+	mu.Lock()
+	profiles[profile.Name] = profile
+	mu.Unlock()
+
+	llm.RegisterProvider(profile.Name, func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		return openai.NewCompatible(profile, cfg.APIKey, openai.ConfigOptions(cfg)...), nil
+	})
+}
+
+// Profile 返回已注册的厂商 Profile
+func Profile(vendor string) (openai.Profile, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := profiles[vendor]
+	return p, ok
+}
+
+// Vendors 返回已注册的厂商名（按字母序）
+func Vendors() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 按厂商名创建 OpenAI 兼容 Provider
+// apiKey 可以为空，会从该厂商 Profile 的 APIKeyEnv 读取
+func New(vendor, apiKey string, opts ...openai.Option) (*openai.Provider, error) {
+	p, ok := Profile(vendor)
+	if !ok {
+		return nil, fmt.Errorf("compat: unknown vendor %q", vendor)
+	}
+	return openai.NewCompatible(p, apiKey, opts...), nil
+}
+
+// ChatTemplateThinking 使用 chat_template_kwargs.enable_thinking 开关（vLLM/SGLang/llama.cpp 部署的混合推理模型）
+func ChatTemplateThinking(payload map[string]any, cfg *llm.ReasoningConfig) {
+	if !cfg.Specified() {
+		return
+	}
+	payload["chat_template_kwargs"] = map[string]any{"enable_thinking": cfg.Enabled()}
+}
@@ -0,0 +1,152 @@
+package compat
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
+)
+
+const chatReply = `{"id":"c1","model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+
+// captureServer 返回记录最近一次请求体的测试服务
+func captureServer(t *testing.T, body *map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*body = nil
+		json.NewDecoder(r.Body).Decode(body)
+		io.WriteString(w, chatReply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVendors_Builtin(t *testing.T) {
+	vendors := Vendors()
+	for _, name := range []string{"moonshot", "zhipu", "minimax", "siliconflow", "vllm", "llamacpp"} {
+		if !slices.Contains(vendors, name) {
+			t.Errorf("vendor %q not registered: %v", name, vendors)
+		}
+		if !slices.Contains(llm.ProviderTypes(), name) {
+			t.Errorf("vendor %q missing from llm registry", name)
+		}
+	}
+	if _, err := New("no-such-vendor", "k"); err == nil {
+		t.Error("unknown vendor should fail")
+	}
+}
+
+func TestNew_LlamaCppQuirks(t *testing.T) {
+	var body map[string]any
+	srv := captureServer(t, &body)
+
+	p, err := New("llamacpp", "k", openai.WithBaseURL(srv.URL), openai.WithModel("local"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	penalty := 1.1
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{
+		Messages:          llm.NewMessages("", "hi"),
+		RepetitionPenalty: &penalty,
+		Reasoning:         &llm.ReasoningConfig{Effort: llm.ReasoningEffortNone},
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if _, ok := body["repetition_penalty"]; ok || body["repeat_penalty"] != 1.1 {
+		t.Errorf("repetition_penalty not renamed: %v", body)
+	}
+	kwargs, _ := body["chat_template_kwargs"].(map[string]any)
+	if enabled, ok := kwargs["enable_thinking"]; !ok || enabled != false {
+		t.Errorf("chat_template_kwargs = %v", body["chat_template_kwargs"])
+	}
+
+	// 未指定推理配置时不发送开关，由模型模板决定
+	if _, err := p.Complete(t.Context(), llm.CompletionRequest{Messages: llm.NewMessages("", "hi")}); err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if _, ok := body["chat_template_kwargs"]; ok {
+		t.Errorf("chat_template_kwargs should not be sent: %v", body["chat_template_kwargs"])
+	}
+}
+
+func TestNew_UnsupportedParams(t *testing.T) {
+	var body map[string]any
+	srv := captureServer(t, &body)
+
+	p, err := New("minimax", "k", openai.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	seed := 1
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{
+		Messages: llm.NewMessages("", "hi"),
+		Stop:     []string{"END"},
+		User:     "u1",
+		Seed:     &seed,
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if body["model"] != "MiniMax-M1" {
+		t.Errorf("model = %v", body["model"])
+	}
+	for _, key := range []string{"stop", "user", "seed"} {
+		if _, ok := body[key]; ok {
+			t.Errorf("unsupported %s should be dropped", key)
+		}
+	}
+
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{Messages: llm.NewMessages("", "hi"), Logprobs: true})
+	var unsupported *llm.UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("logprobs err = %v, want *llm.UnsupportedError", err)
+	}
+}
+
+func TestRegister_NewProvider(t *testing.T) {
+	var body map[string]any
+	srv := captureServer(t, &body)
+
+	Register(openai.Profile{
+		Name:         "test-vllm",
+		BaseURL:      "http://unused.invalid/v1",
+		DefaultModel: "Qwen/Qwen3-32B",
+		Unsupported:  []string{"top_k"},
+		Reasoning:    ChatTemplateThinking,
+	})
+	if _, ok := Profile("test-vllm"); !ok {
+		t.Fatal("registered profile not found")
+	}
+
+	p, err := llm.NewProvider(llm.ProviderConfig{Type: "test-vllm", APIKey: "k", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewProvider error: %v", err)
+	}
+	if p.Name() != "test-vllm" {
+		t.Errorf("Name() = %q", p.Name())
+	}
+	topK := 20
+	_, err = p.Complete(t.Context(), llm.CompletionRequest{
+		Messages:  llm.NewMessages("", "hi"),
+		TopK:      &topK,
+		Reasoning: &llm.ReasoningConfig{Effort: llm.ReasoningEffortHigh},
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if body["model"] != "Qwen/Qwen3-32B" {
+		t.Errorf("model = %v", body["model"])
+	}
+	if _, ok := body["top_k"]; ok {
+		t.Error("unsupported top_k should be dropped")
+	}
+	if kwargs, _ := body["chat_template_kwargs"].(map[string]any); kwargs["enable_thinking"] != true {
+		t.Errorf("chat_template_kwargs = %v", body["chat_template_kwargs"])
+	}
+}
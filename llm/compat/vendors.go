@@ -0,0 +1,160 @@
+package compat
+
+import (
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
+)
+
+// 模型目录仅列出常用模型，价格随厂商调整频繁，未填写
+
+// moonshot 月之暗面 Kimi
+var moonshot = openai.Profile{
+	Name:          "moonshot",
+	BaseURL:       "https://api.moonshot.cn/v1",
+	DefaultModel:  "kimi-k2-0905-preview",
+	APIKeyEnv:     []string{"MOONSHOT_API_KEY"},
+	Unsupported:   []string{"logprobs", "seed", "top_k", "repetition_penalty"},
+	CharsPerToken: 2.5,
+	Models: []llm.ModelInfo{
+		{
+			ID:          "kimi-k2-0905-preview",
+			Name:        "Kimi K2",
+			Description: "Kimi K2 MoE 模型，擅长代码与 Agent 任务",
+			MaxTokens:   262144,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "kimi-thinking-preview",
+			Name:        "Kimi Thinking",
+			Description: "Kimi 深度思考模型",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureVision, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "moonshot-v1-128k",
+			Name:        "Moonshot v1 128K",
+			Description: "Moonshot 长上下文模型",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+	},
+}
+
+// zhipu 智谱 GLM
+var zhipu = openai.Profile{
+	Name:          "zhipu",
+	BaseURL:       "https://open.bigmodel.cn/api/paas/v4",
+	DefaultModel:  "glm-4.5",
+	APIKeyEnv:     []string{"ZHIPUAI_API_KEY", "ZHIPU_API_KEY"},
+	Unsupported:   []string{"n", "logprobs", "presence_penalty", "frequency_penalty", "seed", "top_k", "repetition_penalty", "parallel_tool_calls"},
+	Reasoning:     openai.ThinkingToggle,
+	CharsPerToken: 2.5,
+	Models: []llm.ModelInfo{
+		{
+			ID:          "glm-4.5",
+			Name:        "GLM-4.5",
+			Description: "智谱旗舰混合推理模型，支持开关思考模式",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "glm-4.5-air",
+			Name:        "GLM-4.5 Air",
+			Description: "智谱轻量混合推理模型",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "glm-4v-plus",
+			Name:        "GLM-4V Plus",
+			Description: "智谱视觉模型，支持图像理解",
+			MaxTokens:   8192,
+			Features:    []string{llm.FeatureVision, llm.FeatureStreaming},
+		},
+	},
+}
+
+// minimax MiniMax
+var minimax = openai.Profile{
+	Name:          "minimax",
+	BaseURL:       "https://api.minimax.chat/v1",
+	DefaultModel:  "MiniMax-M1",
+	APIKeyEnv:     []string{"MINIMAX_API_KEY"},
+	Unsupported:   []string{"n", "logprobs", "presence_penalty", "frequency_penalty", "seed", "stop", "user", "top_k", "repetition_penalty", "parallel_tool_calls"},
+	CharsPerToken: 2.5,
+	Models: []llm.ModelInfo{
+		{
+			ID:          "MiniMax-M1",
+			Name:        "MiniMax M1",
+			Description: "MiniMax 长上下文推理模型",
+			MaxTokens:   1000000,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "MiniMax-Text-01",
+			Name:        "MiniMax Text 01",
+			Description: "MiniMax 长上下文通用模型",
+			MaxTokens:   1000192,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+		},
+	},
+}
+
+// siliconflow 硅基流动（托管开源模型）
+var siliconflow = openai.Profile{
+	Name:          "siliconflow",
+	BaseURL:       "https://api.siliconflow.cn/v1",
+	DefaultModel:  "Qwen/Qwen3-32B",
+	RerankModel:   "BAAI/bge-reranker-v2-m3",
+	APIKeyEnv:     []string{"SILICONFLOW_API_KEY"},
+	Unsupported:   []string{"logprobs", "repetition_penalty"},
+	Reasoning:     openai.EnableThinking,
+	CharsPerToken: 2.5,
+	Models: []llm.ModelInfo{
+		{
+			ID:          "Qwen/Qwen3-32B",
+			Name:        "Qwen3 32B",
+			Description: "通义千问 Qwen3 混合推理模型",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "deepseek-ai/DeepSeek-V3",
+			Name:        "DeepSeek V3",
+			Description: "DeepSeek V3 通用模型",
+			MaxTokens:   65536,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "deepseek-ai/DeepSeek-R1",
+			Name:        "DeepSeek R1",
+			Description: "DeepSeek R1 推理模型",
+			MaxTokens:   65536,
+			Features:    []string{llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+	},
+}
+
+// vllm 自部署 vLLM OpenAI 兼容服务
+// 模型取决于部署，目录为空，需通过 openai.WithModel 指定；推理内容通过 reasoning 字段返回
+var vllm = openai.Profile{
+	Name:           "vllm",
+	BaseURL:        "http://localhost:8000/v1",
+	APIKeyEnv:      []string{"VLLM_API_KEY"},
+	ReasoningField: "reasoning",
+	Reasoning:      ChatTemplateThinking,
+	Constraints:    openai.GuidedDecoding,
+}
+
+// llamacpp 自部署 llama.cpp server（llama-server）OpenAI 兼容接口
+// 服务只加载一个模型，请求中的 model 仅作标识；支持 JSON Schema 与 GBNF 语法约束
+var llamacpp = openai.Profile{
+	Name:           "llamacpp",
+	BaseURL:        "http://localhost:8080/v1",
+	APIKeyEnv:      []string{"LLAMACPP_API_KEY"},
+	Unsupported:    []string{"n"},
+	RenameParams:   map[string]string{"repetition_penalty": "repeat_penalty"},
+	ReasoningField: "reasoning_content",
+	Reasoning:      ChatTemplateThinking,
+	Constraints:    openai.LlamaCppGrammar,
+}
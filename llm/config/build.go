@@ -0,0 +1,239 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/cache"
+	"github.com/hexagon-codes/ai-core/llm/router"
+)
+
+// SecretResolver 解析密钥引用中 "scheme:" 之后的部分
+type SecretResolver func(ref string) (string, error)
+
+// Option 是 Build 的配置选项
+type Option func(*builder)
+
+// WithSecretResolver 注册密钥引用方案（如 "vault"），可覆盖内置的 env、file
+func WithSecretResolver(scheme string, resolve SecretResolver) Option {
+	return func(b *builder) {
+		b.resolvers[scheme] = resolve
+	}
+}
+
+// Result Build 的结果
+type Result struct {
+	// Providers 按名称索引的 Provider（已应用中间件）
+	Providers map[string]llm.Provider
+
+	// Router 路由器，未配置时为 nil
+	Router *router.Router
+}
+
+// Default 返回默认入口：配置了路由器时返回路由器，仅有一个 Provider 时返回它，否则返回 nil
+func (r *Result) Default() llm.Provider {
+	if r.Router != nil {
+		return r.Router
+	}
+	if len(r.Providers) == 1 {
+		for _, p := range r.Providers {
+			return p
+		}
+	}
+	return nil
+}
+
+type builder struct {
+	resolvers map[string]SecretResolver
+}
+
+// Build 按配置构建 Provider、中间件链与路由器
+func Build(cfg *Config, opts ...Option) (*Result, error) {
+	b := &builder{resolvers: map[string]SecretResolver{
+		"env":  resolveEnv,
+		"file": resolveFile,
+	}}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("config: no providers configured")
+	}
+
+	res := &Result{Providers: make(map[string]llm.Provider, len(cfg.Providers))}
+	for name, spec := range cfg.Providers {
+		p, err := b.buildProvider(name, spec)
+		if err != nil {
+			return nil, fmt.Errorf("config: provider %q: %w", name, err)
+		}
+		res.Providers[name] = p
+	}
+
+	if cfg.Router != nil {
+		r, err := buildRouter(cfg.Router, res.Providers)
+		if err != nil {
+			return nil, fmt.Errorf("config: router: %w", err)
+		}
+		res.Router = r
+	}
+	return res, nil
+}
+
+// buildProvider 创建 Provider 并应用中间件
+func (b *builder) buildProvider(name string, spec ProviderSpec) (llm.Provider, error) {
+	typ := spec.Type
+	if typ == "" {
+		typ = name
+	}
+	apiKey, err := b.resolve(spec.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("api_key: %w", err)
+	}
+	var options map[string]string
+	if len(spec.Options) > 0 {
+		options = make(map[string]string, len(spec.Options))
+		for k, v := range spec.Options {
+			if options[k], err = b.resolve(v); err != nil {
+				return nil, fmt.Errorf("options.%s: %w", k, err)
+			}
+		}
+	}
+
+	p, err := llm.NewProvider(llm.ProviderConfig{
+		Type:    typ,
+		APIKey:  apiKey,
+		BaseURL: spec.BaseURL,
+		Model:   spec.Model,
+		Options: options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	middlewares := make([]llm.Middleware, 0, len(spec.Middleware))
+	for i, m := range spec.Middleware {
+		mw, err := buildMiddleware(m)
+		if err != nil {
+			return nil, fmt.Errorf("middleware[%d]: %w", i, err)
+		}
+		middlewares = append(middlewares, mw)
+	}
+	return llm.Chain(p, middlewares...), nil
+}
+
+// buildMiddleware 创建中间件
+func buildMiddleware(m MiddlewareSpec) (llm.Middleware, error) {
+	switch m.Type {
+	case "retry":
+		backoff := time.Duration(m.Backoff)
+		if backoff <= 0 {
+			backoff = time.Second
+		}
+		return llm.WithRetry(m.MaxRetries, backoff), nil
+	case "rate_limit":
+		if m.RPS <= 0 {
+			return nil, fmt.Errorf("rate_limit requires rps > 0")
+		}
+		return llm.WithRateLimit(m.RPS), nil
+	case "timeout":
+		if m.Timeout <= 0 {
+			return nil, fmt.Errorf("timeout requires timeout > 0")
+		}
+		return llm.WithTimeout(time.Duration(m.Timeout)), nil
+	case "cache":
+		var opts []cache.MemoryCacheOption
+		if m.MaxEntries > 0 {
+			opts = append(opts, cache.WithMaxEntries(m.MaxEntries))
+		}
+		if m.TTL > 0 {
+			opts = append(opts, cache.WithTTL(time.Duration(m.TTL)))
+		}
+		return llm.WithCache(cache.NewMemoryCache(opts...), nil), nil
+	default:
+		return nil, fmt.Errorf("unknown middleware type %q", m.Type)
+	}
+}
+
+// buildRouter 创建路由器并按顺序注册 Provider
+func buildRouter(spec *RouterSpec, providers map[string]llm.Provider) (*router.Router, error) {
+	names := spec.Providers
+	if len(names) == 0 {
+		for name := range providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var opts []router.Option
+	if spec.Strategy != "" {
+		strategy := router.Strategy(spec.Strategy)
+		switch strategy {
+		case router.StrategyRoundRobin, router.StrategyRandom, router.StrategyLeastLatency, router.StrategyLeastCost,
+			router.StrategyWeighted, router.StrategyFallback, router.StrategyModelMatch:
+		default:
+			return nil, fmt.Errorf("unknown strategy %q", spec.Strategy)
+		}
+		opts = append(opts, router.WithStrategy(strategy))
+	}
+	if spec.Fallback != "" {
+		if _, ok := providers[spec.Fallback]; !ok {
+			return nil, fmt.Errorf("fallback provider %q not configured", spec.Fallback)
+		}
+		opts = append(opts, router.WithFallback(spec.Fallback))
+	}
+	if len(spec.Weights) > 0 {
+		opts = append(opts, router.WithWeights(spec.Weights))
+	}
+	if len(spec.ModelMap) > 0 {
+		opts = append(opts, router.WithModelMap(spec.ModelMap))
+	}
+	if spec.HealthCheck {
+		opts = append(opts, router.WithHealthCheck(true))
+	}
+
+	r := router.New(opts...)
+	for _, name := range names {
+		p, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("provider %q not configured", name)
+		}
+		r.Register(name, p)
+	}
+	return r, nil
+}
+
+// resolve 解析密钥引用，未匹配已注册方案的值原样返回
+func (b *builder) resolve(value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+	resolve, ok := b.resolvers[scheme]
+	if !ok {
+		return value, nil
+	}
+	return resolve(ref)
+}
+
+// resolveEnv 读取环境变量，未设置时报错
+func resolveEnv(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
+
+// resolveFile 读取文件内容（去除首尾空白），适用于 Docker/Kubernetes secret 挂载
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
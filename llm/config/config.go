@@ -0,0 +1,181 @@
+// Package config 从配置文件或环境变量构建 Provider、中间件链与路由器
+//
+// 运维可以在不重新编译的情况下切换厂商、模型和路由策略：
+//
+//	providers:
+//	  primary:
+//	    type: openai
+//	    api_key: env:OPENAI_API_KEY
+//	    model: gpt-4o-mini
+//	    middleware:
+//	      - type: retry
+//	        max_retries: 3
+//	        backoff: 500ms
+//	      - type: rate_limit
+//	        rps: 10
+//	  backup:
+//	    type: moonshot
+//	    api_key: file:/run/secrets/moonshot
+//	router:
+//	  strategy: fallback
+//	  providers: [primary, backup]
+//
+//	cfg, err := config.Load("llm.yaml")
+//	res, err := config.Build(cfg)
+//	provider := res.Default()
+//
+// 本包导入了所有内置 Provider，类型名即 llm.ProviderTypes() 的返回值；
+// 自定义 Provider 通过 llm.RegisterProvider 注册后同样可以在配置中引用。
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	// 注册内置 Provider
+	_ "github.com/hexagon-codes/ai-core/llm/anthropic"
+	_ "github.com/hexagon-codes/ai-core/llm/ark"
+	_ "github.com/hexagon-codes/ai-core/llm/azure"
+	_ "github.com/hexagon-codes/ai-core/llm/compat"
+	_ "github.com/hexagon-codes/ai-core/llm/deepseek"
+	_ "github.com/hexagon-codes/ai-core/llm/ernie"
+	_ "github.com/hexagon-codes/ai-core/llm/gemini"
+	_ "github.com/hexagon-codes/ai-core/llm/ollama"
+	_ "github.com/hexagon-codes/ai-core/llm/openai"
+	_ "github.com/hexagon-codes/ai-core/llm/qwen"
+)
+
+// Config 顶层配置
+type Config struct {
+	// Providers Provider 配置，键为 Provider 名称（路由与日志中使用）
+	Providers map[string]ProviderSpec `json:"providers" yaml:"providers"`
+
+	// Router 路由配置，为空时不创建路由器
+	Router *RouterSpec `json:"router,omitempty" yaml:"router,omitempty"`
+}
+
+// ProviderSpec 单个 Provider 的配置
+type ProviderSpec struct {
+	// Type Provider 类型，为空时使用配置键名
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// APIKey API 密钥，支持密钥引用（如 "env:OPENAI_API_KEY"、"file:/run/secrets/key"）
+	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+
+	// BaseURL API 基础 URL
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+
+	// Model 默认模型
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// Options Provider 特有配置，值同样支持密钥引用
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+
+	// Middleware 中间件链，按顺序由外到内应用（同 llm.Chain）
+	Middleware []MiddlewareSpec `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+}
+
+// MiddlewareSpec 中间件配置
+//
+// 支持的类型及参数：
+//   - retry: max_retries、backoff
+//   - rate_limit: rps
+//   - timeout: timeout
+//   - cache: max_entries、ttl（内存 LRU 缓存）
+type MiddlewareSpec struct {
+	Type       string   `json:"type" yaml:"type"`
+	MaxRetries int      `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	Backoff    Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	RPS        float64  `json:"rps,omitempty" yaml:"rps,omitempty"`
+	Timeout    Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxEntries int      `json:"max_entries,omitempty" yaml:"max_entries,omitempty"`
+	TTL        Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// RouterSpec 路由器配置
+type RouterSpec struct {
+	// Strategy 路由策略（router.Strategy 的取值，如 "fallback"、"weighted"）
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// Providers 参与路由的 Provider 名称，按顺序注册；为空时使用全部（按名称排序）
+	Providers []string `json:"providers,omitempty" yaml:"providers,omitempty"`
+
+	// Fallback 降级 Provider 名称
+	Fallback string `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+
+	// Weights 加权策略的权重
+	Weights map[string]int `json:"weights,omitempty" yaml:"weights,omitempty"`
+
+	// ModelMap 模型到 Provider 名称的映射
+	ModelMap map[string]string `json:"model_map,omitempty" yaml:"model_map,omitempty"`
+
+	// HealthCheck 是否启用健康检查
+	HealthCheck bool `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+}
+
+// Duration 支持 "500ms"、"30s" 形式的时长，JSON 中也可以是纳秒数
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch val := v.(type) {
+	case float64:
+		*d = Duration(val)
+		return nil
+	case string:
+		return d.parse(val)
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+}
+
+// UnmarshalYAML 实现 yaml.Unmarshaler 接口
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.parse(node.Value)
+}
+
+func (d *Duration) parse(s string) error {
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(dur)
+	return nil
+}
+
+// Load 读取配置文件，按扩展名识别格式（.yaml/.yml/.json）
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Parse 解析配置内容，format 为 "yaml"、"yml" 或 "json"
+func Parse(data []byte, format string) (*Config, error) {
+	var cfg Config
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse yaml config: %w", err)
+		}
+	case "json":
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse json config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestLoadAndBuild_YAML(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "moonshot.key")
+	os.WriteFile(secret, []byte("sk-moon\n"), 0o600)
+	t.Setenv("TEST_OPENAI_KEY", "sk-openai")
+
+	path := filepath.Join(dir, "llm.yaml")
+	os.WriteFile(path, []byte(`
+providers:
+  primary:
+    type: openai
+    api_key: env:TEST_OPENAI_KEY
+    model: gpt-4o-mini
+    middleware:
+      - type: retry
+        max_retries: 2
+        backoff: 200ms
+      - type: timeout
+        timeout: 30s
+  moonshot:
+    api_key: file:`+secret+`
+router:
+  strategy: fallback
+  providers: [primary, moonshot]
+  fallback: moonshot
+`), 0o600)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	mw := cfg.Providers["primary"].Middleware
+	if len(mw) != 2 || time.Duration(mw[0].Backoff) != 200*time.Millisecond || time.Duration(mw[1].Timeout) != 30*time.Second {
+		t.Errorf("middleware = %+v", mw)
+	}
+
+	res, err := Build(cfg)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if res.Providers["primary"].Name() != "openai" || res.Providers["moonshot"].Name() != "moonshot" {
+		t.Errorf("providers = %v", res.Providers)
+	}
+	if res.Router == nil || res.Default() != llm.Provider(res.Router) {
+		t.Error("router should be the default entry")
+	}
+}
+
+func TestBuild_JSONAndErrors(t *testing.T) {
+	cfg, err := Parse([]byte(`{"providers":{"deepseek":{"api_key":"sk-x","middleware":[{"type":"cache","ttl":"1m"}]}}}`), "json")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	res, err := Build(cfg)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if p := res.Default(); p == nil || p.Name() != "deepseek" {
+		t.Errorf("Default = %v", p)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"unknown type", Config{Providers: map[string]ProviderSpec{"x": {Type: "nope"}}}, "unknown provider type"},
+		{"missing env", Config{Providers: map[string]ProviderSpec{"openai": {APIKey: "env:TEST_UNSET_KEY_XYZ"}}}, "TEST_UNSET_KEY_XYZ"},
+		{"bad middleware", Config{Providers: map[string]ProviderSpec{"openai": {APIKey: "k", Middleware: []MiddlewareSpec{{Type: "rate_limit"}}}}}, "rps"},
+		{"bad strategy", Config{Providers: map[string]ProviderSpec{"openai": {APIKey: "k"}}, Router: &RouterSpec{Strategy: "best"}}, "unknown strategy"},
+		{"unknown router provider", Config{Providers: map[string]ProviderSpec{"openai": {APIKey: "k"}}, Router: &RouterSpec{Providers: []string{"qwen"}}}, "not configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(&tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_CustomSecretResolver(t *testing.T) {
+	cfg := &Config{Providers: map[string]ProviderSpec{"openai": {APIKey: "vault:llm/openai"}}}
+	var got string
+	_, err := Build(cfg, WithSecretResolver("vault", func(ref string) (string, error) {
+		got = ref
+		return "sk-vault", nil
+	}))
+	if err != nil || got != "llm/openai" {
+		t.Errorf("resolver ref = %q, err = %v", got, err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LLM_PROVIDERS", "main, ark-prod")
+	t.Setenv("LLM_MAIN_TYPE", "qwen")
+	t.Setenv("LLM_MAIN_API_KEY", "sk-q")
+	t.Setenv("LLM_MAIN_RETRY", "3")
+	t.Setenv("LLM_ARK_PROD_TYPE", "ark")
+	t.Setenv("LLM_ARK_PROD_OPTIONS", "endpoint_id=ep-1")
+	t.Setenv("LLM_ARK_PROD_TIMEOUT", "20s")
+	t.Setenv("LLM_ROUTER_STRATEGY", "fallback")
+	t.Setenv("LLM_ROUTER_FALLBACK", "ark-prod")
+
+	cfg, err := FromEnv("llm")
+	if err != nil {
+		t.Fatalf("FromEnv error: %v", err)
+	}
+	ark := cfg.Providers["ark-prod"]
+	if ark.Type != "ark" || ark.Options["endpoint_id"] != "ep-1" || len(ark.Middleware) != 1 {
+		t.Errorf("ark spec = %+v", ark)
+	}
+	if m := cfg.Providers["main"].Middleware; len(m) != 1 || m[0].MaxRetries != 3 {
+		t.Errorf("main middleware = %+v", m)
+	}
+	if cfg.Router == nil || cfg.Router.Fallback != "ark-prod" || len(cfg.Router.Providers) != 2 {
+		t.Errorf("router = %+v", cfg.Router)
+	}
+
+	res, err := Build(cfg)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if res.Providers["main"].Name() != "qwen" {
+		t.Errorf("main = %s", res.Providers["main"].Name())
+	}
+}
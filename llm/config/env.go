@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromEnv 从环境变量构建配置
+//
+// 以 prefix="LLM" 为例：
+//
+//	LLM_PROVIDERS=primary,backup          # Provider 名称列表
+//	LLM_PRIMARY_TYPE=openai               # 类型，默认为名称
+//	LLM_PRIMARY_API_KEY=env:OPENAI_API_KEY
+//	LLM_PRIMARY_BASE_URL=...
+//	LLM_PRIMARY_MODEL=gpt-4o-mini
+//	LLM_PRIMARY_OPTIONS=endpoint_id=ep-xxx,region=cn
+//	LLM_PRIMARY_RETRY=3                   # 重试次数（退避 1s）
+//	LLM_PRIMARY_RATE_LIMIT=10             # 每秒请求数
+//	LLM_PRIMARY_TIMEOUT=30s
+//	LLM_ROUTER_STRATEGY=fallback          # 设置后创建路由器
+//	LLM_ROUTER_FALLBACK=backup
+//
+// 名称中的 "-" 在变量名中写作 "_"，字母大写。
+func FromEnv(prefix string) (*Config, error) {
+	get := func(parts ...string) string {
+		return os.Getenv(envKey(prefix, parts...))
+	}
+
+	names := splitList(get("PROVIDERS"))
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%s is not set", envKey(prefix, "PROVIDERS"))
+	}
+
+	cfg := &Config{Providers: make(map[string]ProviderSpec, len(names))}
+	for _, name := range names {
+		spec := ProviderSpec{
+			Type:    get(name, "TYPE"),
+			APIKey:  get(name, "API_KEY"),
+			BaseURL: get(name, "BASE_URL"),
+			Model:   get(name, "MODEL"),
+		}
+		for _, pair := range splitList(get(name, "OPTIONS")) {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid option %q, want key=value", envKey(prefix, name, "OPTIONS"), pair)
+			}
+			if spec.Options == nil {
+				spec.Options = make(map[string]string)
+			}
+			spec.Options[k] = v
+		}
+
+		if v := get(name, "RETRY"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", envKey(prefix, name, "RETRY"), err)
+			}
+			spec.Middleware = append(spec.Middleware, MiddlewareSpec{Type: "retry", MaxRetries: n, Backoff: Duration(time.Second)})
+		}
+		if v := get(name, "RATE_LIMIT"); v != "" {
+			rps, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", envKey(prefix, name, "RATE_LIMIT"), err)
+			}
+			spec.Middleware = append(spec.Middleware, MiddlewareSpec{Type: "rate_limit", RPS: rps})
+		}
+		if v := get(name, "TIMEOUT"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", envKey(prefix, name, "TIMEOUT"), err)
+			}
+			spec.Middleware = append(spec.Middleware, MiddlewareSpec{Type: "timeout", Timeout: Duration(d)})
+		}
+		cfg.Providers[name] = spec
+	}
+
+	if strategy := get("ROUTER", "STRATEGY"); strategy != "" {
+		cfg.Router = &RouterSpec{
+			Strategy:  strategy,
+			Providers: names,
+			Fallback:  get("ROUTER", "FALLBACK"),
+		}
+	}
+	return cfg, nil
+}
+
+// envKey 拼接环境变量名
+func envKey(prefix string, parts ...string) string {
+	key := strings.Join(append([]string{prefix}, parts...), "_")
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// splitList 按逗号切分并去除空白项
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -2,64 +2,216 @@
 package deepseek
 
 import (
-	"os"
+	"context"
+	"net/http"
+	"strings"
 
 	"github.com/hexagon-codes/ai-core/llm"
 	"github.com/hexagon-codes/ai-core/llm/openai"
+	"github.com/hexagon-codes/ai-core/streamx"
 )
 
 const (
 	defaultBaseURL = "https://api.deepseek.com/v1"
 	defaultModel   = "deepseek-chat"
+	reasonerModel  = "deepseek-reasoner"
 )
 
 // Provider 实现 DeepSeek LLM 提供者
-// DeepSeek 使用 OpenAI 兼容的 API，所以复用 OpenAI Provider
+// DeepSeek 使用 OpenAI 兼容的 API，所以复用按 DeepSeek Profile 配置的 OpenAI Provider
+//
+// 在兼容实现之上处理 DeepSeek 特有的行为：
+//   - 最后一条消息为 assistant 时自动走 beta 端点的对话前缀续写（prefix: true）
+//   - 历史 assistant 消息中的推理内容总是剥离（API 收到 reasoning_content 会返回 400）
+//   - deepseek-reasoner 不支持的参数在发送前返回 *llm.UnsupportedError
 type Provider struct {
 	*openai.Provider
+
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	beta       *openai.Provider // beta 端点（对话前缀续写），New 中创建
 }
 
 // Option 是 Provider 的配置选项
 type Option func(*Provider)
 
+// WithBaseURL 设置 API 基础 URL
+// beta 端点由其推导：末尾的 /v1 替换为 /beta
+func WithBaseURL(url string) Option {
+	return func(p *Provider) {
+		p.baseURL = url
+	}
+}
+
 // WithModel 设置默认模型
 func WithModel(model string) Option {
 	return func(p *Provider) {
-		// 通过重新创建 Provider 来设置模型
-		// 这是因为 openai.Provider 的 model 字段是私有的
+		p.model = model
+	}
+}
+
+// WithHTTPClient 设置 HTTP 客户端
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.httpClient = client
 	}
 }
 
 // New 创建 DeepSeek Provider
 // apiKey 可以为空，会从环境变量 DEEPSEEK_API_KEY 读取
 func New(apiKey string, opts ...Option) *Provider {
-	if apiKey == "" {
-		apiKey = os.Getenv("DEEPSEEK_API_KEY")
-	}
-
 	p := &Provider{
-		Provider: openai.New(
-			apiKey,
-			openai.WithBaseURL(defaultBaseURL),
-			openai.WithModel(defaultModel),
-		),
+		apiKey:  apiKey,
+		baseURL: defaultBaseURL,
+		model:   defaultModel,
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	compatOpts := []openai.Option{openai.WithBaseURL(p.baseURL), openai.WithModel(p.model)}
+	if p.httpClient != nil {
+		compatOpts = append(compatOpts, openai.WithHTTPClient(p.httpClient))
+	}
+	p.Provider = openai.NewCompatible(profile, p.apiKey, compatOpts...)
+	p.beta = openai.NewCompatible(betaProfile(), p.apiKey,
+		append(compatOpts, openai.WithBaseURL(betaURL(p.baseURL)))...)
+
 	return p
 }
 
-// Name 返回提供者名称
-func (p *Provider) Name() string {
-	return "deepseek"
+// Complete 执行非流式补全请求
+func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	req, err := p.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	if isPrefixCompletion(req) {
+		return p.beta.Complete(ctx, req)
+	}
+	return p.Provider.Complete(ctx, req)
+}
+
+// Stream 执行流式补全请求
+func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	req, err := p.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	if isPrefixCompletion(req) {
+		return p.beta.Stream(ctx, req)
+	}
+	return p.Provider.Stream(ctx, req)
+}
+
+// CompleteText 执行 FIM 补全（beta），deepseek-reasoner 不支持
+func (p *Provider) CompleteText(ctx context.Context, req llm.TextCompletionRequest) (*llm.TextCompletionResponse, error) {
+	if err := p.checkText(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.CompleteText(ctx, req)
+}
+
+// StreamText 执行流式 FIM 补全（beta），deepseek-reasoner 不支持
+func (p *Provider) StreamText(ctx context.Context, req llm.TextCompletionRequest) (*streamx.Stream, error) {
+	if err := p.checkText(req); err != nil {
+		return nil, err
+	}
+	return p.Provider.StreamText(ctx, req)
+}
+
+// prepare 填充默认模型、强制剥离历史推理内容并检查模型不支持的参数
+func (p *Provider) prepare(req llm.CompletionRequest) (llm.CompletionRequest, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+	req.ReasoningHistory = llm.ReasoningStrip
+	if isReasoner(req.Model) {
+		if err := checkReasoner(req); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+// checkReasoner 检查 deepseek-reasoner 不支持的参数
+// 官方 API 对 temperature 等采样参数静默忽略，此处显式报错以免调用方误以为生效
+func checkReasoner(req llm.CompletionRequest) error {
+	unsupported := func(feature string) error {
+		return &llm.UnsupportedError{Provider: "deepseek", Feature: feature + " with " + reasonerModel}
+	}
+	switch {
+	case len(req.Tools) > 0 || req.ToolChoice != nil:
+		return unsupported("function calling")
+	case req.WantsLogprobs():
+		return unsupported("logprobs")
+	case req.ResponseFormat != nil && req.ResponseFormat.Type != "" && req.ResponseFormat.Type != "text":
+		return unsupported("JSON output")
+	case req.Temperature != nil:
+		return unsupported("temperature")
+	case req.TopP != nil:
+		return unsupported("top_p")
+	case req.PresencePenalty != nil:
+		return unsupported("presence_penalty")
+	case req.FrequencyPenalty != nil:
+		return unsupported("frequency_penalty")
+	}
+	return nil
 }
 
-// Models 返回可用模型列表
-func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
+// checkText 检查 FIM 补全请求的模型
+func (p *Provider) checkText(req llm.TextCompletionRequest) error {
+	model := req.Model
+	if model == "" {
+		model = p.model
+	}
+	if isReasoner(model) {
+		return &llm.UnsupportedError{Provider: "deepseek", Feature: "FIM completion with " + reasonerModel}
+	}
+	return nil
+}
+
+// isReasoner 判断是否为推理模型
+func isReasoner(model string) bool {
+	return strings.HasPrefix(model, reasonerModel)
+}
+
+// isPrefixCompletion 判断是否为对话前缀续写（最后一条消息为 assistant）
+func isPrefixCompletion(req llm.CompletionRequest) bool {
+	n := len(req.Messages)
+	return n > 0 && req.Messages[n-1].Role == llm.RoleAssistant
+}
+
+// betaURL 由 API 基础 URL 推导 beta 端点
+func betaURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/v1") + "/beta"
+}
+
+// betaProfile beta 端点的 Profile：在 profile 基础上开启对话前缀续写
+func betaProfile() openai.Profile {
+	beta := profile
+	beta.PrefixCompletion = true
+	return beta
+}
+
+// profile DeepSeek 的差异：
+//   - 不支持 n 参数，n>1 时通过并发请求模拟，流式 n>1 不支持
+//   - 不支持 top_k、repetition_penalty
+//   - FIM 补全（/completions，支持 suffix）与对话前缀续写为 beta 功能，位于 /beta 路径下
+//   - 用量中的 prompt_cache_hit_tokens/prompt_cache_miss_tokens 映射为 CachedTokens/CacheMissTokens
+var profile = openai.Profile{
+	Name:         "deepseek",
+	BaseURL:      defaultBaseURL,
+	DefaultModel: defaultModel,
+	APIKeyEnv:    []string{"DEEPSEEK_API_KEY"},
+	Unsupported:  []string{"n", "top_k", "repetition_penalty"},
+	CompletionsURL: func(baseURL string) string {
+		return betaURL(baseURL) + "/completions"
+	},
+	Models: []llm.ModelInfo{
 		{
 			ID:          "deepseek-chat",
 			Name:        "DeepSeek Chat",
@@ -70,7 +222,7 @@ func (p *Provider) Models() []llm.ModelInfo {
 			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
 		},
 		{
-			ID:          "deepseek-reasoner",
+			ID:          reasonerModel,
 			Name:        "DeepSeek Reasoner",
 			Description: "Advanced reasoning model for complex tasks",
 			MaxTokens:   64000,
@@ -78,10 +230,11 @@ func (p *Provider) Models() []llm.ModelInfo {
 			OutputCost:  2.19,
 			Features:    []string{llm.FeatureStreaming},
 		},
-	}
+	},
 }
 
-// 确保实现了 Provider 和 EmbeddingProvider 接口
+// 确保实现了 Provider、EmbeddingProvider 和 CompletionTextProvider 接口
 // Embed/EmbedWithModel 方法继承自 openai.Provider
 var _ llm.Provider = (*Provider)(nil)
 var _ llm.EmbeddingProvider = (*Provider)(nil)
+var _ llm.CompletionTextProvider = (*Provider)(nil)
@@ -0,0 +1,105 @@
+package deepseek
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestComplete_PrefixCompletion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/beta/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body struct {
+			Model    string           `json:"model"`
+			Messages []map[string]any `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Model != "deepseek-chat" || len(body.Messages) != 3 {
+			t.Fatalf("unexpected body: %+v", body)
+		}
+		if _, ok := body.Messages[1]["reasoning_content"]; ok {
+			t.Errorf("history reasoning should be stripped: %v", body.Messages[1])
+		}
+		if last := body.Messages[2]; last["role"] != "assistant" || last["prefix"] != true {
+			t.Errorf("last message = %v, want assistant prefix", last)
+		}
+		io.WriteString(w, `{"id":"c1","model":"deepseek-chat","choices":[{"index":0,"message":{"role":"assistant","content":"print('hi')"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":30,"completion_tokens":5,"total_tokens":35,"prompt_cache_hit_tokens":24,"prompt_cache_miss_tokens":6}}`)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL+"/v1"), WithModel("deepseek-chat"))
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: "write hello world"},
+			{Role: llm.RoleAssistant, Content: "ok", Reasoning: "thinking..."},
+			{Role: llm.RoleAssistant, Content: "# hello.py\n"},
+		},
+		ReasoningHistory: llm.ReasoningPreserve,
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.Usage.CachedTokens != 24 || resp.Usage.CacheMissTokens != 6 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestComplete_ChatEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["model"] != "deepseek-reasoner" {
+			t.Errorf("model = %v, want WithModel value", body["model"])
+		}
+		io.WriteString(w, `{"id":"c2","choices":[{"index":0,"message":{"role":"assistant","content":"4","reasoning_content":"2+2"},"finish_reason":"stop"}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL+"/v1"), WithModel("deepseek-reasoner"))
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: "2+2?"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.Content != "4" || resp.Reasoning != "2+2" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestReasonerUnsupportedParams(t *testing.T) {
+	temp := 0.5
+	p := New("key")
+	tests := []struct {
+		name string
+		req  llm.CompletionRequest
+	}{
+		{"temperature", llm.CompletionRequest{Temperature: &temp}},
+		{"tools", llm.CompletionRequest{Tools: []llm.ToolDefinition{llm.NewToolDefinition("f", "", nil)}}},
+		{"json", llm.CompletionRequest{ResponseFormat: &llm.ResponseFormat{Type: "json_object"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Model = "deepseek-reasoner"
+			tt.req.Messages = []llm.Message{{Role: llm.RoleUser, Content: "hi"}}
+			if _, err := p.Complete(t.Context(), tt.req); !errors.Is(err, llm.ErrUnsupported) {
+				t.Errorf("Complete error = %v, want ErrUnsupported", err)
+			}
+		})
+	}
+
+	if _, err := p.CompleteText(t.Context(), llm.TextCompletionRequest{Model: "deepseek-reasoner", Prompt: "a"}); !errors.Is(err, llm.ErrUnsupported) {
+		t.Errorf("CompleteText error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// EmbedBatches 按单次上限分批执行嵌入请求并合并结果
+//
+// 供 Provider 实现 CreateEmbeddings 复用：maxBatch 为 Provider 单次请求的输入上限（<=0 表示不限），
+// req.BatchSize 更小时以其为准；call 处理一批输入，返回的 Embeddings 须与该批输入一一对应。
+// 各批依次执行，Usage 累加；req.Normalize 为 true 时对结果做 L2 归一化。
+func EmbedBatches(ctx context.Context, req EmbedRequest, maxBatch int, call func(ctx context.Context, input []string) (*EmbedResponse, error)) (*EmbedResponse, error) {
+	size := maxBatch
+	if req.BatchSize > 0 && (size <= 0 || req.BatchSize < size) {
+		size = req.BatchSize
+	}
+	if size <= 0 {
+		size = len(req.Input)
+	}
+
+	out := &EmbedResponse{
+		Model:      req.Model,
+		Embeddings: make([][]float32, 0, len(req.Input)),
+	}
+	for start := 0; start < len(req.Input); start += size {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(start+size, len(req.Input))
+		resp, err := call(ctx, req.Input[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("embedding: got %d vectors for %d inputs", len(resp.Embeddings), end-start)
+		}
+		if resp.Model != "" {
+			out.Model = resp.Model
+		}
+		out.Embeddings = append(out.Embeddings, resp.Embeddings...)
+		out.Usage.Add(resp.Usage)
+	}
+
+	if req.Normalize {
+		for _, vec := range out.Embeddings {
+			NormalizeL2(vec)
+		}
+	}
+	return out, nil
+}
+
+// NormalizeL2 将向量原地缩放为单位长度，零向量保持不变
+func NormalizeL2(vec []float32) {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	scale := 1 / math.Sqrt(sum)
+	for i, v := range vec {
+		vec[i] = float32(float64(v) * scale)
+	}
+}
+
+// DecodeBase64Embedding 解码 base64 编码的向量（小端序 float32 数组，OpenAI encoding_format=base64）
+func DecodeBase64Embedding(s string) ([]float32, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("embedding: invalid base64: %w", err)
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("embedding: base64 payload length %d is not a multiple of 4", len(data))
+	}
+	vec := make([]float32, len(data)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vec, nil
+}
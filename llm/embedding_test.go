@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestEmbedBatches(t *testing.T) {
+	var batches [][]string
+	req := EmbedRequest{Input: []string{"a", "b", "c", "d", "e"}, BatchSize: 3, Normalize: true}
+	resp, err := EmbedBatches(context.Background(), req, 10, func(_ context.Context, input []string) (*EmbedResponse, error) {
+		batches = append(batches, input)
+		out := &EmbedResponse{Model: "m", Usage: Usage{PromptTokens: len(input), TotalTokens: len(input)}}
+		for range input {
+			out.Embeddings = append(out.Embeddings, []float32{3, 4})
+		}
+		return out, nil
+	})
+	if err != nil {
+		t.Fatalf("EmbedBatches error: %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 2 {
+		t.Errorf("batches = %v", batches)
+	}
+	if len(resp.Embeddings) != 5 || resp.Model != "m" || resp.Usage.TotalTokens != 5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if v := resp.Embeddings[4]; math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Errorf("vector not normalized: %v", v)
+	}
+}
+
+func TestEmbedBatches_CountMismatch(t *testing.T) {
+	_, err := EmbedBatches(context.Background(), EmbedRequest{Input: []string{"a", "b"}}, 0, func(context.Context, []string) (*EmbedResponse, error) {
+		return &EmbedResponse{Embeddings: [][]float32{{1}}}, nil
+	})
+	if err == nil {
+		t.Error("expected error for mismatched vector count")
+	}
+}
+
+func TestDecodeBase64Embedding(t *testing.T) {
+	want := []float32{0.5, -1.25, 3}
+	buf := make([]byte, 4*len(want))
+	for i, v := range want {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	got, err := DecodeBase64Embedding(base64.StdEncoding.EncodeToString(buf))
+	if err != nil {
+		t.Fatalf("DecodeBase64Embedding error: %v", err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if _, err := DecodeBase64Embedding("AAA="); err == nil {
+		t.Error("expected error for truncated payload")
+	}
+}
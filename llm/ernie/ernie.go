@@ -1,10 +1,15 @@
 // Package ernie provides Baidu ERNIE (文心一言) LLM provider.
 //
-// Differences from OpenAI:
+// Two APIs are supported:
+//   - Qianfan v2 (NewV2): OpenAI-compatible, bearer API key; recommended
+//   - Legacy wenxinworkshop (New): OAuth access_token from API key + secret key
+//
+// Differences of the legacy API from OpenAI:
 //   - Auth: OAuth access_token (not API key in header)
 //   - Model: encoded in URL path (not request body)
 //   - Response: "result" field (not "choices[0].message.content")
 //   - System: separate "system" field (not in messages array)
+//   - Tools: "functions" / "function_call" (not "tools" / "tool_calls")
 package ernie
 
 import (
@@ -14,18 +19,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
 	"github.com/hexagon-codes/ai-core/streamx"
 	"github.com/hexagon-codes/toolkit/net/httpx"
 )
 
 const (
-	defaultBaseURL = "https://aip.baidubce.com/rpc/2.0/ai_custom/v1/wenxinworkshop"
-	tokenURL       = "https://aip.baidubce.com/oauth/2.0/token"
-	defaultModel   = "ernie-4.0-8k"
+	defaultBaseURL  = "https://aip.baidubce.com/rpc/2.0/ai_custom/v1/wenxinworkshop"
+	defaultTokenURL = "https://aip.baidubce.com/oauth/2.0/token"
+	defaultModel    = "ernie-4.0-8k"
 )
 
 // Provider 百度文心一言 Provider
@@ -33,8 +41,10 @@ type Provider struct {
 	apiKey      string
 	secretKey   string
 	baseURL     string
+	tokenURL    string
 	model       string
 	httpClient  *http.Client
+	chat        *openai.Provider // Qianfan v2 兼容实现，仅 NewV2 创建时非 nil
 	mu          sync.Mutex
 	accessToken string
 	tokenExpiry time.Time
@@ -48,6 +58,11 @@ func WithBaseURL(url string) Option {
 	return func(p *Provider) { p.baseURL = url }
 }
 
+// WithTokenURL 设置 access_token 接口地址（仅旧版 API）
+func WithTokenURL(url string) Option {
+	return func(p *Provider) { p.tokenURL = url }
+}
+
 // WithModel 设置默认模型
 func WithModel(model string) Option {
 	return func(p *Provider) { p.model = model }
@@ -58,12 +73,14 @@ func WithHTTPClient(client *http.Client) Option {
 	return func(p *Provider) { p.httpClient = client }
 }
 
-// New 创建 ERNIE Provider
+// New 创建旧版 API（wenxinworkshop）的 ERNIE Provider
+// 使用 API Key 与 Secret Key 换取 access_token；新接入建议使用 NewV2
 func New(apiKey, secretKey string, opts ...Option) *Provider {
 	p := &Provider{
 		apiKey:     apiKey,
 		secretKey:  secretKey,
 		baseURL:    defaultBaseURL,
+		tokenURL:   defaultTokenURL,
 		model:      defaultModel,
 		httpClient: httpx.RawClient(httpx.WithResponseHeaderTimeout(120 * time.Second)),
 	}
@@ -78,28 +95,27 @@ func (p *Provider) Name() string { return "ernie" }
 
 // Complete 非流式补全
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
-	token, err := p.ensureToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("ernie auth failed: %w", err)
+	if p.chat != nil {
+		return p.chat.Complete(ctx, req)
 	}
-
-	model := req.Model
-	if model == "" {
-		model = p.model
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if req.N > 1 {
+		// 不支持原生 n 参数，通过并发请求模拟
+		return llm.CompleteChoices(ctx, p, req)
 	}
 
-	body := p.buildRequest(req, false)
-	url := fmt.Sprintf("%s/chat/%s?access_token=%s", p.baseURL, model, token)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	body, err := p.buildRequest(req, false)
 	if err != nil {
 		return nil, err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := p.httpClient.Do(httpReq)
+	resp, err := p.send(ctx, p.resolveModel(req), body, false)
 	if err != nil {
-		return nil, fmt.Errorf("ernie request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -113,29 +129,39 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 
 // Stream 流式补全
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
-	token, err := p.ensureToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("ernie auth failed: %w", err)
+	if p.chat != nil {
+		return p.chat.Stream(ctx, req)
 	}
-
-	model := req.Model
-	if model == "" {
-		model = p.model
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if req.N > 1 {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
 	}
 
-	body := p.buildRequest(req, true)
-	url := fmt.Sprintf("%s/chat/%s?access_token=%s", p.baseURL, model, token)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	body, err := p.buildRequest(req, true)
 	if err != nil {
 		return nil, err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "text/event-stream")
-
-	resp, err := p.httpClient.Do(httpReq)
+	resp, err := p.send(ctx, p.resolveModel(req), body, true)
 	if err != nil {
-		return nil, fmt.Errorf("ernie stream failed: %w", err)
+		return nil, err
+	}
+
+	// 出错时服务端返回 JSON 而非 SSE
+	if isJSONResponse(resp) {
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("ernie read response failed: %w", err)
+		}
+		if _, err := p.parseResponse(data); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ernie stream failed: unexpected response: %s", data)
 	}
 
 	return streamx.NewStreamWithParser(resp.Body, &ernieStreamParser{}), nil
@@ -143,6 +169,9 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 
 // Models 返回支持的模型列表
 func (p *Provider) Models() []llm.ModelInfo {
+	if p.chat != nil {
+		return p.chat.Models()
+	}
 	return []llm.ModelInfo{
 		{ID: "ernie-4.5-8k", Name: "ERNIE 4.5", Description: "百度最新旗舰模型"},
 		{ID: "ernie-4.0-8k", Name: "ERNIE 4.0", Description: "强大的中文理解能力"},
@@ -162,7 +191,73 @@ func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
 
 // ============== Internal ==============
 
+// resolveModel 返回请求使用的模型
+func (p *Provider) resolveModel(req llm.CompletionRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return p.model
+}
+
+// send 发送对话请求
+// access_token 失效（错误码 110/111）时刷新后重试一次；
+// 返回 JSON 的响应体已被读取并替换，调用方可照常读取
+func (p *Provider) send(ctx context.Context, model string, body []byte, stream bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := p.ensureToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ernie auth failed: %w", err)
+		}
+
+		endpoint := fmt.Sprintf("%s/chat/%s?access_token=%s", p.baseURL, model, token)
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if stream {
+			httpReq.Header.Set("Accept", "text/event-stream")
+		}
+
+		resp, err := p.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("ernie request failed: %w", err)
+		}
+		if stream && !isJSONResponse(resp) {
+			return resp, nil
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("ernie read response failed: %w", err)
+		}
+		var e struct {
+			ErrorCode int `json:"error_code"`
+		}
+		json.Unmarshal(data, &e)
+		if (e.ErrorCode == errCodeTokenInvalid || e.ErrorCode == errCodeTokenExpired) && attempt == 0 {
+			p.invalidateToken(token)
+			continue
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		return resp, nil
+	}
+}
+
+// access_token 相关错误码
+const (
+	errCodeTokenInvalid = 110
+	errCodeTokenExpired = 111
+)
+
+// isJSONResponse 检查响应是否为 JSON（而非 SSE）
+func isJSONResponse(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+}
+
 // ensureToken 确保 access_token 有效，过期前 5 分钟刷新
+// 刷新请求受 ctx 控制，凭证通过表单提交而非 URL 查询参数
 func (p *Provider) ensureToken(ctx context.Context) (string, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -171,25 +266,37 @@ func (p *Provider) ensureToken(ctx context.Context) (string, error) {
 		return p.accessToken, nil
 	}
 
-	url := fmt.Sprintf("%s?grant_type=client_credentials&client_id=%s&client_secret=%s",
-		tokenURL, p.apiKey, p.secretKey)
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {p.apiKey},
+		"client_secret": {p.secretKey},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := p.httpClient.Get(url)
+	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
 		return "", fmt.Errorf("token request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var result struct {
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-		Error       string `json:"error"`
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return "", fmt.Errorf("token decode failed: %w", err)
 	}
 	if result.Error != "" {
-		return "", fmt.Errorf("token error: %s", result.Error)
+		return "", fmt.Errorf("token error: %s %s", result.Error, result.ErrorDescription)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("token error: empty access_token (status %d)", resp.StatusCode)
 	}
 
 	p.accessToken = result.AccessToken
@@ -197,51 +304,143 @@ func (p *Provider) ensureToken(ctx context.Context) (string, error) {
 	return p.accessToken, nil
 }
 
+// invalidateToken 使服务端判定失效的 access_token 作废
+// 仅当缓存的仍是该 token 时清除，避免覆盖其他请求已刷新的新 token
+func (p *Provider) invalidateToken(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.accessToken == token {
+		p.accessToken = ""
+	}
+}
+
 type ernieMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role         string             `json:"role"`
+	Content      string             `json:"content"`
+	Name         string             `json:"name,omitempty"`
+	FunctionCall *ernieFunctionCall `json:"function_call,omitempty"`
+}
+
+type ernieFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Thoughts  string `json:"thoughts,omitempty"`
+}
+
+type ernieFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Parameters  *llm.Schema `json:"parameters"`
 }
 
 type ernieRequest struct {
-	Messages []ernieMessage `json:"messages"`
-	System   string         `json:"system,omitempty"`
-	Stream   bool           `json:"stream,omitempty"`
+	Messages        []ernieMessage  `json:"messages"`
+	System          string          `json:"system,omitempty"`
+	Functions       []ernieFunction `json:"functions,omitempty"`
+	ToolChoice      any             `json:"tool_choice,omitempty"`
+	Stream          bool            `json:"stream,omitempty"`
+	Temperature     *float64        `json:"temperature,omitempty"`
+	TopP            *float64        `json:"top_p,omitempty"`
+	PenaltyScore    *float64        `json:"penalty_score,omitempty"`
+	Stop            []string        `json:"stop,omitempty"`
+	MaxOutputTokens int             `json:"max_output_tokens,omitempty"`
+	ResponseFormat  string          `json:"response_format,omitempty"`
+	UserID          string          `json:"user_id,omitempty"`
 }
 
-func (p *Provider) buildRequest(req llm.CompletionRequest, stream bool) []byte {
-	er := ernieRequest{Stream: stream}
+func (p *Provider) buildRequest(req llm.CompletionRequest, stream bool) ([]byte, error) {
+	er := ernieRequest{
+		Stream:          stream,
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		PenaltyScore:    req.RepetitionPenalty,
+		Stop:            req.Stop,
+		MaxOutputTokens: req.MaxTokens,
+		UserID:          req.User,
+	}
 
+	for _, t := range req.Tools {
+		er.Functions = append(er.Functions, ernieFunction{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  t.Function.Parameters,
+		})
+	}
+	// 旧版 API 仅支持指定函数的对象形式，"auto"/"none" 等字符串取默认行为
+	if _, isString := req.ToolChoice.(string); !isString && len(er.Functions) > 0 {
+		er.ToolChoice = req.ToolChoice
+	}
+	if rf := req.ResponseFormat; rf != nil && rf.Type != "" && rf.Type != "text" {
+		// 不支持 json_schema，降级为 json_object
+		er.ResponseFormat = "json_object"
+	}
+
+	// 旧版 API 的函数调用没有 ID，工具结果按函数名关联
+	toolNames := make(map[string]string)
+	var system []string
 	for _, m := range req.Messages {
-		role := string(m.Role)
-		if role == "system" {
-			er.System = m.Content
-			continue
+		switch m.Role {
+		case llm.RoleSystem:
+			system = append(system, m.Content)
+		case llm.RoleAssistant:
+			msg := ernieMessage{Role: "assistant", Content: m.Content}
+			if len(m.ToolCalls) > 1 {
+				// 每条 assistant 消息仅能携带一次函数调用，静默丢弃会使后续工具结果失去对应调用
+				return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "multiple tool calls per message on legacy API"}
+			}
+			if len(m.ToolCalls) > 0 {
+				tc := m.ToolCalls[0]
+				toolNames[tc.ID] = tc.Name
+				msg.FunctionCall = &ernieFunctionCall{Name: tc.Name, Arguments: tc.Arguments}
+			}
+			er.Messages = append(er.Messages, msg)
+		case llm.RoleTool:
+			name := m.Name
+			if name == "" {
+				name = toolNames[m.ToolCallID]
+			}
+			er.Messages = append(er.Messages, ernieMessage{Role: "function", Name: name, Content: m.Content})
+		default:
+			er.Messages = append(er.Messages, ernieMessage{Role: "user", Content: m.Content})
 		}
-		if role != "user" && role != "assistant" {
-			role = "user"
-		}
-		er.Messages = append(er.Messages, ernieMessage{Role: role, Content: m.Content})
 	}
+	er.System = strings.Join(system, "\n\n")
 
 	// ERNIE requires messages to start with user
 	if len(er.Messages) > 0 && er.Messages[0].Role != "user" {
 		er.Messages = append([]ernieMessage{{Role: "user", Content: "请继续"}}, er.Messages...)
 	}
 
-	data, _ := json.Marshal(er)
-	return data
+	data, err := json.Marshal(er)
+	if err != nil || len(req.ExtraBody) == 0 {
+		return data, err
+	}
+
+	// ExtraBody 需要与结构体字段一同校验，先转为 map 再合并
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if err := llm.MergeExtraBody(payload, req.ExtraBody, "messages", "system", "stream"); err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+type ernieUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 type ernieResponse struct {
-	ID     string `json:"id"`
-	Result string `json:"result"`
-	Usage  struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-	ErrorCode int    `json:"error_code"`
-	ErrorMsg  string `json:"error_msg"`
+	ID           string             `json:"id"`
+	Result       string             `json:"result"`
+	FinishReason string             `json:"finish_reason"`
+	FunctionCall *ernieFunctionCall `json:"function_call"`
+	Usage        ernieUsage         `json:"usage"`
+	ErrorCode    int                `json:"error_code"`
+	ErrorMsg     string             `json:"error_msg"`
 }
 
 func (p *Provider) parseResponse(data []byte) (*llm.CompletionResponse, error) {
@@ -253,7 +452,7 @@ func (p *Provider) parseResponse(data []byte) (*llm.CompletionResponse, error) {
 		return nil, fmt.Errorf("ernie error %d: %s", er.ErrorCode, er.ErrorMsg)
 	}
 
-	return &llm.CompletionResponse{
+	resp := &llm.CompletionResponse{
 		ID:      er.ID,
 		Content: er.Result,
 		Usage: llm.Usage{
@@ -261,8 +460,25 @@ func (p *Provider) parseResponse(data []byte) (*llm.CompletionResponse, error) {
 			CompletionTokens: er.Usage.CompletionTokens,
 			TotalTokens:      er.Usage.TotalTokens,
 		},
-		FinishReason: "stop",
-	}, nil
+		FinishReason: finishReason(er.FinishReason, er.FunctionCall != nil),
+	}
+	if fc := er.FunctionCall; fc != nil {
+		resp.ToolCalls = []llm.ToolCall{{ID: er.ID, Type: "function", Name: fc.Name, Arguments: fc.Arguments}}
+		resp.Reasoning = fc.Thoughts
+	}
+	return resp, nil
+}
+
+// finishReason 将 ERNIE 的结束原因映射为 OpenAI 风格
+func finishReason(reason string, hasFunctionCall bool) string {
+	switch {
+	case hasFunctionCall || reason == "function_call":
+		return "tool_calls"
+	case reason == "" || reason == "normal":
+		return "stop"
+	default:
+		return reason
+	}
 }
 
 // ernieStreamParser 解析 ERNIE SSE 流
@@ -270,23 +486,31 @@ type ernieStreamParser struct{}
 
 func (p *ernieStreamParser) Parse(data []byte) (*streamx.Chunk, error) {
 	var er struct {
-		Result string `json:"result"`
-		IsEnd  bool   `json:"is_end"`
-		Usage  struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
-		} `json:"usage"`
+		ernieResponse
+		IsEnd bool `json:"is_end"`
 	}
 	if err := json.Unmarshal(data, &er); err != nil {
 		return nil, err
 	}
+	if er.ErrorCode != 0 {
+		return nil, fmt.Errorf("ernie error %d: %s", er.ErrorCode, er.ErrorMsg)
+	}
 
 	chunk := &streamx.Chunk{
 		Content: er.Result,
 	}
+	if fc := er.FunctionCall; fc != nil {
+		chunk.ToolCalls = []streamx.ToolCall{{ID: er.ID, Type: "function", Name: fc.Name, Arguments: fc.Arguments}}
+	}
 	if er.IsEnd {
-		chunk.FinishReason = "stop"
+		chunk.FinishReason = finishReason(er.FinishReason, er.FunctionCall != nil)
+	}
+	if er.Usage.TotalTokens > 0 {
+		chunk.Usage = &streamx.Usage{
+			PromptTokens:     er.Usage.PromptTokens,
+			CompletionTokens: er.Usage.CompletionTokens,
+			TotalTokens:      er.Usage.TotalTokens,
+		}
 	}
 	return chunk, nil
 }
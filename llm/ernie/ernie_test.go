@@ -1,6 +1,11 @@
 package ernie
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hexagon-codes/ai-core/llm"
@@ -14,7 +19,10 @@ func TestBuildRequest_SystemMessage(t *testing.T) {
 			{Role: "user", Content: "Hello"},
 		},
 	}
-	data := p.buildRequest(req, false)
+	data, err := p.buildRequest(req, false)
+	if err != nil {
+		t.Fatalf("buildRequest error: %v", err)
+	}
 	if len(data) == 0 {
 		t.Fatal("empty request body")
 	}
@@ -33,7 +41,10 @@ func TestBuildRequest_UserFirst(t *testing.T) {
 			{Role: "user", Content: "hello"},
 		},
 	}
-	data := p.buildRequest(req, false)
+	data, err := p.buildRequest(req, false)
+	if err != nil {
+		t.Fatalf("buildRequest error: %v", err)
+	}
 	s := string(data)
 	// Messages should start with user (ERNIE requirement)
 	if !contains(s, `"role":"user"`) {
@@ -41,6 +52,24 @@ func TestBuildRequest_UserFirst(t *testing.T) {
 	}
 }
 
+func TestBuildRequest_MultipleToolCalls(t *testing.T) {
+	p := New("key", "secret")
+	req := llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: "user", Content: "weather?"},
+			{Role: "assistant", ToolCalls: []llm.ToolCallRef{
+				{ID: "1", Name: "weather", Arguments: `{"city":"北京"}`},
+				{ID: "2", Name: "weather", Arguments: `{"city":"上海"}`},
+			}},
+		},
+	}
+	_, err := p.buildRequest(req, false)
+	var unsupported *llm.UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("err = %v, want UnsupportedError", err)
+	}
+}
+
 func TestParseResponse_Success(t *testing.T) {
 	p := New("key", "secret")
 	data := []byte(`{"id":"123","result":"Hello!","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
@@ -80,6 +109,128 @@ func TestModels(t *testing.T) {
 	}
 }
 
+func TestBuildRequest_Functions(t *testing.T) {
+	p := New("key", "secret")
+	data, err := p.buildRequest(llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: "a"},
+			{Role: llm.RoleSystem, Content: "b"},
+			{Role: llm.RoleUser, Content: "weather?"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCallRef{{ID: "as-1", Name: "get_weather", Arguments: `{"city":"北京"}`}}},
+			{Role: llm.RoleTool, ToolCallID: "as-1", Content: "晴"},
+		},
+		Tools:          []llm.ToolDefinition{llm.NewToolDefinition("get_weather", "查询天气", &llm.Schema{Type: "object"})},
+		ToolChoice:     "auto",
+		ResponseFormat: &llm.ResponseFormat{Type: "json_schema"},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequest error: %v", err)
+	}
+
+	var body struct {
+		System         string           `json:"system"`
+		Functions      []map[string]any `json:"functions"`
+		ToolChoice     any              `json:"tool_choice"`
+		ResponseFormat string           `json:"response_format"`
+		Messages       []ernieMessage   `json:"messages"`
+	}
+	json.Unmarshal(data, &body)
+	if body.System != "a\n\nb" {
+		t.Errorf("system = %q", body.System)
+	}
+	if len(body.Functions) != 1 || body.Functions[0]["name"] != "get_weather" || body.ToolChoice != nil {
+		t.Errorf("functions = %v, tool_choice = %v", body.Functions, body.ToolChoice)
+	}
+	if body.ResponseFormat != "json_object" {
+		t.Errorf("response_format = %q", body.ResponseFormat)
+	}
+	if len(body.Messages) != 3 || body.Messages[1].FunctionCall == nil ||
+		body.Messages[2].Role != "function" || body.Messages[2].Name != "get_weather" {
+		t.Errorf("messages = %+v", body.Messages)
+	}
+}
+
+func TestParseResponse_FunctionCall(t *testing.T) {
+	p := New("key", "secret")
+	resp, err := p.parseResponse([]byte(`{"id":"as-1","result":"","finish_reason":"function_call","function_call":{"name":"get_weather","arguments":"{}","thoughts":"需要查询天气"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FinishReason != "tool_calls" || len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "get_weather" || resp.Reasoning == "" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestComplete_TokenRefresh(t *testing.T) {
+	var tokenCalls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		r.ParseForm()
+		if r.URL.RawQuery != "" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("credentials should be sent as form body, query = %q", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "tok" + string(rune('0'+tokenCalls)), "expires_in": 3600})
+	})
+	mux.HandleFunc("/chat/ernie-4.0-8k", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("access_token") == "tok1" {
+			io.WriteString(w, `{"error_code":111,"error_msg":"Access token expired"}`)
+			return
+		}
+		io.WriteString(w, `{"id":"as-2","result":"你好","usage":{"total_tokens":3}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := New("key", "secret", WithBaseURL(srv.URL), WithTokenURL(srv.URL+"/token"))
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.Content != "你好" || tokenCalls != 2 {
+		t.Errorf("content = %q, token calls = %d", resp.Content, tokenCalls)
+	}
+}
+
+func TestNewV2(t *testing.T) {
+	var (
+		auth string
+		body map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		io.WriteString(w, `{"id":"c1","model":"ernie-4.5-turbo-128k","choices":[{"index":0,"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`)
+	}))
+	defer srv.Close()
+
+	penalty := 1.1
+	p := NewV2("bce-v3/key", WithBaseURL(srv.URL))
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{
+		Messages:          []llm.Message{{Role: llm.RoleSystem, Content: "sys"}, {Role: llm.RoleUser, Content: "weather?"}},
+		Tools:             []llm.ToolDefinition{llm.NewToolDefinition("get_weather", "查询天气", &llm.Schema{Type: "object"})},
+		MaxTokens:         64,
+		RepetitionPenalty: &penalty,
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+
+	if auth != "Bearer bce-v3/key" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if body["model"] != "ernie-4.5-turbo-128k" || body["penalty_score"] != 1.1 || body["max_completion_tokens"] != float64(64) || body["tools"] == nil {
+		t.Errorf("body = %v", body)
+	}
+	if msgs, _ := body["messages"].([]any); len(msgs) != 2 {
+		t.Errorf("system should stay in messages: %v", body["messages"])
+	}
+	if p.Name() != "ernie" || len(resp.ToolCalls) != 1 || resp.Usage.TotalTokens != 8 {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
 func contains(s, sub string) bool {
 	return len(s) >= len(sub) && (s == sub || len(s) > 0 && containsStr(s, sub))
 }
@@ -0,0 +1,28 @@
+package ernie
+
+import (
+	"fmt"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// 注册为 "ernie"：提供 options.secret_key 时使用旧版 API，否则使用千帆 v2
+func init() {
+	llm.RegisterProvider("ernie", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		var opts []Option
+		if cfg.BaseURL != "" {
+			opts = append(opts, WithBaseURL(cfg.BaseURL))
+		}
+		if cfg.Model != "" {
+			opts = append(opts, WithModel(cfg.Model))
+		}
+		secretKey := cfg.Option("secret_key")
+		if secretKey == "" {
+			return NewV2(cfg.APIKey, opts...), nil
+		}
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("ernie legacy api requires api_key with options.secret_key")
+		}
+		return New(cfg.APIKey, secretKey, opts...), nil
+	})
+}
@@ -0,0 +1,95 @@
+package ernie
+
+import (
+	"os"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
+	"github.com/hexagon-codes/toolkit/net/httpx"
+)
+
+const (
+	v2BaseURL      = "https://qianfan.baidubce.com/v2"
+	v2DefaultModel = "ernie-4.5-turbo-128k"
+)
+
+// NewV2 创建千帆 v2 API 的 ERNIE Provider
+// v2 接口兼容 OpenAI 协议，使用 Bearer API Key 认证，支持函数调用、
+// system 消息、response_format 及流式用量统计。
+// apiKey 可以为空，会从环境变量 QIANFAN_API_KEY 读取
+func NewV2(apiKey string, opts ...Option) *Provider {
+	if apiKey == "" {
+		apiKey = os.Getenv("QIANFAN_API_KEY")
+	}
+
+	p := &Provider{
+		apiKey:     apiKey,
+		baseURL:    v2BaseURL,
+		model:      v2DefaultModel,
+		httpClient: httpx.RawClient(httpx.WithResponseHeaderTimeout(120 * time.Second)),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.chat = openai.NewCompatible(v2Profile, p.apiKey,
+		openai.WithBaseURL(p.baseURL),
+		openai.WithModel(p.model),
+		openai.WithHTTPClient(p.httpClient),
+	)
+	return p
+}
+
+// v2Profile 千帆 v2 兼容接口的差异：
+//   - 不支持 n、logprobs、top_k
+//   - 重复惩罚使用 penalty_score，最大输出使用 max_completion_tokens
+//   - 托管的混合推理模型（Qwen3 等）使用 enable_thinking/thinking_budget
+var v2Profile = openai.Profile{
+	Name:         "ernie",
+	BaseURL:      v2BaseURL,
+	DefaultModel: v2DefaultModel,
+	APIKeyEnv:    []string{"QIANFAN_API_KEY"},
+	Unsupported:  []string{"n", "logprobs", "top_k"},
+	RenameParams: map[string]string{
+		"repetition_penalty": "penalty_score",
+		"max_tokens":         "max_completion_tokens",
+	},
+	Reasoning: openai.EnableThinking,
+	Models: []llm.ModelInfo{
+		{
+			ID:          "ernie-4.5-turbo-128k",
+			Name:        "ERNIE 4.5 Turbo",
+			Description: "百度旗舰模型，长上下文",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "ernie-4.5-turbo-vl-32k",
+			Name:        "ERNIE 4.5 Turbo VL",
+			Description: "多模态理解模型",
+			MaxTokens:   32768,
+			Features:    []string{llm.FeatureVision, llm.FeatureStreaming},
+		},
+		{
+			ID:          "ernie-x1-turbo-32k",
+			Name:        "ERNIE X1 Turbo",
+			Description: "深度推理模型",
+			MaxTokens:   32768,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "ernie-4.0-8k",
+			Name:        "ERNIE 4.0",
+			Description: "强大的中文理解能力",
+			MaxTokens:   8192,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+		},
+		{
+			ID:          "ernie-speed-128k",
+			Name:        "ERNIE Speed",
+			Description: "高性价比通用模型",
+			MaxTokens:   131072,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+		},
+	},
+}
@@ -0,0 +1,32 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported 表示 Provider 不支持请求中的某项能力
+// 可通过 errors.Is(err, ErrUnsupported) 判断，再用 errors.As 取得 *UnsupportedError 详情
+var ErrUnsupported = errors.New("llm: unsupported")
+
+// ErrReservedParam 表示 ExtraBody 试图覆盖 Provider 保留或已设置的请求参数
+var ErrReservedParam = errors.New("llm: reserved request parameter")
+
+// UnsupportedError 描述 Provider 不支持的具体能力
+type UnsupportedError struct {
+	// Provider 提供者名称
+	Provider string
+
+	// Feature 不支持的能力（如 "logprobs"、"streaming with n>1"）
+	Feature string
+}
+
+// Error 实现 error 接口
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s: %s is not supported", e.Provider, e.Feature)
+}
+
+// Is 使 errors.Is(err, ErrUnsupported) 成立
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergeExtraBody 将 ExtraBody 合并到 Provider 构造的请求体中
+//
+// payload 中已存在的键（model、messages 以及类型化字段映射出的参数）与
+// reserved 中列出的键不可被覆盖，冲突时返回包装了 ErrReservedParam 的错误，
+// 调用方应改用对应的类型化字段。
+func MergeExtraBody(payload map[string]any, extra map[string]any, reserved ...string) error {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	// 按键排序，保证冲突错误信息稳定
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, exists := payload[k]; exists {
+			return fmt.Errorf("%w: extra_body key %q conflicts with a field set by the provider", ErrReservedParam, k)
+		}
+		for _, r := range reserved {
+			if k == r {
+				return fmt.Errorf("%w: extra_body key %q is reserved", ErrReservedParam, k)
+			}
+		}
+	}
+	for _, k := range keys {
+		payload[k] = extra[k]
+	}
+	return nil
+}
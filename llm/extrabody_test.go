@@ -0,0 +1,28 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeExtraBody(t *testing.T) {
+	payload := map[string]any{"model": "qwen-plus", "temperature": 0.5}
+
+	if err := MergeExtraBody(payload, map[string]any{"enable_search": true}); err != nil {
+		t.Fatalf("MergeExtraBody error: %v", err)
+	}
+	if payload["enable_search"] != true {
+		t.Errorf("enable_search not merged: %v", payload)
+	}
+
+	if err := MergeExtraBody(payload, map[string]any{"temperature": 1.0}); !errors.Is(err, ErrReservedParam) {
+		t.Errorf("overriding a typed field should fail with ErrReservedParam, got %v", err)
+	}
+	if payload["temperature"] != 0.5 {
+		t.Errorf("payload must not be modified on conflict, temperature = %v", payload["temperature"])
+	}
+
+	if err := MergeExtraBody(payload, map[string]any{"stream_options": nil}, "stream_options"); !errors.Is(err, ErrReservedParam) {
+		t.Errorf("reserved key should fail with ErrReservedParam, got %v", err)
+	}
+}
@@ -19,6 +19,12 @@ import (
 const (
 	defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
 	defaultModel   = "gemini-2.0-flash"
+
+	// defaultEmbeddingModel 默认 Embedding 模型
+	defaultEmbeddingModel = "text-embedding-004"
+
+	// maxEmbeddingInputs batchEmbedContents 单次请求的最大输入条数
+	maxEmbeddingInputs = 100
 )
 
 // Provider 实现 Google Gemini LLM 提供者
@@ -27,6 +33,7 @@ type Provider struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
+	catalog    *llm.ModelCatalog
 }
 
 // Option 是 Provider 的配置选项
@@ -73,6 +80,7 @@ func New(apiKey string, opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.catalog = llm.NewModelCatalog(curatedModels, p.fetchModels, 0)
 
 	return p
 }
@@ -84,6 +92,12 @@ func (p *Provider) Name() string {
 
 // Complete 执行非流式补全请求
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -126,6 +140,15 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 
 // Stream 执行流式补全请求
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	if req.WantsLogprobs() {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if req.N > 1 {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -162,54 +185,9 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 }
 
 // Models 返回可用模型列表
+// ListModels 成功后返回远端目录，否则返回内置列表
 func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
-		{
-			ID:          "gemini-2.0-flash",
-			Name:        "Gemini 2.0 Flash",
-			Description: "Next-gen fast and versatile model",
-			MaxTokens:   1048576,
-			InputCost:   0.10,
-			OutputCost:  0.40,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gemini-2.0-flash-thinking",
-			Name:        "Gemini 2.0 Flash Thinking",
-			Description: "Fast model with enhanced reasoning",
-			MaxTokens:   1048576,
-			InputCost:   0.10,
-			OutputCost:  0.40,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gemini-1.5-pro",
-			Name:        "Gemini 1.5 Pro",
-			Description: "Most capable model for complex tasks",
-			MaxTokens:   2097152,
-			InputCost:   1.25,
-			OutputCost:  5.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gemini-1.5-flash",
-			Name:        "Gemini 1.5 Flash",
-			Description: "Fast and efficient for most tasks",
-			MaxTokens:   1048576,
-			InputCost:   0.075,
-			OutputCost:  0.30,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gemini-1.5-flash-8b",
-			Name:        "Gemini 1.5 Flash 8B",
-			Description: "Lightweight and cost-effective",
-			MaxTokens:   1048576,
-			InputCost:   0.0375,
-			OutputCost:  0.15,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-	}
+	return p.catalog.Models()
 }
 
 // CountTokens 计算消息的 Token 数量（简化实现）
@@ -266,6 +244,24 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest) ([]byte, error) {
 	if len(req.Stop) > 0 {
 		generationConfig["stopSequences"] = req.Stop
 	}
+	if req.N > 1 {
+		generationConfig["candidateCount"] = req.N
+	}
+	if req.TopK != nil {
+		generationConfig["topK"] = *req.TopK
+	}
+	if req.Seed != nil {
+		generationConfig["seed"] = *req.Seed
+	}
+	if req.PresencePenalty != nil {
+		generationConfig["presencePenalty"] = *req.PresencePenalty
+	}
+	if req.FrequencyPenalty != nil {
+		generationConfig["frequencyPenalty"] = *req.FrequencyPenalty
+	}
+	if req.Reasoning.Specified() {
+		generationConfig["thinkingConfig"] = buildThinkingConfig(req.Reasoning)
+	}
 
 	// ResponseFormat 支持
 	// Gemini 通过 responseMimeType 和 responseSchema 控制输出格式
@@ -302,9 +298,29 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest) ([]byte, error) {
 		payload["tools"] = tools
 	}
 
+	if err := llm.MergeExtraBody(payload, req.ExtraBody, "systemInstruction", "generationConfig"); err != nil {
+		return nil, err
+	}
+
 	return json.Marshal(payload)
 }
 
+// buildThinkingConfig 构建 Gemini 2.5 的 thinkingConfig
+// thinkingBudget 为 0 表示关闭思考，-1 表示由模型动态决定
+func buildThinkingConfig(cfg *llm.ReasoningConfig) map[string]any {
+	if !cfg.Enabled() {
+		return map[string]any{"thinkingBudget": 0}
+	}
+	budget := cfg.Budget()
+	if budget == 0 {
+		budget = -1
+	}
+	return map[string]any{
+		"thinkingBudget":  budget,
+		"includeThoughts": !cfg.Exclude,
+	}
+}
+
 // convertRole 转换角色名称
 func convertRole(role llm.Role) string {
 	switch role {
@@ -325,6 +341,7 @@ type geminiContent struct {
 
 type geminiPart struct {
 	Text         string              `json:"text,omitempty"`
+	Thought      bool                `json:"thought,omitempty"` // 思考摘要部分（includeThoughts）
 	FunctionCall *geminiFunctionCall `json:"functionCall,omitempty"`
 }
 
@@ -346,35 +363,33 @@ type geminiResponse struct {
 			Probability string `json:"probability"`
 		} `json:"safetyRatings"`
 	} `json:"candidates"`
-	UsageMetadata struct {
-		PromptTokenCount     int `json:"promptTokenCount"`
-		CandidatesTokenCount int `json:"candidatesTokenCount"`
-		TotalTokenCount      int `json:"totalTokenCount"`
-	} `json:"usageMetadata"`
+	UsageMetadata streamx.GeminiUsage `json:"usageMetadata"`
 }
 
 // parseResponse 解析响应
 func (p *Provider) parseResponse(resp *geminiResponse, model string) *llm.CompletionResponse {
 	result := &llm.CompletionResponse{
 		Model: model,
-		Usage: llm.Usage{
-			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
-			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
-			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
-		},
+		Usage: resp.UsageMetadata.ToUsage(),
 	}
 
-	if len(resp.Candidates) > 0 {
-		candidate := resp.Candidates[0]
-		result.FinishReason = candidate.FinishReason
-
+	choices := make([]llm.Choice, len(resp.Candidates))
+	for i, candidate := range resp.Candidates {
+		choice := llm.Choice{
+			Index:        i,
+			FinishReason: candidate.FinishReason,
+		}
 		for _, part := range candidate.Content.Parts {
+			if part.Thought {
+				choice.Reasoning += part.Text
+				continue
+			}
 			if part.Text != "" {
-				result.Content += part.Text
+				choice.Content += part.Text
 			}
 			if part.FunctionCall != nil {
 				args, _ := json.Marshal(part.FunctionCall.Args)
-				result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
+				choice.ToolCalls = append(choice.ToolCalls, llm.ToolCall{
 					ID:        fmt.Sprintf("call_%s", part.FunctionCall.Name),
 					Type:      "function",
 					Name:      part.FunctionCall.Name,
@@ -382,43 +397,94 @@ func (p *Provider) parseResponse(resp *geminiResponse, model string) *llm.Comple
 				})
 			}
 		}
+		choices[i] = choice
 	}
+	result.SetChoices(choices)
 
 	return result
 }
 
 // Embed 生成文本的向量嵌入
 func (p *Provider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
-	return p.EmbedWithModel(ctx, "text-embedding-004", texts)
+	return p.EmbedWithModel(ctx, defaultEmbeddingModel, texts)
 }
 
 // EmbedWithModel 使用指定模型生成嵌入
 func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []string) ([][]float32, error) {
-	// 批量嵌入
-	requests := make([]map[string]any, len(texts))
-	for i, text := range texts {
-		requests[i] = map[string]any{
-			"model": fmt.Sprintf("models/%s", model),
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	resp, err := p.CreateEmbeddings(ctx, llm.EmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// CreateEmbeddings 按完整请求参数生成嵌入
+//
+// batchEmbedContents 单次最多 maxEmbeddingInputs 条，超出时自动分批；
+// Dimensions 映射为 outputDimensionality，InputType 映射为 taskType（RETRIEVAL_QUERY/RETRIEVAL_DOCUMENT）。
+// Gemini 不返回 Embedding 用量，Base64 被忽略。
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultEmbeddingModel
+	}
+	if len(req.Input) == 0 {
+		return &llm.EmbedResponse{Model: req.Model}, nil
+	}
+	return llm.EmbedBatches(ctx, req, maxEmbeddingInputs, func(ctx context.Context, input []string) (*llm.EmbedResponse, error) {
+		return p.embedBatch(ctx, req, input)
+	})
+}
+
+// embedBatch 发送单批 batchEmbedContents 请求
+func (p *Provider) embedBatch(ctx context.Context, req llm.EmbedRequest, input []string) (*llm.EmbedResponse, error) {
+	var taskType string
+	switch req.InputType {
+	case llm.EmbedInputQuery:
+		taskType = "RETRIEVAL_QUERY"
+	case llm.EmbedInputDocument:
+		taskType = "RETRIEVAL_DOCUMENT"
+	}
+
+	requests := make([]map[string]any, len(input))
+	for i, text := range input {
+		r := map[string]any{
+			"model": fmt.Sprintf("models/%s", req.Model),
 			"content": map[string]any{
 				"parts": []map[string]string{{"text": text}},
 			},
 		}
+		if taskType != "" {
+			r["taskType"] = taskType
+		}
+		if req.Dimensions > 0 {
+			r["outputDimensionality"] = req.Dimensions
+		}
+		requests[i] = r
 	}
 
 	payload := map[string]any{
 		"requests": requests,
 	}
-	body, _ := json.Marshal(payload)
+	if err := llm.MergeExtraBody(payload, req.ExtraBody); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
 
-	url := fmt.Sprintf("%s/models/%s:batchEmbedContents", p.baseURL, model)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	url := fmt.Sprintf("%s/models/%s:batchEmbedContents", p.baseURL, req.Model)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-goog-api-key", p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", p.apiKey)
 
-	resp, err := p.httpClient.Do(req)
+	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -441,14 +507,16 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 		return nil, err
 	}
 
-	embeddings := make([][]float32, len(result.Embeddings))
+	out := &llm.EmbedResponse{
+		Model:      req.Model,
+		Embeddings: make([][]float32, len(result.Embeddings)),
+	}
 	for i, e := range result.Embeddings {
-		embeddings[i] = e.Values
+		out.Embeddings[i] = e.Values
 	}
-
-	return embeddings, nil
+	return out, nil
 }
 
 // 确保实现了 Provider 和 EmbeddingProvider 接口
 var _ llm.Provider = (*Provider)(nil)
-var _ llm.EmbeddingProvider = (*Provider)(nil)
+var _ llm.EmbedRequestProvider = (*Provider)(nil)
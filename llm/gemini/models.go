@@ -0,0 +1,120 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ListModels 调用 /models 获取支持 generateContent 的模型，并与内置元数据合并（带缓存）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.catalog.List(ctx)
+}
+
+// fetchModels 请求 /models 端点（单页最多 1000 个）
+func (p *Provider) fetchModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models?pageSize=1000", nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("x-goog-api-key", p.apiKey)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("gemini api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("gemini api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	var result struct {
+		Models []struct {
+			Name                       string   `json:"name"`
+			DisplayName                string   `json:"displayName"`
+			Description                string   `json:"description"`
+			InputTokenLimit            int      `json:"inputTokenLimit"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	models := make([]llm.ModelInfo, 0, len(result.Models))
+	for _, m := range result.Models {
+		if !slices.Contains(m.SupportedGenerationMethods, "generateContent") {
+			continue
+		}
+		models = append(models, llm.ModelInfo{
+			ID:          strings.TrimPrefix(m.Name, "models/"),
+			Name:        m.DisplayName,
+			Description: m.Description,
+			MaxTokens:   m.InputTokenLimit,
+		})
+	}
+	return models, nil
+}
+
+// 确保实现了 ModelLister 接口
+var _ llm.ModelLister = (*Provider)(nil)
+
+// curatedModels 内置模型元数据（上下文长度、特性、价格）
+var curatedModels = []llm.ModelInfo{
+	{
+		ID:          "gemini-2.0-flash",
+		Name:        "Gemini 2.0 Flash",
+		Description: "Next-gen fast and versatile model",
+		MaxTokens:   1048576,
+		InputCost:   0.10,
+		OutputCost:  0.40,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+	{
+		ID:          "gemini-2.0-flash-thinking",
+		Name:        "Gemini 2.0 Flash Thinking",
+		Description: "Fast model with enhanced reasoning",
+		MaxTokens:   1048576,
+		InputCost:   0.10,
+		OutputCost:  0.40,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
+	},
+	{
+		ID:          "gemini-1.5-pro",
+		Name:        "Gemini 1.5 Pro",
+		Description: "Most capable model for complex tasks",
+		MaxTokens:   2097152,
+		InputCost:   1.25,
+		OutputCost:  5.00,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+	{
+		ID:          "gemini-1.5-flash",
+		Name:        "Gemini 1.5 Flash",
+		Description: "Fast and efficient for most tasks",
+		MaxTokens:   1048576,
+		InputCost:   0.075,
+		OutputCost:  0.30,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+	{
+		ID:          "gemini-1.5-flash-8b",
+		Name:        "Gemini 1.5 Flash 8B",
+		Description: "Lightweight and cost-effective",
+		MaxTokens:   1048576,
+		InputCost:   0.0375,
+		OutputCost:  0.15,
+		Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+	},
+}
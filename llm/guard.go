@@ -0,0 +1,346 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// ============== 内容审核中间件 ==============
+
+// ErrContentFlagged 表示内容未通过审核
+// 可通过 errors.Is 判断，再用 errors.As 取得 *ModerationError 详情
+var ErrContentFlagged = errors.New("llm: content flagged by moderation")
+
+// GuardStage 审核阶段
+type GuardStage string
+
+const (
+	GuardInput  GuardStage = "input"  // 请求发送前的输入审核
+	GuardOutput GuardStage = "output" // 模型返回后的输出审核
+)
+
+// GuardAction 内容被标记后的处理方式
+type GuardAction string
+
+const (
+	// GuardBlock 拒绝请求，返回 *ModerationError（默认）
+	GuardBlock GuardAction = "block"
+
+	// GuardRedact 将被标记的内容替换为 GuardConfig.RedactText 后继续
+	GuardRedact GuardAction = "redact"
+)
+
+// ModerationError 内容审核拦截错误
+type ModerationError struct {
+	// Stage 被拦截的阶段
+	Stage GuardStage
+
+	// Result 触发拦截的审核结果
+	Result ModerationResult
+}
+
+// Error 实现 error 接口
+func (e *ModerationError) Error() string {
+	return fmt.Sprintf("llm: %s flagged by moderation: %s", e.Stage, strings.Join(e.Result.FlaggedCategories(), ","))
+}
+
+// Is 使 errors.Is(err, ErrContentFlagged) 成立
+func (e *ModerationError) Is(target error) bool {
+	return target == ErrContentFlagged
+}
+
+// GuardConfig 内容审核中间件配置
+type GuardConfig struct {
+	// Moderator 审核服务（如 openai.Provider）
+	Moderator ModerationProvider
+
+	// Model 审核模型，为空时使用 Moderator 默认值
+	Model string
+
+	// SkipInput 跳过输入审核
+	SkipInput bool
+
+	// SkipOutput 跳过输出审核
+	SkipOutput bool
+
+	// Action 命中后的处理方式，默认 GuardBlock
+	Action GuardAction
+
+	// RedactText 脱敏替换文本，默认 "[内容已屏蔽]"
+	RedactText string
+
+	// StreamInterval 流式输出每累积多少字节审核一次，默认 200
+	// 未审核的内容会暂缓下发，保证下游只收到已通过审核的文本
+	StreamInterval int
+
+	// StreamOverlap 流式审核时附带的上一段末尾字节数，默认 64
+	// 每次只审核新增内容加上该重叠窗口，避免跨段拆开的违规内容漏检
+	StreamOverlap int
+}
+
+// WithGuard 创建内容审核中间件
+//
+// 在 Complete/Stream 前审核用户输入，在返回后审核模型输出；
+// 流式输出按 StreamInterval 增量审核。审核服务本身出错时直接返回该错误（fail closed）。
+//
+// 使用示例:
+//
+//	guarded := llm.Chain(provider, llm.WithGuard(llm.GuardConfig{
+//	    Moderator: openai.New("key"),
+//	    Action:    llm.GuardRedact,
+//	}))
+func WithGuard(cfg GuardConfig) Middleware {
+	if cfg.Action == "" {
+		cfg.Action = GuardBlock
+	}
+	if cfg.RedactText == "" {
+		cfg.RedactText = "[内容已屏蔽]"
+	}
+	if cfg.StreamInterval <= 0 {
+		cfg.StreamInterval = 200
+	}
+	if cfg.StreamOverlap <= 0 {
+		cfg.StreamOverlap = 64
+	}
+	return func(next Provider) Provider {
+		return &guardProvider{inner: next, cfg: cfg}
+	}
+}
+
+type guardProvider struct {
+	inner Provider
+	cfg   GuardConfig
+}
+
+func (p *guardProvider) Name() string     { return p.inner.Name() }
+func (p *guardProvider) Unwrap() Provider { return p.inner }
+func (p *guardProvider) Models() []ModelInfo {
+	return p.inner.Models()
+}
+func (p *guardProvider) CountTokens(messages []Message) (int, error) {
+	return p.inner.CountTokens(messages)
+}
+
+func (p *guardProvider) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	req, err := p.checkInput(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.inner.Complete(ctx, req)
+	if err != nil || p.cfg.SkipOutput {
+		return resp, err
+	}
+	return p.checkOutput(ctx, resp)
+}
+
+func (p *guardProvider) Stream(ctx context.Context, req CompletionRequest) (*streamx.Stream, error) {
+	req, err := p.checkInput(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	inner, err := p.inner.Stream(ctx, req)
+	if err != nil || p.cfg.SkipOutput {
+		return inner, err
+	}
+
+	return streamx.NewChunkStream(ctx, func(emit func(*streamx.Chunk) error) error {
+		defer inner.Close()
+		return p.guardStream(ctx, inner, emit)
+	}), nil
+}
+
+// checkInput 审核用户消息，按策略拦截或脱敏
+func (p *guardProvider) checkInput(ctx context.Context, req CompletionRequest) (CompletionRequest, error) {
+	if p.cfg.SkipInput {
+		return req, nil
+	}
+
+	var texts []string
+	var indexes []int
+	for i, msg := range req.Messages {
+		if msg.Role != RoleUser {
+			continue
+		}
+		if text := messageText(msg); text != "" {
+			texts = append(texts, text)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(texts) == 0 {
+		return req, nil
+	}
+
+	results, err := p.moderate(ctx, texts)
+	if err != nil {
+		return req, err
+	}
+
+	redacted := false
+	for i, result := range results {
+		if !result.Flagged {
+			continue
+		}
+		if p.cfg.Action == GuardBlock {
+			return req, &ModerationError{Stage: GuardInput, Result: result}
+		}
+		if !redacted {
+			// 写时复制，避免修改调用方的消息切片
+			req.Messages = append([]Message(nil), req.Messages...)
+			redacted = true
+		}
+		msg := &req.Messages[indexes[i]]
+		msg.Content = p.cfg.RedactText
+		msg.MultiContent = nil
+	}
+	return req, nil
+}
+
+// checkOutput 审核非流式响应的各个选项
+func (p *guardProvider) checkOutput(ctx context.Context, resp *CompletionResponse) (*CompletionResponse, error) {
+	choices := resp.Choices
+	if len(choices) == 0 {
+		choices = []Choice{resp.firstChoice()}
+	}
+
+	// 仅含工具调用的选项没有文本，不送审
+	var texts []string
+	var indexes []int
+	for i, c := range choices {
+		if c.Content != "" {
+			texts = append(texts, c.Content)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(texts) == 0 {
+		return resp, nil
+	}
+	results, err := p.moderate(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged := false
+	for j, result := range results {
+		if !result.Flagged {
+			continue
+		}
+		if p.cfg.Action == GuardBlock {
+			return nil, &ModerationError{Stage: GuardOutput, Result: result}
+		}
+		i := indexes[j]
+		choices[i].Content = p.cfg.RedactText
+		choices[i].ToolCalls = nil
+		choices[i].FinishReason = "content_filter"
+		flagged = true
+	}
+	if flagged {
+		resp.SetChoices(choices)
+	}
+	return resp, nil
+}
+
+// guardStream 增量审核流式输出
+//
+// 内容先暂存，累积满 StreamInterval 或流结束时审核新增部分（附带上一段末尾 StreamOverlap 字节），
+// 通过后才下发暂存的块。每段只审核一次，审核量与输出长度成线性关系。
+func (p *guardProvider) guardStream(ctx context.Context, inner *streamx.Stream, emit func(*streamx.Chunk) error) error {
+	var (
+		unchecked strings.Builder
+		tail      string
+		pending   []*streamx.Chunk
+	)
+
+	// flush 审核新增内容，通过则下发暂存块；返回 false 表示已拦截并结束
+	flush := func() (bool, error) {
+		if unchecked.Len() > 0 {
+			segment := tail + unchecked.String()
+			results, err := p.moderate(ctx, []string{segment})
+			if err != nil {
+				return false, err
+			}
+			tail = lastBytes(segment, p.cfg.StreamOverlap)
+			unchecked.Reset()
+			if len(results) > 0 && results[0].Flagged {
+				if p.cfg.Action == GuardBlock {
+					return false, &ModerationError{Stage: GuardOutput, Result: results[0]}
+				}
+				return false, emit(&streamx.Chunk{Content: p.cfg.RedactText, FinishReason: "content_filter"})
+			}
+		}
+		for _, chunk := range pending {
+			if err := emit(chunk); err != nil {
+				return false, err
+			}
+		}
+		pending = pending[:0]
+		return true, nil
+	}
+
+	for chunk := range inner.Chunks() {
+		pending = append(pending, chunk)
+		unchecked.WriteString(chunk.Content)
+		if unchecked.Len() < p.cfg.StreamInterval {
+			continue
+		}
+		if ok, err := flush(); !ok {
+			return err
+		}
+	}
+
+	select {
+	case err := <-inner.Errors():
+		return err
+	default:
+	}
+
+	_, err := flush()
+	return err
+}
+
+// moderate 调用审核服务，保证结果数量与输入一致
+func (p *guardProvider) moderate(ctx context.Context, texts []string) ([]ModerationResult, error) {
+	resp, err := p.cfg.Moderator.Moderate(ctx, ModerationRequest{Model: p.cfg.Model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("moderation failed: %w", err)
+	}
+	if len(resp.Results) != len(texts) {
+		return nil, fmt.Errorf("moderation returned %d results for %d inputs", len(resp.Results), len(texts))
+	}
+	return resp.Results, nil
+}
+
+// lastBytes 返回 s 末尾至多 n 字节，起点对齐到 UTF-8 字符边界
+func lastBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return s[i:]
+}
+
+// messageText 提取消息的文本内容（多模态消息只取文本部分）
+func messageText(msg Message) string {
+	if !msg.HasMultiContent() {
+		return msg.Content
+	}
+	var b strings.Builder
+	for _, part := range msg.MultiContent {
+		if part.Type == "text" {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// keywordModerator 命中关键词即标记
+type keywordModerator struct {
+	keyword string
+	calls   int
+	inputs  []string
+}
+
+func (m *keywordModerator) Moderate(ctx context.Context, req ModerationRequest) (*ModerationResponse, error) {
+	m.calls++
+	m.inputs = append(m.inputs, req.Input...)
+	resp := &ModerationResponse{}
+	for _, text := range req.Input {
+		flagged := strings.Contains(text, m.keyword)
+		resp.Results = append(resp.Results, ModerationResult{
+			Flagged:    flagged,
+			Categories: map[string]bool{"violence": flagged},
+		})
+	}
+	return resp, nil
+}
+
+// streamingMock 以给定分片返回流式响应
+type streamingMock struct {
+	mockProvider
+	pieces []string
+}
+
+func (m *streamingMock) Stream(ctx context.Context, req CompletionRequest) (*streamx.Stream, error) {
+	return streamx.NewChunkStream(ctx, func(emit func(*streamx.Chunk) error) error {
+		for _, piece := range m.pieces {
+			if err := emit(&streamx.Chunk{Content: piece}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}), nil
+}
+
+func TestGuard_BlocksFlaggedInput(t *testing.T) {
+	mock := &mockProvider{name: "test", completeResp: &CompletionResponse{Content: "ok"}}
+	p := Chain(mock, WithGuard(GuardConfig{Moderator: &keywordModerator{keyword: "bad"}}))
+
+	_, err := p.Complete(context.Background(), CompletionRequest{Messages: []Message{UserMessage("something bad")}})
+	var modErr *ModerationError
+	if !errors.As(err, &modErr) || modErr.Stage != GuardInput {
+		t.Fatalf("err = %v, want input ModerationError", err)
+	}
+	if !errors.Is(err, ErrContentFlagged) {
+		t.Error("error should match ErrContentFlagged")
+	}
+	if mock.callCount.Load() != 0 {
+		t.Error("flagged input must not reach the provider")
+	}
+}
+
+func TestGuard_RedactsFlaggedOutput(t *testing.T) {
+	mock := &mockProvider{name: "test", completeResp: &CompletionResponse{Content: "bad answer", FinishReason: "stop"}}
+	p := Chain(mock, WithGuard(GuardConfig{
+		Moderator:  &keywordModerator{keyword: "bad"},
+		Action:     GuardRedact,
+		RedactText: "***",
+	}))
+
+	resp, err := p.Complete(context.Background(), CompletionRequest{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.Content != "***" || resp.FinishReason != "content_filter" {
+		t.Errorf("unexpected response: content=%q finish=%q", resp.Content, resp.FinishReason)
+	}
+}
+
+func TestGuard_StreamStopsAtFlaggedContent(t *testing.T) {
+	mock := &streamingMock{pieces: []string{"hello ", "world ", "bad ", "tail"}}
+	mock.name = "test"
+	p := Chain(mock, WithGuard(GuardConfig{
+		Moderator:      &keywordModerator{keyword: "bad"},
+		Action:         GuardRedact,
+		RedactText:     "***",
+		StreamInterval: 6,
+	}))
+
+	stream, err := p.Stream(context.Background(), CompletionRequest{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.Content != "hello world ***" {
+		t.Errorf("Content = %q, want %q", result.Content, "hello world ***")
+	}
+	if result.FinishReason != "content_filter" {
+		t.Errorf("FinishReason = %q, want content_filter", result.FinishReason)
+	}
+}
+
+func TestGuard_StreamBlockReturnsError(t *testing.T) {
+	mock := &streamingMock{pieces: []string{"bad"}}
+	mock.name = "test"
+	p := Chain(mock, WithGuard(GuardConfig{Moderator: &keywordModerator{keyword: "bad"}}))
+
+	stream, err := p.Stream(context.Background(), CompletionRequest{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	result, err := stream.Collect()
+	if !errors.Is(err, ErrContentFlagged) {
+		t.Fatalf("Collect err = %v, want ErrContentFlagged", err)
+	}
+	if result != nil && result.Content != "" {
+		t.Errorf("blocked content leaked: %q", result.Content)
+	}
+}
+
+func TestGuard_SkipsToolCallOnlyOutput(t *testing.T) {
+	moderator := &keywordModerator{keyword: "bad"}
+	mock := &mockProvider{name: "test", completeResp: &CompletionResponse{
+		ToolCalls:    []ToolCall{{ID: "1", Type: "function", Name: "lookup", Arguments: "{}"}},
+		FinishReason: "tool_calls",
+	}}
+	p := Chain(mock, WithGuard(GuardConfig{Moderator: moderator, SkipInput: true}))
+
+	resp, err := p.Complete(context.Background(), CompletionRequest{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || moderator.calls != 0 {
+		t.Errorf("tool calls = %d, moderation calls = %d, want 1 and 0", len(resp.ToolCalls), moderator.calls)
+	}
+}
+
+func TestGuard_StreamModeratesIncrementally(t *testing.T) {
+	pieces := make([]string, 50)
+	for i := range pieces {
+		pieces[i] = "0123456789"
+	}
+	// 关键词跨两个审核段拆开，需要依靠重叠窗口检出
+	pieces[len(pieces)-1] = "012345678b"
+	pieces = append(pieces, "ad")
+	mock := &streamingMock{pieces: pieces}
+	mock.name = "test"
+	moderator := &keywordModerator{keyword: "bad"}
+	p := Chain(mock, WithGuard(GuardConfig{
+		Moderator:      moderator,
+		SkipInput:      true,
+		StreamInterval: 10,
+		StreamOverlap:  4,
+	}))
+
+	stream, err := p.Stream(context.Background(), CompletionRequest{Messages: []Message{UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	if _, err := stream.Collect(); !errors.Is(err, ErrContentFlagged) {
+		t.Fatalf("Collect err = %v, want ErrContentFlagged", err)
+	}
+
+	total := 0
+	for _, input := range moderator.inputs {
+		if len(input) > 10+4 {
+			t.Errorf("moderated segment of %d bytes, want at most 14", len(input))
+		}
+		total += len(input)
+	}
+	if total > 51*14 {
+		t.Errorf("moderated %d bytes in total for 502 bytes of output", total)
+	}
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxImageBytes 下载图片的默认大小上限（20MB）
+const DefaultMaxImageBytes = 20 << 20
+
+// ErrImageTooLarge 表示下载的图片超过大小上限
+var ErrImageTooLarge = errors.New("llm: image exceeds size limit")
+
+// Download 下载所有仅有 URL 的图片到 Bytes
+//
+// 厂商返回的图片 URL 通常在数小时内过期，需要持久化时应在拿到响应后立即下载。
+// 已有 Bytes 的图片会被跳过。client 为 nil 时使用 http.DefaultClient，
+// maxBytes <= 0 时使用 DefaultMaxImageBytes。
+func (r *ImageResponse) Download(ctx context.Context, client *http.Client, maxBytes int64) error {
+	for i := range r.Data {
+		img := &r.Data[i]
+		if len(img.Bytes) > 0 || img.URL == "" {
+			continue
+		}
+		data, err := FetchImage(ctx, client, img.URL, maxBytes)
+		if err != nil {
+			return fmt.Errorf("download image %d: %w", i, err)
+		}
+		img.Bytes = data
+	}
+	return nil
+}
+
+// FetchImage 下载单张图片，超过 maxBytes 时返回 ErrImageTooLarge
+//
+// client 为 nil 时使用 http.DefaultClient，maxBytes <= 0 时使用 DefaultMaxImageBytes。
+func FetchImage(ctx context.Context, client *http.Client, url string, maxBytes int64) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxImageBytes
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image download error: %s", resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("%w: %d > %d bytes", ErrImageTooLarge, resp.ContentLength, maxBytes)
+	}
+
+	// 多读 1 字节用于判断是否超限（ContentLength 可能缺失）
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrImageTooLarge, maxBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImageResponse_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/big" {
+			io.WriteString(w, strings.Repeat("x", 64))
+			return
+		}
+		io.WriteString(w, "png")
+	}))
+	defer srv.Close()
+
+	resp := &ImageResponse{Data: []ImageData{{URL: srv.URL + "/small"}, {Bytes: []byte("kept")}}}
+	if err := resp.Download(context.Background(), srv.Client(), 16); err != nil {
+		t.Fatalf("Download error: %v", err)
+	}
+	if string(resp.Data[0].Bytes) != "png" || string(resp.Data[1].Bytes) != "kept" {
+		t.Errorf("unexpected bytes: %q %q", resp.Data[0].Bytes, resp.Data[1].Bytes)
+	}
+
+	if _, err := FetchImage(context.Background(), srv.Client(), srv.URL+"/big", 16); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("err = %v, want ErrImageTooLarge", err)
+	}
+}
@@ -70,6 +70,21 @@ func Chain(provider Provider, middlewares ...Middleware) Provider {
 	return provider
 }
 
+// Unwrap 返回中间件包装的下一层 Provider，p 不是中间件时返回 nil
+//
+// 中间件只转发 Provider 接口的方法，ModelLister、EmbeddingProvider 等可选能力
+// 需要逐层 Unwrap 到原始 Provider 上做类型断言：
+//
+//	for p := provider; p != nil; p = llm.Unwrap(p) {
+//	    if lister, ok := p.(llm.ModelLister); ok { ... }
+//	}
+func Unwrap(p Provider) Provider {
+	if u, ok := p.(interface{ Unwrap() Provider }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
 // ============== 重试中间件 ==============
 
 // WithRetry 创建重试中间件
@@ -97,7 +112,8 @@ type retryProvider struct {
 	backoff    time.Duration
 }
 
-func (p *retryProvider) Name() string { return p.inner.Name() }
+func (p *retryProvider) Name() string     { return p.inner.Name() }
+func (p *retryProvider) Unwrap() Provider { return p.inner }
 func (p *retryProvider) Models() []ModelInfo {
 	return p.inner.Models()
 }
@@ -165,7 +181,8 @@ type rateLimitProvider struct {
 	mu       sync.Mutex
 }
 
-func (p *rateLimitProvider) Name() string { return p.inner.Name() }
+func (p *rateLimitProvider) Name() string     { return p.inner.Name() }
+func (p *rateLimitProvider) Unwrap() Provider { return p.inner }
 func (p *rateLimitProvider) Models() []ModelInfo {
 	return p.inner.Models()
 }
@@ -240,7 +257,8 @@ type timeoutProvider struct {
 	timeout time.Duration
 }
 
-func (p *timeoutProvider) Name() string { return p.inner.Name() }
+func (p *timeoutProvider) Name() string     { return p.inner.Name() }
+func (p *timeoutProvider) Unwrap() Provider { return p.inner }
 func (p *timeoutProvider) Models() []ModelInfo {
 	return p.inner.Models()
 }
@@ -285,7 +303,8 @@ type callbackProvider struct {
 	callback Callback
 }
 
-func (p *callbackProvider) Name() string { return p.inner.Name() }
+func (p *callbackProvider) Name() string     { return p.inner.Name() }
+func (p *callbackProvider) Unwrap() Provider { return p.inner }
 func (p *callbackProvider) Models() []ModelInfo {
 	return p.inner.Models()
 }
@@ -398,7 +417,8 @@ type cacheProvider struct {
 	sf    singleflight.Group
 }
 
-func (p *cacheProvider) Name() string { return p.inner.Name() }
+func (p *cacheProvider) Name() string     { return p.inner.Name() }
+func (p *cacheProvider) Unwrap() Provider { return p.inner }
 func (p *cacheProvider) Models() []ModelInfo {
 	return p.inner.Models()
 }
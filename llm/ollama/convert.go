@@ -0,0 +1,122 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// convertMessages 将通用消息转换为 Ollama /api/chat 格式
+//   - MultiContent 中的文本拼接为 content，图片放入 images（base64，不含 data URI 前缀）
+//   - assistant 的 ToolCalls 转为 tool_calls，arguments 为 JSON 对象
+//   - tool 消息通过 tool_name 关联工具（按 ToolCallID 查找对应调用的函数名）
+func convertMessages(history []llm.Message) ([]map[string]any, error) {
+	toolNames := make(map[string]string)
+	messages := make([]map[string]any, 0, len(history))
+	for _, msg := range history {
+		m := map[string]any{
+			"role":    string(msg.Role),
+			"content": msg.Content,
+		}
+
+		if len(msg.MultiContent) > 0 {
+			var (
+				texts  []string
+				images []string
+			)
+			for _, part := range msg.MultiContent {
+				switch part.Type {
+				case "text":
+					texts = append(texts, part.Text)
+				case "image_url":
+					if part.ImageURL == nil {
+						continue
+					}
+					image, err := imageData(part.ImageURL.URL)
+					if err != nil {
+						return nil, err
+					}
+					images = append(images, image)
+				}
+			}
+			m["content"] = strings.Join(texts, "\n")
+			if len(images) > 0 {
+				m["images"] = images
+			}
+		}
+
+		switch msg.Role {
+		case llm.RoleAssistant:
+			if msg.Reasoning != "" {
+				m["thinking"] = msg.Reasoning
+			}
+			if len(msg.ToolCalls) > 0 {
+				calls := make([]map[string]any, len(msg.ToolCalls))
+				for i, tc := range msg.ToolCalls {
+					args := map[string]any{}
+					if strings.TrimSpace(tc.Arguments) != "" {
+						if err := json.Unmarshal([]byte(tc.Arguments), &args); err != nil {
+							return nil, fmt.Errorf("ollama: tool call %s has invalid arguments: %w", tc.Name, err)
+						}
+					}
+					toolNames[tc.ID] = tc.Name
+					calls[i] = map[string]any{
+						"function": map[string]any{"name": tc.Name, "arguments": args},
+					}
+				}
+				m["tool_calls"] = calls
+			}
+		case llm.RoleTool:
+			name := msg.Name
+			if name == "" {
+				name = toolNames[msg.ToolCallID]
+			}
+			if name != "" {
+				m["tool_name"] = name
+			}
+		}
+
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
+
+// imageData 将图片地址转换为 Ollama 所需的 base64 数据
+// Ollama 不拉取远程图片，仅支持 data URI
+func imageData(url string) (string, error) {
+	if !strings.HasPrefix(url, "data:") {
+		return "", &llm.UnsupportedError{Provider: "ollama", Feature: "remote image URLs (use base64 data URIs)"}
+	}
+	_, data, ok := strings.Cut(url, ",")
+	if !ok {
+		return "", fmt.Errorf("ollama: invalid data URI")
+	}
+	return data, nil
+}
+
+// convertTools 将工具定义转换为 Ollama 格式
+// Ollama 要求 parameters 为 object 类型的 Schema，缺省时补全为空对象
+func convertTools(tools []llm.ToolDefinition) []map[string]any {
+	result := make([]map[string]any, len(tools))
+	for i, t := range tools {
+		var params any = map[string]any{"type": "object", "properties": map[string]any{}}
+		if t.Function.Parameters != nil {
+			params = t.Function.Parameters
+		}
+		typ := t.Type
+		if typ == "" {
+			typ = "function"
+		}
+		result[i] = map[string]any{
+			"type": typ,
+			"function": map[string]any{
+				"name":        t.Function.Name,
+				"description": t.Function.Description,
+				"parameters":  params,
+			},
+		}
+	}
+	return result
+}
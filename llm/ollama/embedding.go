@@ -52,11 +52,47 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 	if len(texts) == 0 {
 		return nil, nil
 	}
+	resp, err := p.CreateEmbeddings(ctx, llm.EmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// CreateEmbeddings 按完整请求参数生成嵌入
+//
+// /api/embed 无输入条数上限，仅在设置 BatchSize 时分批；
+// Dimensions 映射为 dimensions（需模型支持截断维度，如 qwen3-embedding），
+// 用量取自 prompt_eval_count。Ollama 不区分输入类型且仅返回 float 向量，InputType 与 Base64 被忽略。
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultEmbeddingModel
+	}
+	if len(req.Input) == 0 {
+		return &llm.EmbedResponse{Model: req.Model}, nil
+	}
+	return llm.EmbedBatches(ctx, req, 0, func(ctx context.Context, input []string) (*llm.EmbedResponse, error) {
+		return p.embedBatch(ctx, req, input)
+	})
+}
 
+// embedBatch 发送单批 /api/embed 请求
+func (p *Provider) embedBatch(ctx context.Context, req llm.EmbedRequest, input []string) (*llm.EmbedResponse, error) {
 	// Ollama /api/embed 支持批量输入
 	payload := map[string]any{
-		"model": model,
-		"input": texts,
+		"model": req.Model,
+		"input": input,
+	}
+	if req.Dimensions > 0 {
+		payload["dimensions"] = req.Dimensions
+	}
+	if p.keepAlive != "" {
+		if _, ok := req.ExtraBody["keep_alive"]; !ok {
+			payload["keep_alive"] = p.keepAlive
+		}
+	}
+	if err := llm.MergeExtraBody(payload, req.ExtraBody); err != nil {
+		return nil, err
 	}
 
 	body, err := json.Marshal(payload)
@@ -99,16 +135,24 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 		embeddings[i] = vec
 	}
 
-	return embeddings, nil
+	return &llm.EmbedResponse{
+		Model:      result.Model,
+		Embeddings: embeddings,
+		Usage: llm.Usage{
+			PromptTokens: result.PromptEvalCount,
+			TotalTokens:  result.PromptEvalCount,
+		},
+	}, nil
 }
 
 // ollamaEmbedResponse Ollama /api/embed 响应结构
 //
 // Ollama 返回 float64 类型的向量，需要转换为 float32
 type ollamaEmbedResponse struct {
-	Model      string      `json:"model"`
-	Embeddings [][]float64 `json:"embeddings"`
+	Model           string      `json:"model"`
+	Embeddings      [][]float64 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
 }
 
 // 确保实现了 EmbeddingProvider 接口
-var _ llm.EmbeddingProvider = (*Provider)(nil)
+var _ llm.EmbedRequestProvider = (*Provider)(nil)
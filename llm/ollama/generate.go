@@ -0,0 +1,207 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// CompleteText 调用 /api/generate 执行文本补全
+//
+// 未设置 Suffix 时使用 raw 模式（不套用模型的对话模板），提示词原样续写；
+// 设置 Suffix 时由模型模板完成 FIM 拼接，需模型支持 suffix（如 qwen2.5-coder、codellama:code）。
+// Ollama 不支持 echo，Echo 为 true 时在结果前拼接提示词。
+func (p *Provider) CompleteText(ctx context.Context, req llm.TextCompletionRequest) (*llm.TextCompletionResponse, error) {
+	resp, err := p.postGenerate(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result generateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("ollama api error: %s", result.Error)
+	}
+
+	text := result.Response
+	if req.Echo {
+		text = req.Prompt + text
+	}
+	return &llm.TextCompletionResponse{
+		ID:           result.CreatedAt,
+		Model:        result.Model,
+		Text:         text,
+		FinishReason: finishReason(result.DoneReason, false),
+		Usage:        result.usage(),
+	}, nil
+}
+
+// StreamText 调用 /api/generate 执行流式文本补全
+func (p *Provider) StreamText(ctx context.Context, req llm.TextCompletionRequest) (*streamx.Stream, error) {
+	resp, err := p.postGenerate(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return streamx.NewChunkStream(ctx, func(emit func(*streamx.Chunk) error) error {
+		defer resp.Body.Close()
+		if req.Echo && req.Prompt != "" {
+			if err := emit(&streamx.Chunk{Model: req.Model, Content: req.Prompt}); err != nil {
+				return err
+			}
+		}
+		return readGenerateStream(resp.Body, emit)
+	}), nil
+}
+
+// postGenerate 发送 /api/generate 请求，非 2xx 时读取响应体并返回错误
+func (p *Provider) postGenerate(ctx context.Context, req llm.TextCompletionRequest, stream bool) (*http.Response, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+
+	body, err := p.buildGenerateBody(req, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("ollama api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("ollama api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+	return resp, nil
+}
+
+// buildGenerateBody 构建 /api/generate 请求体
+func (p *Provider) buildGenerateBody(req llm.TextCompletionRequest, stream bool) ([]byte, error) {
+	payload := map[string]any{
+		"model":  req.Model,
+		"prompt": req.Prompt,
+		"stream": stream,
+	}
+	if req.Suffix != "" {
+		payload["suffix"] = req.Suffix
+	} else if _, ok := req.ExtraBody["raw"]; !ok {
+		payload["raw"] = true
+	}
+	if p.keepAlive != "" {
+		if _, ok := req.ExtraBody["keep_alive"]; !ok {
+			payload["keep_alive"] = p.keepAlive
+		}
+	}
+
+	explicit := make(map[string]any)
+	if req.Temperature != nil {
+		explicit["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		explicit["top_p"] = *req.TopP
+	}
+	if req.MaxTokens > 0 {
+		explicit["num_predict"] = req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		explicit["stop"] = req.Stop
+	}
+	if req.Seed != nil {
+		explicit["seed"] = *req.Seed
+	}
+
+	options, err := p.buildOptions(explicit, req.ExtraBody["options"])
+	if err != nil {
+		return nil, err
+	}
+	if len(options) > 0 {
+		payload["options"] = options
+	}
+
+	if err := llm.MergeExtraBody(payload, withoutOptions(req.ExtraBody)); err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+// generateResponse /api/generate 响应结构（流式时为每行一个对象）
+type generateResponse struct {
+	Model           string `json:"model"`
+	CreatedAt       string `json:"created_at"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason,omitempty"`
+	Error           string `json:"error,omitempty"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+}
+
+// usage 返回用量统计（仅最后一条响应有值）
+func (r *generateResponse) usage() llm.Usage {
+	return llm.Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// readGenerateStream 逐行解析 /api/generate 的 NDJSON 流式响应
+func readGenerateStream(body io.Reader, emit func(*streamx.Chunk) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var r generateResponse
+		if err := json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("ollama stream decode failed: %w", err)
+		}
+		if r.Error != "" {
+			return fmt.Errorf("ollama stream error: %s", r.Error)
+		}
+
+		chunk := &streamx.Chunk{
+			ID:      r.CreatedAt,
+			Model:   r.Model,
+			Content: r.Response,
+		}
+		if r.Done {
+			chunk.FinishReason = finishReason(r.DoneReason, false)
+			usage := r.usage()
+			chunk.Usage = &usage
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+		if r.Done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// 确保实现了 CompletionTextProvider 接口
+var _ llm.CompletionTextProvider = (*Provider)(nil)
@@ -0,0 +1,73 @@
+package ollama
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestCompleteText_Raw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["raw"] != true || body["suffix"] != nil || body["stream"] != false {
+			t.Errorf("unexpected body: %v", body)
+		}
+		options := body["options"].(map[string]any)
+		if options["num_predict"] != float64(32) || options["stop"] == nil {
+			t.Errorf("unexpected options: %v", options)
+		}
+		io.WriteString(w, `{"model":"codellama","created_at":"t1","response":" world","done":true,"done_reason":"length","prompt_eval_count":3,"eval_count":2}`)
+	}))
+	defer srv.Close()
+
+	p := New(WithBaseURL(srv.URL))
+	resp, err := p.CompleteText(t.Context(), llm.TextCompletionRequest{
+		Model:     "codellama",
+		Prompt:    "hello",
+		Echo:      true,
+		Stop:      []string{"\n"},
+		MaxTokens: 32,
+	})
+	if err != nil {
+		t.Fatalf("CompleteText error: %v", err)
+	}
+	if resp.Text != "hello world" || resp.FinishReason != "length" || resp.Usage.TotalTokens != 5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestStreamText_Suffix(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["suffix"] != "\n}" || body["raw"] != nil {
+			t.Errorf("unexpected body: %v", body)
+		}
+		io.WriteString(w, `{"model":"qwen2.5-coder","response":"return ","done":false}
+{"model":"qwen2.5-coder","response":"x","done":false}
+{"model":"qwen2.5-coder","response":"","done":true,"done_reason":"stop","prompt_eval_count":7,"eval_count":2}
+`)
+	}))
+	defer srv.Close()
+
+	p := New(WithBaseURL(srv.URL))
+	stream, err := p.StreamText(t.Context(), llm.TextCompletionRequest{Model: "qwen2.5-coder", Prompt: "func f() int {\n", Suffix: "\n}"})
+	if err != nil {
+		t.Fatalf("StreamText error: %v", err)
+	}
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.Content != "return x" || result.FinishReason != "stop" || result.Usage.TotalTokens != 9 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
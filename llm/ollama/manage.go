@@ -0,0 +1,458 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ModelDetails 模型的格式与规格信息
+type ModelDetails struct {
+	ParentModel       string   `json:"parent_model,omitempty"`
+	Format            string   `json:"format,omitempty"`
+	Family            string   `json:"family,omitempty"`
+	Families          []string `json:"families,omitempty"`
+	ParameterSize     string   `json:"parameter_size,omitempty"`
+	QuantizationLevel string   `json:"quantization_level,omitempty"`
+}
+
+// LocalModel 本地已拉取的模型（/api/tags）
+type LocalModel struct {
+	Name       string       `json:"name"`
+	Model      string       `json:"model"`
+	Size       int64        `json:"size"`
+	Digest     string       `json:"digest"`
+	ModifiedAt time.Time    `json:"modified_at"`
+	Details    ModelDetails `json:"details"`
+}
+
+// ModelDetail 模型详情（/api/show）
+type ModelDetail struct {
+	Modelfile    string         `json:"modelfile"`
+	Parameters   string         `json:"parameters"`
+	Template     string         `json:"template"`
+	System       string         `json:"system"`
+	License      string         `json:"license"`
+	Details      ModelDetails   `json:"details"`
+	ModelInfo    map[string]any `json:"model_info"`
+	Capabilities []string       `json:"capabilities"`
+	ModifiedAt   time.Time      `json:"modified_at"`
+}
+
+// ContextLength 返回模型训练时的上下文长度，未知时返回 0
+// 取自 model_info 中的 "<architecture>.context_length"
+func (d *ModelDetail) ContextLength() int {
+	arch, _ := d.ModelInfo["general.architecture"].(string)
+	if v, ok := d.ModelInfo[arch+".context_length"].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// HasCapability 检查模型是否具备指定能力（如 "completion"、"tools"、"vision"、"thinking"、"embedding"）
+func (d *ModelDetail) HasCapability(capability string) bool {
+	for _, c := range d.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// RunningModel 已加载到内存中的模型（/api/ps）
+type RunningModel struct {
+	Name          string       `json:"name"`
+	Model         string       `json:"model"`
+	Size          int64        `json:"size"`
+	SizeVRAM      int64        `json:"size_vram"`
+	Digest        string       `json:"digest"`
+	Details       ModelDetails `json:"details"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	ContextLength int          `json:"context_length,omitempty"`
+}
+
+// Progress 拉取、创建模型时的进度
+// Total/Completed 仅在下载镜像层时有值
+type Progress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+}
+
+// Percent 返回当前镜像层的下载百分比（0-100），无下载信息时返回 0
+func (pr Progress) Percent() float64 {
+	if pr.Total <= 0 {
+		return 0
+	}
+	return float64(pr.Completed) / float64(pr.Total) * 100
+}
+
+// ProgressFunc 接收进度回调，按服务端推送顺序在调用方 goroutine 中执行
+type ProgressFunc func(Progress)
+
+// CreateModelRequest 创建模型请求
+//
+// 可以直接填写 From/System/Template/Parameters，也可以提供 Modelfile 文本，
+// Modelfile 中的指令会被解析并补全未填写的字段。
+type CreateModelRequest struct {
+	// Model 新模型名称
+	Model string
+
+	// From 基础模型，须为服务端已存在的模型名（本地 GGUF/Safetensors 文件需先上传为 blob，暂不支持）
+	From string
+
+	// System 系统提示词
+	System string
+
+	// Template 提示词模板
+	Template string
+
+	// Parameters 模型参数（如 temperature、num_ctx、stop）
+	Parameters map[string]any
+
+	// Quantize 量化类型（如 "q4_K_M"），仅对 fp16/fp32 基础模型有效
+	Quantize string
+
+	// Adapters LoRA 适配器：文件名 → 已上传 blob 的摘要（"sha256:..."）
+	Adapters map[string]string
+
+	// Messages 预置的对话历史
+	Messages []CreateModelMessage
+
+	// License 许可证文本
+	License []string
+
+	// Modelfile Modelfile 文本（支持 FROM、SYSTEM、TEMPLATE、PARAMETER、MESSAGE、LICENSE 指令）
+	// FROM 指向本地文件与 ADAPTER 指令需要上传 blob，会返回错误，请改用 From 模型名与 Adapters
+	Modelfile string
+}
+
+// CreateModelMessage 创建模型时预置的对话消息
+type CreateModelMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ListLocalModels 列出本地已拉取的模型
+func (p *Provider) ListLocalModels(ctx context.Context) ([]LocalModel, error) {
+	var result struct {
+		Models []LocalModel `json:"models"`
+	}
+	if err := p.call(ctx, "GET", "/api/tags", nil, &result); err != nil {
+		return nil, fmt.Errorf("list models failed: %w", err)
+	}
+	return result.Models, nil
+}
+
+// ShowModel 获取模型详情（上下文长度、模型族、量化级别、能力等）
+func (p *Provider) ShowModel(ctx context.Context, model string) (*ModelDetail, error) {
+	var detail ModelDetail
+	if err := p.call(ctx, "POST", "/api/show", map[string]any{"model": model}, &detail); err != nil {
+		return nil, fmt.Errorf("show model failed: %w", err)
+	}
+	return &detail, nil
+}
+
+// ListRunningModels 列出已加载到内存中的模型
+func (p *Provider) ListRunningModels(ctx context.Context) ([]RunningModel, error) {
+	var result struct {
+		Models []RunningModel `json:"models"`
+	}
+	if err := p.call(ctx, "GET", "/api/ps", nil, &result); err != nil {
+		return nil, fmt.Errorf("list running models failed: %w", err)
+	}
+	return result.Models, nil
+}
+
+// PullModel 拉取模型
+// progress 非 nil 时以流式方式拉取并回调下载进度
+func (p *Provider) PullModel(ctx context.Context, model string, progress ProgressFunc) error {
+	payload := map[string]any{"model": model}
+	if err := p.stream(ctx, "/api/pull", payload, progress); err != nil {
+		return fmt.Errorf("pull model failed: %w", err)
+	}
+	p.catalog.Invalidate()
+	return nil
+}
+
+// CreateModel 基于已有模型或 Modelfile 创建新模型
+// progress 非 nil 时回调创建进度
+func (p *Provider) CreateModel(ctx context.Context, req CreateModelRequest, progress ProgressFunc) error {
+	if req.Modelfile != "" {
+		if err := applyModelfile(&req, req.Modelfile); err != nil {
+			return fmt.Errorf("create model failed: %w", err)
+		}
+	}
+	if req.Model == "" || req.From == "" {
+		return fmt.Errorf("create model failed: model and from are required")
+	}
+
+	payload := map[string]any{"model": req.Model, "from": req.From}
+	if req.System != "" {
+		payload["system"] = req.System
+	}
+	if req.Template != "" {
+		payload["template"] = req.Template
+	}
+	if len(req.Parameters) > 0 {
+		payload["parameters"] = req.Parameters
+	}
+	if req.Quantize != "" {
+		payload["quantize"] = req.Quantize
+	}
+	if len(req.Adapters) > 0 {
+		payload["adapters"] = req.Adapters
+	}
+	if len(req.Messages) > 0 {
+		payload["messages"] = req.Messages
+	}
+	if len(req.License) > 0 {
+		payload["license"] = req.License
+	}
+	if err := p.stream(ctx, "/api/create", payload, progress); err != nil {
+		return fmt.Errorf("create model failed: %w", err)
+	}
+	p.catalog.Invalidate()
+	return nil
+}
+
+// CopyModel 复制模型（创建同一模型的新名称）
+func (p *Provider) CopyModel(ctx context.Context, source, destination string) error {
+	payload := map[string]any{"source": source, "destination": destination}
+	if err := p.call(ctx, "POST", "/api/copy", payload, nil); err != nil {
+		return fmt.Errorf("copy model failed: %w", err)
+	}
+	p.catalog.Invalidate()
+	return nil
+}
+
+// DeleteModel 删除本地模型
+func (p *Provider) DeleteModel(ctx context.Context, model string) error {
+	if err := p.call(ctx, "DELETE", "/api/delete", map[string]any{"model": model}, nil); err != nil {
+		return fmt.Errorf("delete model failed: %w", err)
+	}
+	p.catalog.Invalidate()
+	return nil
+}
+
+// UnloadModel 立即从内存中卸载模型（keep_alive=0）
+func (p *Provider) UnloadModel(ctx context.Context, model string) error {
+	payload := map[string]any{"model": model, "keep_alive": 0}
+	if err := p.call(ctx, "POST", "/api/generate", payload, nil); err != nil {
+		return fmt.Errorf("unload model failed: %w", err)
+	}
+	return nil
+}
+
+// call 发送管理接口请求，out 非 nil 时解析响应
+func (p *Provider) call(ctx context.Context, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// stream 发送流式管理请求并逐行解析 NDJSON 进度
+// progress 为 nil 时使用非流式请求
+func (p *Provider) stream(ctx context.Context, path string, payload map[string]any, progress ProgressFunc) error {
+	payload["stream"] = progress != nil
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var event struct {
+			Progress
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("decode progress: %w", err)
+		}
+		// 流式过程中的错误以 {"error": "..."} 返回，状态码仍为 200
+		if event.Error != "" {
+			return fmt.Errorf("%s", event.Error)
+		}
+		if progress != nil {
+			progress(event.Progress)
+		}
+	}
+	return scanner.Err()
+}
+
+// apiError 读取 Ollama 的错误响应 {"error": "..."}
+func apiError(resp *http.Response) error {
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s (failed to read body: %v)", resp.Status, err)
+	}
+	var e struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(bodyBytes, &e) == nil && e.Error != "" {
+		return fmt.Errorf("%s: %s", resp.Status, e.Error)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, string(bodyBytes))
+}
+
+// applyModelfile 解析 Modelfile 并补全请求中未填写的字段
+// 支持单行指令与 """ 包裹的多行值
+func applyModelfile(req *CreateModelRequest, modelfile string) error {
+	lines := strings.Split(modelfile, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		command, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+
+		// 多行值："""...""" 可跨越多行
+		if strings.HasPrefix(args, `"""`) {
+			value := strings.TrimPrefix(args, `"""`)
+			for !strings.HasSuffix(value, `"""`) {
+				i++
+				if i >= len(lines) {
+					return fmt.Errorf("modelfile: unterminated \"\"\" in %s", command)
+				}
+				value += "\n" + lines[i]
+			}
+			args = strings.TrimSuffix(value, `"""`)
+		} else if len(args) >= 2 && strings.HasPrefix(args, `"`) && strings.HasSuffix(args, `"`) {
+			args = args[1 : len(args)-1]
+		}
+
+		switch strings.ToUpper(command) {
+		case "FROM":
+			if req.From == "" {
+				if isLocalModelPath(args) {
+					return fmt.Errorf("modelfile: FROM %q refers to a local file, which must be uploaded as a blob; use an existing model name", args)
+				}
+				req.From = args
+			}
+		case "SYSTEM":
+			if req.System == "" {
+				req.System = args
+			}
+		case "TEMPLATE":
+			if req.Template == "" {
+				req.Template = args
+			}
+		case "PARAMETER":
+			name, value, ok := strings.Cut(args, " ")
+			if !ok {
+				return fmt.Errorf("modelfile: invalid PARAMETER %q", args)
+			}
+			setParameter(req, name, strings.Trim(strings.TrimSpace(value), `"`))
+		case "LICENSE":
+			req.License = append(req.License, args)
+		case "MESSAGE":
+			role, content, ok := strings.Cut(args, " ")
+			switch role {
+			case "system", "user", "assistant":
+			default:
+				ok = false
+			}
+			if !ok {
+				return fmt.Errorf("modelfile: invalid MESSAGE %q", args)
+			}
+			req.Messages = append(req.Messages, CreateModelMessage{Role: role, Content: strings.TrimSpace(content)})
+		case "ADAPTER":
+			return fmt.Errorf("modelfile: ADAPTER requires uploading the adapter as a blob; set CreateModelRequest.Adapters instead")
+		default:
+			return fmt.Errorf("modelfile: unknown command %q", command)
+		}
+	}
+	return nil
+}
+
+// isLocalModelPath 判断 FROM 的值是否为本地文件或目录路径
+func isLocalModelPath(from string) bool {
+	lower := strings.ToLower(from)
+	return strings.HasPrefix(from, "/") || strings.HasPrefix(from, "./") || strings.HasPrefix(from, "../") ||
+		strings.HasPrefix(from, "~") || strings.HasSuffix(lower, ".gguf") || strings.HasSuffix(lower, ".safetensors")
+}
+
+// setParameter 写入模型参数，已显式设置的参数不覆盖
+// stop 可重复出现，累积为列表；数值参数按 JSON 数字解析
+func setParameter(req *CreateModelRequest, name, value string) {
+	if req.Parameters == nil {
+		req.Parameters = make(map[string]any)
+	}
+	if name == "stop" {
+		stops, _ := req.Parameters["stop"].([]string)
+		req.Parameters["stop"] = append(stops, value)
+		return
+	}
+	if _, exists := req.Parameters[name]; exists {
+		return
+	}
+	var num json.Number
+	if err := json.Unmarshal([]byte(value), &num); err == nil {
+		if n, err := num.Int64(); err == nil {
+			req.Parameters[name] = n
+			return
+		}
+		if f, err := num.Float64(); err == nil {
+			req.Parameters[name] = f
+			return
+		}
+	}
+	req.Parameters[name] = value
+}
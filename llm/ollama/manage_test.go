@@ -0,0 +1,146 @@
+package ollama
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPullModelProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true || body["model"] != "llama3.2" {
+			t.Errorf("body = %v", body)
+		}
+		io.WriteString(w, `{"status":"pulling manifest"}
+{"status":"downloading","digest":"sha256:a","total":200,"completed":50}
+{"status":"downloading","digest":"sha256:a","total":200,"completed":200}
+{"status":"success"}
+`)
+	}))
+	defer srv.Close()
+
+	var events []Progress
+	p := New(WithBaseURL(srv.URL))
+	if err := p.PullModel(t.Context(), "llama3.2", func(pr Progress) { events = append(events, pr) }); err != nil {
+		t.Fatalf("PullModel error: %v", err)
+	}
+	if len(events) != 4 || events[1].Percent() != 25 || events[3].Status != "success" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestPullModelStreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status":"pulling manifest"}
+{"error":"pull model manifest: file does not exist"}
+`)
+	}))
+	defer srv.Close()
+
+	err := New(WithBaseURL(srv.URL)).PullModel(t.Context(), "nope", func(Progress) {})
+	if err == nil || !strings.Contains(err.Error(), "file does not exist") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestShowModel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"details":{"family":"llama","families":["llama"],"parameter_size":"3.2B","quantization_level":"Q4_K_M"},
+"model_info":{"general.architecture":"llama","llama.context_length":131072},"capabilities":["completion","tools"]}`)
+	}))
+	defer srv.Close()
+
+	d, err := New(WithBaseURL(srv.URL)).ShowModel(t.Context(), "llama3.2")
+	if err != nil {
+		t.Fatalf("ShowModel error: %v", err)
+	}
+	if d.ContextLength() != 131072 || !d.HasCapability("tools") || d.HasCapability("vision") || d.Details.QuantizationLevel != "Q4_K_M" {
+		t.Errorf("detail = %+v", d)
+	}
+}
+
+func TestManageRequests(t *testing.T) {
+	var got []string
+	bodies := map[string]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/api/delete":
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"model 'x' not found"}`)
+		case "/api/ps":
+			io.WriteString(w, `{"models":[{"name":"llama3.2:latest","size_vram":2000,"context_length":4096}]}`)
+		case "/api/create":
+			io.WriteString(w, `{"status":"success"}`)
+		default:
+			io.WriteString(w, `{}`)
+		}
+	}))
+	defer srv.Close()
+	p := New(WithBaseURL(srv.URL))
+	ctx := t.Context()
+
+	if err := p.CopyModel(ctx, "llama3.2", "backup"); err != nil {
+		t.Errorf("CopyModel error: %v", err)
+	}
+	if err := p.DeleteModel(ctx, "x"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("DeleteModel error = %v", err)
+	}
+	if err := p.UnloadModel(ctx, "llama3.2"); err != nil || bodies["/api/generate"]["keep_alive"] != float64(0) {
+		t.Errorf("UnloadModel err = %v, body = %v", err, bodies["/api/generate"])
+	}
+	running, err := p.ListRunningModels(ctx)
+	if err != nil || len(running) != 1 || running[0].ContextLength != 4096 {
+		t.Errorf("running = %+v, err = %v", running, err)
+	}
+
+	err = p.CreateModel(ctx, CreateModelRequest{
+		Model: "mario",
+		Modelfile: `FROM llama3.2
+# comment
+PARAMETER temperature 0.7
+PARAMETER num_ctx 8192
+PARAMETER stop "<|end|>"
+PARAMETER stop "<|user|>"
+SYSTEM """
+You are Mario.
+"""
+MESSAGE user Who are you?
+MESSAGE assistant It's-a me, Mario!
+LICENSE MIT`,
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateModel error: %v", err)
+	}
+	body := bodies["/api/create"]
+	params, _ := body["parameters"].(map[string]any)
+	if body["from"] != "llama3.2" || body["system"] != "\nYou are Mario.\n" || body["stream"] != false ||
+		params["temperature"] != 0.7 || params["num_ctx"] != float64(8192) || len(params["stop"].([]any)) != 2 {
+		t.Errorf("create body = %v", body)
+	}
+	if msgs, _ := body["messages"].([]any); len(msgs) != 2 || msgs[1].(map[string]any)["content"] != "It's-a me, Mario!" {
+		t.Errorf("create messages = %v", body["messages"])
+	}
+	if license, _ := body["license"].([]any); len(license) != 1 || license[0] != "MIT" {
+		t.Errorf("create license = %v", body["license"])
+	}
+
+	for _, modelfile := range []string{"FROM ./mario.gguf", "FROM llama3.2\nADAPTER ./lora.gguf", "FROM llama3.2\nMESSAGE tool hi"} {
+		if err := p.CreateModel(ctx, CreateModelRequest{Model: "bad", Modelfile: modelfile}, nil); err == nil {
+			t.Errorf("CreateModel(%q) should fail", modelfile)
+		}
+	}
+
+	want := []string{"POST /api/copy", "DELETE /api/delete", "POST /api/generate", "GET /api/ps", "POST /api/create"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v", got)
+	}
+}
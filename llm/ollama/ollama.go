@@ -2,6 +2,7 @@
 package ollama
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -27,7 +28,9 @@ type Provider struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
-	models     []llm.ModelInfo // 缓存的模型列表
+	catalog    *llm.ModelCatalog // 本地模型目录（带缓存）
+	options    Options           // 默认运行参数
+	keepAlive  string            // 默认 keep_alive，为空时使用服务端默认值（5 分钟）
 }
 
 // Option 是 Provider 的配置选项
@@ -54,6 +57,22 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithOptions 设置默认运行参数（如 num_ctx、mirostat）
+// 请求中的类型化字段与 ExtraBody["options"] 优先
+func WithOptions(opts Options) Option {
+	return func(p *Provider) {
+		p.options = opts
+	}
+}
+
+// WithKeepAlive 设置模型在最后一次请求后保留在内存中的时长
+// 0 表示生成后立即卸载，负数表示常驻内存
+func WithKeepAlive(d time.Duration) Option {
+	return func(p *Provider) {
+		p.keepAlive = KeepAlive(d)
+	}
+}
+
 // New 创建 Ollama Provider
 func New(opts ...Option) *Provider {
 	p := &Provider{
@@ -76,6 +95,7 @@ func New(opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.catalog = llm.NewModelCatalog(defaultModels, p.fetchLocalModels, time.Minute)
 
 	return p
 }
@@ -87,6 +107,10 @@ func (p *Provider) Name() string {
 
 // Complete 执行非流式补全请求
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req.N > 1 {
+		// 不支持原生 n 参数，通过并发请求模拟
+		return llm.CompleteChoices(ctx, p, req)
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -122,11 +146,18 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 		return nil, err
 	}
 
-	return p.parseResponse(&result, req.Model), nil
+	response := p.parseResponse(&result, req.Model)
+	if req.Reasoning.Excluded() {
+		response.StripReasoning()
+	}
+	return response, nil
 }
 
 // Stream 执行流式补全请求
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	if req.N > 1 {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
+	}
 	if req.Model == "" {
 		req.Model = p.model
 	}
@@ -157,126 +188,116 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 		return nil, fmt.Errorf("ollama api error: %s, body: %s", resp.Status, string(bodyBytes))
 	}
 
-	// Ollama 使用类似 OpenAI 的格式，但需要自定义解析
-	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.OpenAIFormat), nil
+	// Ollama 流式响应为 NDJSON（每行一个 JSON 对象），而非 SSE
+	return streamx.NewChunkStream(ctx, func(emit func(*streamx.Chunk) error) error {
+		defer resp.Body.Close()
+		return p.readStream(resp.Body, req, emit)
+	}), nil
 }
 
 // Models 返回可用模型列表
+// 优先返回本地已拉取的模型（带缓存），Ollama 不可达时返回常见模型的默认列表
 func (p *Provider) Models() []llm.ModelInfo {
-	// 如果已缓存，直接返回
-	if len(p.models) > 0 {
-		return p.models
-	}
-
-	// 尝试从 Ollama 获取本地模型列表
-	models, err := p.fetchLocalModels()
-	if err == nil && len(models) > 0 {
-		p.models = models
-		return p.models
-	}
-
-	// 返回常见模型的默认列表
-	return []llm.ModelInfo{
-		{
-			ID:          "llama3.2",
-			Name:        "Llama 3.2",
-			Description: "Meta's Llama 3.2 model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureStreaming},
-		},
-		{
-			ID:          "llama3.2:1b",
-			Name:        "Llama 3.2 1B",
-			Description: "Meta's Llama 3.2 1B parameter model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureStreaming},
-		},
-		{
-			ID:          "llama3.1",
-			Name:        "Llama 3.1",
-			Description: "Meta's Llama 3.1 model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
-		},
-		{
-			ID:          "qwen2.5",
-			Name:        "Qwen 2.5",
-			Description: "Alibaba's Qwen 2.5 model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
-		},
-		{
-			ID:          "qwen2.5:7b",
-			Name:        "Qwen 2.5 7B",
-			Description: "Alibaba's Qwen 2.5 7B model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
-		},
-		{
-			ID:          "mistral",
-			Name:        "Mistral",
-			Description: "Mistral AI's model",
-			MaxTokens:   32768,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
-		},
-		{
-			ID:          "codellama",
-			Name:        "Code Llama",
-			Description: "Meta's Code Llama model for coding tasks",
-			MaxTokens:   16384,
-			Features:    []string{llm.FeatureStreaming},
-		},
-		{
-			ID:          "deepseek-coder-v2",
-			Name:        "DeepSeek Coder V2",
-			Description: "DeepSeek's coding model",
-			MaxTokens:   128000,
-			Features:    []string{llm.FeatureStreaming},
-		},
-		{
-			ID:          "llava",
-			Name:        "LLaVA",
-			Description: "Vision-language model",
-			MaxTokens:   4096,
-			Features:    []string{llm.FeatureVision, llm.FeatureStreaming},
-		},
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if models, err := p.catalog.List(ctx); err == nil && len(models) > 0 {
+		return models
 	}
+	return p.catalog.Models()
 }
 
-// fetchLocalModels 从 Ollama 获取本地模型列表
-func (p *Provider) fetchLocalModels() ([]llm.ModelInfo, error) {
-	resp, err := p.httpClient.Get(p.baseURL + "/api/tags")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// ListModels 从 Ollama 获取本地模型列表，并与常见模型的元数据合并（带缓存）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.catalog.List(ctx)
+}
 
-	var result struct {
-		Models []struct {
-			Name       string `json:"name"`
-			Size       int64  `json:"size"`
-			ModifiedAt string `json:"modified_at"`
-			Details    struct {
-				Format            string `json:"format"`
-				Family            string `json:"family"`
-				ParameterSize     string `json:"parameter_size"`
-				QuantizationLevel string `json:"quantization_level"`
-			} `json:"details"`
-		} `json:"models"`
-	}
+// defaultModels 常见模型的默认列表与元数据
+var defaultModels = []llm.ModelInfo{
+	{
+		ID:          "llama3.2",
+		Name:        "Llama 3.2",
+		Description: "Meta's Llama 3.2 model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureStreaming},
+	},
+	{
+		ID:          "llama3.2:1b",
+		Name:        "Llama 3.2 1B",
+		Description: "Meta's Llama 3.2 1B parameter model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureStreaming},
+	},
+	{
+		ID:          "llama3.1",
+		Name:        "Llama 3.1",
+		Description: "Meta's Llama 3.1 model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+	},
+	{
+		ID:          "qwen2.5",
+		Name:        "Qwen 2.5",
+		Description: "Alibaba's Qwen 2.5 model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+	},
+	{
+		ID:          "qwen2.5:7b",
+		Name:        "Qwen 2.5 7B",
+		Description: "Alibaba's Qwen 2.5 7B model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+	},
+	{
+		ID:          "qwen3",
+		Name:        "Qwen 3",
+		Description: "Alibaba's Qwen 3 hybrid reasoning model",
+		MaxTokens:   40960,
+		Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming, llm.FeatureReasoning},
+	},
+	{
+		ID:          "mistral",
+		Name:        "Mistral",
+		Description: "Mistral AI's model",
+		MaxTokens:   32768,
+		Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
+	},
+	{
+		ID:          "codellama",
+		Name:        "Code Llama",
+		Description: "Meta's Code Llama model for coding tasks",
+		MaxTokens:   16384,
+		Features:    []string{llm.FeatureStreaming},
+	},
+	{
+		ID:          "deepseek-coder-v2",
+		Name:        "DeepSeek Coder V2",
+		Description: "DeepSeek's coding model",
+		MaxTokens:   128000,
+		Features:    []string{llm.FeatureStreaming},
+	},
+	{
+		ID:          "llava",
+		Name:        "LLaVA",
+		Description: "Vision-language model",
+		MaxTokens:   4096,
+		Features:    []string{llm.FeatureVision, llm.FeatureStreaming},
+	},
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+// fetchLocalModels 从 Ollama 获取本地模型列表
+func (p *Provider) fetchLocalModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	local, err := p.ListLocalModels(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	models := make([]llm.ModelInfo, len(result.Models))
-	for i, m := range result.Models {
+	models := make([]llm.ModelInfo, len(local))
+	for i, m := range local {
 		models[i] = llm.ModelInfo{
 			ID:          m.Name,
 			Name:        m.Name,
 			Description: fmt.Sprintf("%s model (%s)", m.Details.Family, m.Details.ParameterSize),
-			MaxTokens:   32768, // 默认值，Ollama 不提供此信息
-			Features:    []string{llm.FeatureStreaming},
 		}
 	}
 
@@ -295,12 +316,9 @@ func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
 
 // buildRequestBody 构建请求体
 func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]byte, error) {
-	messages := make([]map[string]any, len(req.Messages))
-	for i, msg := range req.Messages {
-		messages[i] = map[string]any{
-			"role":    string(msg.Role),
-			"content": msg.Content,
-		}
+	messages, err := convertMessages(llm.ApplyReasoningPolicy(req.Messages, req.ReasoningHistory))
+	if err != nil {
+		return nil, err
 	}
 
 	payload := map[string]any{
@@ -308,46 +326,103 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 		"messages": messages,
 		"stream":   stream,
 	}
-	if think, ok := ollamaThinkFromMetadata(req.Metadata); ok {
+	if req.Reasoning.Specified() {
+		payload["think"] = req.Reasoning.Enabled()
+	} else if think, ok := ollamaThinkFromMetadata(req.Metadata); ok {
+		// 兼容旧的 Metadata["thinking"] 写法
 		payload["think"] = think
 	}
+	if p.keepAlive != "" {
+		if _, ok := req.ExtraBody["keep_alive"]; !ok {
+			payload["keep_alive"] = p.keepAlive
+		}
+	}
+
+	if req.WantsLogprobs() {
+		payload["logprobs"] = true
+		if req.TopLogprobs > 0 {
+			payload["top_logprobs"] = req.TopLogprobs
+		}
+	}
 
 	// Ollama 使用 options 嵌套参数
-	options := make(map[string]any)
+	explicit := make(map[string]any)
 	if req.Temperature != nil {
-		options["temperature"] = *req.Temperature
+		explicit["temperature"] = *req.Temperature
 	}
 	if req.TopP != nil {
-		options["top_p"] = *req.TopP
+		explicit["top_p"] = *req.TopP
 	}
 	if req.MaxTokens > 0 {
-		options["num_predict"] = req.MaxTokens
+		explicit["num_predict"] = req.MaxTokens
 	}
 	if len(req.Stop) > 0 {
-		options["stop"] = req.Stop
+		explicit["stop"] = req.Stop
+	}
+	if req.Seed != nil {
+		explicit["seed"] = *req.Seed
+	}
+	if req.TopK != nil {
+		explicit["top_k"] = *req.TopK
+	}
+	if req.RepetitionPenalty != nil {
+		explicit["repeat_penalty"] = *req.RepetitionPenalty
+	}
+	if req.PresencePenalty != nil {
+		explicit["presence_penalty"] = *req.PresencePenalty
+	}
+	if req.FrequencyPenalty != nil {
+		explicit["frequency_penalty"] = *req.FrequencyPenalty
 	}
 
+	options, err := p.buildOptions(explicit, req.ExtraBody["options"])
+	if err != nil {
+		return nil, err
+	}
 	if len(options) > 0 {
 		payload["options"] = options
 	}
 
 	// 工具支持（部分模型支持）
 	if len(req.Tools) > 0 {
-		payload["tools"] = req.Tools
+		payload["tools"] = convertTools(req.Tools)
 	}
 
 	// ResponseFormat 支持
-	// Ollama 使用 format 参数指定输出格式
-	if req.ResponseFormat != nil {
-		switch req.ResponseFormat.Type {
-		case "json_object", "json_schema":
+	// Ollama 使用 format 参数指定输出格式："json" 或完整的 JSON Schema
+	if rf := req.ResponseFormat; rf != nil {
+		switch rf.Type {
+		case "json_object":
 			payload["format"] = "json"
+		case "json_schema":
+			if rf.JSONSchema != nil && rf.JSONSchema.Schema != nil {
+				payload["format"] = rf.JSONSchema.Schema
+			} else {
+				payload["format"] = "json"
+			}
 		}
 	}
+	// 约束解码仅支持 JSON Schema，与 ResponseFormat 同时设置时以约束为准
+	if c := req.Constraint; c != nil {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+		if c.JSONSchema == nil {
+			return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "regex/grammar/choice constraints"}
+		}
+		payload["format"] = c.JSONSchema
+	}
+
+	// options 已在上方合并，其余键原样透传
+	if err := llm.MergeExtraBody(payload, withoutOptions(req.ExtraBody)); err != nil {
+		return nil, err
+	}
 
 	return json.Marshal(payload)
 }
 
+// ollamaThinkFromMetadata 解析旧版 Metadata["thinking"]/["think"] 中的思考开关
+// 新代码应使用 CompletionRequest.Reasoning
 func ollamaThinkFromMetadata(metadata map[string]any) (bool, bool) {
 	if len(metadata) == 0 {
 		return false, false
@@ -379,99 +454,146 @@ type ollamaResponse struct {
 	Message   struct {
 		Role      string `json:"role"`
 		Content   string `json:"content"`
+		Thinking  string `json:"thinking,omitempty"` // think=true 时返回的思考过程
 		ToolCalls []struct {
+			ID       string `json:"id,omitempty"` // 新版 Ollama 返回
 			Function struct {
+				Index     int            `json:"index,omitempty"`
 				Name      string         `json:"name"`
 				Arguments map[string]any `json:"arguments"`
 			} `json:"function"`
 		} `json:"tool_calls,omitempty"`
 	} `json:"message"`
-	Done               bool `json:"done"`
-	TotalDuration      int  `json:"total_duration"`
-	LoadDuration       int  `json:"load_duration"`
-	PromptEvalCount    int  `json:"prompt_eval_count"`
-	PromptEvalDuration int  `json:"prompt_eval_duration"`
-	EvalCount          int  `json:"eval_count"`
-	EvalDuration       int  `json:"eval_duration"`
+	Logprobs           []llm.TokenLogprob `json:"logprobs,omitempty"`
+	Done               bool               `json:"done"`
+	DoneReason         string             `json:"done_reason,omitempty"`
+	Error              string             `json:"error,omitempty"`
+	TotalDuration      int                `json:"total_duration"`
+	LoadDuration       int                `json:"load_duration"`
+	PromptEvalCount    int                `json:"prompt_eval_count"`
+	PromptEvalDuration int                `json:"prompt_eval_duration"`
+	EvalCount          int                `json:"eval_count"`
+	EvalDuration       int                `json:"eval_duration"`
 }
 
 // parseResponse 解析响应
 func (p *Provider) parseResponse(resp *ollamaResponse, model string) *llm.CompletionResponse {
 	result := &llm.CompletionResponse{
-		ID:      resp.CreatedAt,
-		Model:   model,
-		Content: resp.Message.Content,
-		Usage: llm.Usage{
-			PromptTokens:     resp.PromptEvalCount,
-			CompletionTokens: resp.EvalCount,
-			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
-		},
+		ID:        resp.CreatedAt,
+		Model:     model,
+		Content:   resp.Message.Content,
+		Reasoning: resp.Message.Thinking,
+		Logprobs:  resp.Logprobs,
+		Usage:     resp.usage(),
+		ToolCalls: resp.toolCalls(0),
 	}
-
 	if resp.Done {
-		result.FinishReason = "stop"
+		result.FinishReason = finishReason(resp.DoneReason, len(result.ToolCalls) > 0)
+	}
+	return result
+}
+
+// usage 返回用量统计（仅最后一条响应有值）
+func (r *ollamaResponse) usage() llm.Usage {
+	return llm.Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
 	}
+}
 
-	// 解析工具调用
-	for i, tc := range resp.Message.ToolCalls {
+// toolCalls 解析工具调用，offset 为此前已产生的调用数（流式时保证 ID 唯一）
+// Ollama 一次性返回完整参数；未返回 ID 时按序生成 call_N
+func (r *ollamaResponse) toolCalls(offset int) []llm.ToolCall {
+	var calls []llm.ToolCall
+	for i, tc := range r.Message.ToolCalls {
+		id := tc.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", offset+i)
+		}
 		args, _ := json.Marshal(tc.Function.Arguments)
-		result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
-			ID:        fmt.Sprintf("call_%d", i),
+		calls = append(calls, llm.ToolCall{
+			ID:        id,
 			Type:      "function",
 			Name:      tc.Function.Name,
 			Arguments: string(args),
 		})
 	}
-
-	return result
+	return calls
 }
 
-// Ping 检查 Ollama 服务是否可用
-func (p *Provider) Ping(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
-	if err != nil {
-		return err
+// finishReason 将 done_reason 映射为 OpenAI 风格的结束原因
+func finishReason(doneReason string, hasToolCalls bool) string {
+	switch {
+	case hasToolCalls:
+		return "tool_calls"
+	case doneReason == "length":
+		return "length"
+	default:
+		return "stop"
 	}
+}
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("ollama service unavailable: %w", err)
-	}
-	defer resp.Body.Close()
+// readStream 逐行解析 NDJSON 流式响应
+func (p *Provider) readStream(body io.Reader, req llm.CompletionRequest, emit func(*streamx.Chunk) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	stripReasoning := req.Reasoning.Excluded()
+	toolCount := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var r ollamaResponse
+		if err := json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("ollama stream decode failed: %w", err)
+		}
+		if r.Error != "" {
+			return fmt.Errorf("ollama stream error: %s", r.Error)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ollama service returned status: %d", resp.StatusCode)
+		chunk := &streamx.Chunk{
+			Model:    r.Model,
+			Role:     r.Message.Role,
+			Content:  r.Message.Content,
+			Logprobs: r.Logprobs,
+		}
+		if !stripReasoning {
+			chunk.Reasoning = r.Message.Thinking
+		}
+		chunk.ToolCalls = r.toolCalls(toolCount)
+		toolCount += len(chunk.ToolCalls)
+		if r.Done {
+			chunk.FinishReason = finishReason(r.DoneReason, toolCount > 0)
+			usage := r.usage()
+			chunk.Usage = &usage
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+		if r.Done {
+			return nil
+		}
 	}
-
-	return nil
+	return scanner.Err()
 }
 
-// PullModel 拉取模型
-func (p *Provider) PullModel(ctx context.Context, model string) error {
-	payload := map[string]any{
-		"name":   model,
-		"stream": false,
-	}
-	body, _ := json.Marshal(payload)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/pull", bytes.NewReader(body))
+// Ping 检查 Ollama 服务是否可用
+func (p *Provider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("pull model failed: %w", err)
+		return fmt.Errorf("ollama service unavailable: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return fmt.Errorf("pull model failed: %s (failed to read body: %v)", resp.Status, readErr)
-		}
-		return fmt.Errorf("pull model failed: %s", string(bodyBytes))
+		return fmt.Errorf("ollama service returned status: %d", resp.StatusCode)
 	}
 
 	return nil
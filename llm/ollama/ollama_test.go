@@ -2,7 +2,12 @@ package ollama
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
 )
@@ -60,3 +65,269 @@ func TestBuildRequestBodyPreservesExplicitThinkingOn(t *testing.T) {
 		t.Fatalf("payload[think] = false, want true")
 	}
 }
+
+func TestBuildRequestBodyReasoningOverridesMetadata(t *testing.T) {
+	p := New()
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Model:     "qwen3",
+		Messages:  []llm.Message{llm.UserMessage("hi")},
+		Metadata:  map[string]any{"thinking": "on"},
+		Reasoning: &llm.ReasoningConfig{Effort: llm.ReasoningEffortNone},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+
+	if got, ok := payload["think"].(bool); !ok || got {
+		t.Fatalf("payload[think] = %v, want false", payload["think"])
+	}
+}
+
+func TestBuildRequestBodyUnspecifiedReasoningOmitsThink(t *testing.T) {
+	p := New()
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Model:     "llama3.2",
+		Messages:  []llm.Message{llm.UserMessage("hi")},
+		Reasoning: &llm.ReasoningConfig{Exclude: true},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if _, ok := payload["think"]; ok {
+		t.Fatalf("payload[think] = %v, want absent", payload["think"])
+	}
+}
+
+func TestBuildRequestBodyLogprobs(t *testing.T) {
+	p := New()
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Model:       "llama3.2",
+		Messages:    []llm.Message{llm.UserMessage("hi")},
+		TopLogprobs: 3,
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload["logprobs"] != true || payload["top_logprobs"] != float64(3) {
+		t.Fatalf("unexpected logprobs payload: logprobs=%v top_logprobs=%v", payload["logprobs"], payload["top_logprobs"])
+	}
+}
+
+func TestBuildRequestBodyTypedSamplingOptions(t *testing.T) {
+	p := New()
+	seed, topK, penalty := 42, 20, 1.1
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Model:             "llama3.2",
+		Messages:          []llm.Message{llm.UserMessage("hi")},
+		Seed:              &seed,
+		TopK:              &topK,
+		RepetitionPenalty: &penalty,
+		ExtraBody:         map[string]any{"keep_alive": "5m"},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+
+	var payload struct {
+		KeepAlive string         `json:"keep_alive"`
+		Options   map[string]any `json:"options"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if payload.Options["seed"] != float64(42) || payload.Options["top_k"] != float64(20) || payload.Options["repeat_penalty"] != 1.1 {
+		t.Errorf("unexpected options: %v", payload.Options)
+	}
+	if payload.KeepAlive != "5m" {
+		t.Errorf("keep_alive = %q, want 5m", payload.KeepAlive)
+	}
+}
+
+func TestBuildRequestBodyMessagesRoundTrip(t *testing.T) {
+	p := New()
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Model: "llava",
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, MultiContent: []llm.ContentPart{
+				{Type: "text", Text: "what is this?"},
+				{Type: "image_url", ImageURL: &llm.ImageURL{URL: "data:image/png;base64,iVBORw0K"}},
+			}},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCallRef{{ID: "call_0", Name: "lookup", Arguments: `{"q":"cat"}`}}},
+			{Role: llm.RoleTool, ToolCallID: "call_0", Content: "a cat"},
+		},
+		Tools: []llm.ToolDefinition{{Function: llm.ToolFunctionDef{Name: "lookup"}}},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+
+	var payload struct {
+		Messages []struct {
+			Content   string   `json:"content"`
+			Images    []string `json:"images"`
+			ToolName  string   `json:"tool_name"`
+			ToolCalls []struct {
+				Function struct {
+					Name      string         `json:"name"`
+					Arguments map[string]any `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"messages"`
+		Tools []map[string]any `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	msgs := payload.Messages
+	if msgs[0].Content != "what is this?" || len(msgs[0].Images) != 1 || msgs[0].Images[0] != "iVBORw0K" {
+		t.Errorf("image message = %+v", msgs[0])
+	}
+	if len(msgs[1].ToolCalls) != 1 || msgs[1].ToolCalls[0].Function.Arguments["q"] != "cat" {
+		t.Errorf("assistant tool calls = %+v", msgs[1])
+	}
+	if msgs[2].ToolName != "lookup" {
+		t.Errorf("tool_name = %q", msgs[2].ToolName)
+	}
+	fn, _ := payload.Tools[0]["function"].(map[string]any)
+	if payload.Tools[0]["type"] != "function" || fn["parameters"] == nil {
+		t.Errorf("tools = %v", payload.Tools)
+	}
+
+	_, err = p.buildRequestBody(llm.CompletionRequest{Messages: []llm.Message{{Role: llm.RoleUser, MultiContent: []llm.ContentPart{
+		{Type: "image_url", ImageURL: &llm.ImageURL{URL: "https://example.com/cat.png"}},
+	}}}}, false)
+	var unsupported *llm.UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("remote image error = %v, want UnsupportedError", err)
+	}
+}
+
+func TestBuildRequestBodyOptionsPrecedence(t *testing.T) {
+	numCtx, mirostat, defaultSeed := 4096, 2, 1
+	p := New(WithOptions(Options{NumCtx: &numCtx, Seed: &defaultSeed}), WithKeepAlive(-1))
+
+	seed, ctx8k := 42, 8192
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Messages:  []llm.Message{llm.UserMessage("hi")},
+		Seed:      &seed,
+		ExtraBody: map[string]any{"options": Options{NumCtx: &ctx8k, Mirostat: &mirostat}},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+	var payload struct {
+		KeepAlive string         `json:"keep_alive"`
+		Options   map[string]any `json:"options"`
+	}
+	json.Unmarshal(body, &payload)
+	if payload.Options["num_ctx"] != float64(8192) || payload.Options["mirostat"] != float64(2) || payload.Options["seed"] != float64(42) {
+		t.Errorf("options = %v", payload.Options)
+	}
+	if _, err := time.ParseDuration(payload.KeepAlive); err != nil || payload.KeepAlive != "-1m" {
+		t.Errorf("keep_alive = %q", payload.KeepAlive)
+	}
+
+	_, err = p.buildRequestBody(llm.CompletionRequest{
+		Messages:  []llm.Message{llm.UserMessage("hi")},
+		Seed:      &seed,
+		ExtraBody: map[string]any{"options": map[string]any{"seed": 7}},
+	}, false)
+	if !errors.Is(err, llm.ErrReservedParam) {
+		t.Errorf("conflict error = %v, want ErrReservedParam", err)
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"model":"qwen3","message":{"role":"assistant","content":"","thinking":"hmm"},"done":false}
+{"model":"qwen3","message":{"role":"assistant","content":"Hel"},"done":false}
+{"model":"qwen3","message":{"role":"assistant","content":"lo","tool_calls":[{"function":{"name":"lookup","arguments":{"q":"x"}}}]},"done":false}
+{"model":"qwen3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":7,"eval_count":3}
+`)
+	}))
+	defer srv.Close()
+
+	stream, err := New(WithBaseURL(srv.URL)).Stream(t.Context(), llm.CompletionRequest{Messages: []llm.Message{llm.UserMessage("hi")}})
+	if err != nil {
+		t.Fatalf("Stream error: %v", err)
+	}
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.Content != "Hello" || result.Reasoning != "hmm" || result.FinishReason != "tool_calls" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.ToolCalls) != 1 || result.ToolCalls[0].Arguments != `{"q":"x"}` || result.Usage.TotalTokens != 10 {
+		t.Errorf("tool calls = %+v, usage = %+v", result.ToolCalls, result.Usage)
+	}
+}
+
+func TestBuildRequestBodyJSONSchemaFormat(t *testing.T) {
+	p := New()
+	s := &llm.Schema{Type: "object", Properties: map[string]*llm.Schema{"age": {Type: "integer"}}, Required: []string{"age"}}
+	body, err := p.buildRequestBody(llm.CompletionRequest{
+		Messages:       []llm.Message{llm.UserMessage("hi")},
+		ResponseFormat: &llm.ResponseFormat{Type: "json_schema", JSONSchema: &llm.ResponseFormatJSONSchema{Name: "person", Schema: s}},
+	}, false)
+	if err != nil {
+		t.Fatalf("buildRequestBody returned error: %v", err)
+	}
+	var payload struct {
+		Format map[string]any `json:"format"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("format should be a schema object: %v (%s)", err, body)
+	}
+	if payload.Format["type"] != "object" || payload.Format["properties"] == nil {
+		t.Errorf("format = %v", payload.Format)
+	}
+
+	_, err = p.buildRequestBody(llm.CompletionRequest{
+		Messages:   []llm.Message{llm.UserMessage("hi")},
+		Constraint: &llm.Constraint{Grammar: `root ::= "a"`},
+	}, false)
+	var unsupported *llm.UnsupportedError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("grammar constraint error = %v, want UnsupportedError", err)
+	}
+}
+
+func TestCreateEmbeddings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["dimensions"] != float64(2) || body["model"] != "qwen3-embedding" {
+			t.Errorf("unexpected body: %v", body)
+		}
+		io.WriteString(w, `{"model":"qwen3-embedding","embeddings":[[0.5,0.25]],"prompt_eval_count":3}`)
+	}))
+	defer srv.Close()
+
+	p := New(WithBaseURL(srv.URL))
+	resp, err := p.CreateEmbeddings(t.Context(), llm.EmbedRequest{Model: "qwen3-embedding", Input: []string{"hi"}, Dimensions: 2})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if len(resp.Embeddings) != 1 || resp.Embeddings[0][1] != 0.25 || resp.Usage.PromptTokens != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,124 @@
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// Options Ollama 运行参数（请求体中的 options 字段）
+//
+// 可通过 WithOptions 设置 Provider 级默认值，或按请求放入 ExtraBody：
+//
+//	numCtx := 16384
+//	req.ExtraBody = map[string]any{
+//	    "options":    ollama.Options{NumCtx: &numCtx},
+//	    "keep_alive": ollama.KeepAlive(30 * time.Minute),
+//	}
+//
+// 优先级：ExtraBody > CompletionRequest 类型化字段 > Provider 默认值；
+// ExtraBody 与类型化字段设置同一参数时返回 llm.ErrReservedParam。
+type Options struct {
+	// 加载参数（模型已加载时修改会触发重新加载）
+	NumCtx    *int  `json:"num_ctx,omitempty"`    // 上下文窗口大小
+	NumBatch  *int  `json:"num_batch,omitempty"`  // 提示词批处理大小
+	NumGPU    *int  `json:"num_gpu,omitempty"`    // 卸载到 GPU 的层数
+	MainGPU   *int  `json:"main_gpu,omitempty"`   // 多 GPU 时的主 GPU
+	NumThread *int  `json:"num_thread,omitempty"` // CPU 线程数
+	UseMMap   *bool `json:"use_mmap,omitempty"`   // 是否使用 mmap 加载模型
+
+	// 采样参数
+	NumKeep          *int     `json:"num_keep,omitempty"`          // 上下文截断时保留的提示词 Token 数
+	NumPredict       *int     `json:"num_predict,omitempty"`       // 最大生成 Token 数（-1 不限）
+	Seed             *int     `json:"seed,omitempty"`              // 随机种子
+	Temperature      *float64 `json:"temperature,omitempty"`       // 温度
+	TopK             *int     `json:"top_k,omitempty"`             // Top-K 采样
+	TopP             *float64 `json:"top_p,omitempty"`             // Top-P 采样
+	MinP             *float64 `json:"min_p,omitempty"`             // Min-P 采样
+	TypicalP         *float64 `json:"typical_p,omitempty"`         // 局部典型采样
+	RepeatLastN      *int     `json:"repeat_last_n,omitempty"`     // 重复惩罚回看的 Token 数
+	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`    // 重复惩罚
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // 存在惩罚
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // 频率惩罚
+	Mirostat         *int     `json:"mirostat,omitempty"`          // Mirostat 采样（0 关闭，1 或 2 开启）
+	MirostatTau      *float64 `json:"mirostat_tau,omitempty"`      // Mirostat 目标熵
+	MirostatEta      *float64 `json:"mirostat_eta,omitempty"`      // Mirostat 学习率
+	Stop             []string `json:"stop,omitempty"`              // 停止序列
+}
+
+// KeepAlive 将时长转换为 keep_alive 参数值
+// 0 表示生成后立即卸载，负数表示常驻内存
+// 服务端按 time.ParseDuration 解析字符串值，常驻内存需带单位（"-1m"），不能写作 "-1"
+func KeepAlive(d time.Duration) string {
+	if d < 0 {
+		return "-1m"
+	}
+	return d.String()
+}
+
+// toMap 将 Options 转为请求体中的键值
+func (o Options) toMap() map[string]any {
+	data, _ := json.Marshal(o)
+	var m map[string]any
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+// requestOptions 解析 ExtraBody["options"]，支持 Options、*Options 与 map[string]any
+func requestOptions(v any) (map[string]any, error) {
+	switch o := v.(type) {
+	case nil:
+		return nil, nil
+	case Options:
+		return o.toMap(), nil
+	case *Options:
+		if o == nil {
+			return nil, nil
+		}
+		return o.toMap(), nil
+	case map[string]any:
+		return o, nil
+	default:
+		return nil, fmt.Errorf("ollama: extra_body options must be ollama.Options or map[string]any, got %T", v)
+	}
+}
+
+// buildOptions 按优先级合并运行参数
+// explicit 为类型化字段映射出的参数，与 ExtraBody 冲突时返回 llm.ErrReservedParam
+func (p *Provider) buildOptions(explicit map[string]any, extra any) (map[string]any, error) {
+	perRequest, err := requestOptions(extra)
+	if err != nil {
+		return nil, err
+	}
+
+	options := p.options.toMap()
+	if options == nil {
+		options = make(map[string]any)
+	}
+	for k, v := range explicit {
+		options[k] = v
+	}
+	for k, v := range perRequest {
+		if _, exists := explicit[k]; exists {
+			return nil, fmt.Errorf("%w: extra_body options key %q conflicts with a typed request field", llm.ErrReservedParam, k)
+		}
+		options[k] = v
+	}
+	return options, nil
+}
+
+// withoutOptions 返回去除 options 键的 ExtraBody（options 由 buildOptions 单独合并）
+func withoutOptions(extra map[string]any) map[string]any {
+	if _, ok := extra["options"]; !ok {
+		return extra
+	}
+	rest := make(map[string]any, len(extra))
+	for k, v := range extra {
+		if k != "options" {
+			rest[k] = v
+		}
+	}
+	return rest
+}
@@ -0,0 +1,17 @@
+package ollama
+
+import "github.com/hexagon-codes/ai-core/llm"
+
+// 注册为 "ollama"，本地服务无需 APIKey
+func init() {
+	llm.RegisterProvider("ollama", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		var opts []Option
+		if cfg.BaseURL != "" {
+			opts = append(opts, WithBaseURL(cfg.BaseURL))
+		}
+		if cfg.Model != "" {
+			opts = append(opts, WithModel(cfg.Model))
+		}
+		return New(opts...), nil
+	})
+}
@@ -0,0 +1,189 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+const (
+	defaultTranscriptionModel = "whisper-1"
+	defaultSpeechModel        = "tts-1"
+	defaultSpeechVoice        = "alloy"
+)
+
+// Transcribe 调用 OpenAI 兼容的 /audio/transcriptions 端点转写音频
+//
+// 音频以 multipart/form-data 上传，单文件上限以服务端为准（OpenAI 为 25MB）。
+// Format 为 text/srt/vtt 时服务端返回纯文本，原样放入 Text。
+func (p *Provider) Transcribe(ctx context.Context, req llm.TranscriptionRequest) (*llm.TranscriptionResponse, error) {
+	if req.Audio == nil {
+		return nil, fmt.Errorf("transcription audio is required")
+	}
+	if req.Model == "" {
+		req.Model = defaultTranscriptionModel
+	}
+	if req.FileName == "" {
+		req.FileName = "audio.mp3"
+	}
+	if req.Format == "" {
+		req.Format = llm.TranscriptionJSON
+	}
+
+	body, contentType, err := buildTranscriptionForm(req)
+	if err != nil {
+		return nil, fmt.Errorf("构建转写请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuth(httpReq)
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("转写请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("openai audio api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("openai audio api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	switch req.Format {
+	case llm.TranscriptionText, llm.TranscriptionSRT, llm.TranscriptionVTT:
+		text, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("读取转写结果失败: %w", err)
+		}
+		return &llm.TranscriptionResponse{Text: string(text)}, nil
+	}
+
+	var result llm.TranscriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析转写响应失败: %w", err)
+	}
+	return &result, nil
+}
+
+// buildTranscriptionForm 构建转写请求的 multipart 表单
+func buildTranscriptionForm(req llm.TranscriptionRequest) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	part, err := w.CreateFormFile("file", req.FileName)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, req.Audio); err != nil {
+		return nil, "", fmt.Errorf("读取音频失败: %w", err)
+	}
+
+	fields := map[string]string{
+		"model":           req.Model,
+		"response_format": string(req.Format),
+		"language":        req.Language,
+		"prompt":          req.Prompt,
+	}
+	if req.Temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*req.Temperature, 'f', -1, 64)
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, g := range req.TimestampGranularities {
+		if err := w.WriteField("timestamp_granularities[]", g); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+// SynthesizeSpeech 调用 OpenAI 兼容的 /audio/speech 端点合成语音
+//
+// 返回的音频流直接来自 HTTP 响应体，调用方负责关闭。
+func (p *Provider) SynthesizeSpeech(ctx context.Context, req llm.SpeechRequest) (*llm.SpeechResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultSpeechModel
+	}
+	if req.Voice == "" {
+		req.Voice = defaultSpeechVoice
+	}
+
+	payload := speechRequest{
+		Model:          req.Model,
+		Input:          req.Input,
+		Voice:          req.Voice,
+		ResponseFormat: req.Format,
+		Speed:          req.Speed,
+		Instructions:   req.Instructions,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化语音合成请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("语音合成请求失败: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("openai audio api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("openai audio api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	return &llm.SpeechResponse{
+		Audio:       resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// speechRequest OpenAI Audio Speech API 请求结构
+type speechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+	Instructions   string  `json:"instructions,omitempty"`
+}
+
+// 确保 OpenAI Provider 实现了音频相关接口
+var (
+	_ llm.TranscriptionProvider = (*Provider)(nil)
+	_ llm.SpeechProvider        = (*Provider)(nil)
+)
@@ -0,0 +1,96 @@
+package openai
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestTranscribe_VerboseJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/transcriptions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		if got := r.FormValue("response_format"); got != "verbose_json" {
+			t.Errorf("response_format = %q", got)
+		}
+		if got := r.MultipartForm.Value["timestamp_granularities[]"]; len(got) != 1 || got[0] != "segment" {
+			t.Errorf("timestamp_granularities = %v", got)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "a.wav" || string(data) != "RIFF" {
+			t.Errorf("unexpected file %s: %q", header.Filename, data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"text":"你好","language":"chinese","duration":1.5,"segments":[{"id":0,"start":0,"end":1.5,"text":"你好"}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.Transcribe(t.Context(), llm.TranscriptionRequest{
+		Audio:                  strings.NewReader("RIFF"),
+		FileName:               "a.wav",
+		Format:                 llm.TranscriptionVerboseJSON,
+		TimestampGranularities: []string{"segment"},
+	})
+	if err != nil {
+		t.Fatalf("Transcribe error: %v", err)
+	}
+	if resp.Text != "你好" || resp.Duration != 1.5 || len(resp.Segments) != 1 || resp.Segments[0].End != 1.5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTranscribe_SRTReturnsRawText(t *testing.T) {
+	const srt = "1\n00:00:00,000 --> 00:00:01,500\n你好\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, srt)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.Transcribe(t.Context(), llm.TranscriptionRequest{
+		Audio:  strings.NewReader("x"),
+		Format: llm.TranscriptionSRT,
+	})
+	if err != nil {
+		t.Fatalf("Transcribe error: %v", err)
+	}
+	if resp.Text != srt {
+		t.Errorf("Text = %q, want %q", resp.Text, srt)
+	}
+}
+
+func TestSynthesizeSpeech(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"voice":"alloy"`) || !strings.Contains(string(body), `"response_format":"wav"`) {
+			t.Errorf("unexpected body: %s", body)
+		}
+		w.Header().Set("Content-Type", "audio/wav")
+		io.WriteString(w, "WAVDATA")
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.SynthesizeSpeech(t.Context(), llm.SpeechRequest{Input: "hi", Format: "wav"})
+	if err != nil {
+		t.Fatalf("SynthesizeSpeech error: %v", err)
+	}
+	defer resp.Audio.Close()
+	data, _ := io.ReadAll(resp.Audio)
+	if string(data) != "WAVDATA" || resp.ContentType != "audio/wav" {
+		t.Errorf("unexpected audio: %q %s", data, resp.ContentType)
+	}
+}
@@ -0,0 +1,270 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// batchEndpoint 批量任务执行的接口路径
+const batchEndpoint = "/v1/chat/completions"
+
+// BatchBodyFunc 将补全请求编码为批量任务单行请求体
+//
+// 兼容厂商（通义千问、火山方舟）复用本包的批量实现时，
+// 通过它注入各自的请求体构建逻辑（模型映射、厂商参数等）。
+type BatchBodyFunc func(req llm.CompletionRequest) ([]byte, error)
+
+// CreateBatch 将请求写为 JSONL 上传后创建批量任务
+func (p *Provider) CreateBatch(ctx context.Context, req llm.BatchRequest) (*llm.BatchJob, error) {
+	return p.CreateBatchWithBody(ctx, req, func(r llm.CompletionRequest) ([]byte, error) {
+		if r.Model == "" {
+			r.Model = p.model
+		}
+		return p.buildRequestBody(r, false)
+	})
+}
+
+// CreateBatchWithBody 使用自定义请求体构建函数创建批量任务
+func (p *Provider) CreateBatchWithBody(ctx context.Context, req llm.BatchRequest, body BatchBodyFunc) (*llm.BatchJob, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("batch requires at least one item")
+	}
+
+	input, err := encodeBatchInput(req.Items, body)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := p.UploadFile(ctx, "batch.jsonl", "batch", bytes.NewReader(input))
+	if err != nil {
+		return nil, fmt.Errorf("上传批量输入文件失败: %w", err)
+	}
+
+	window := req.CompletionWindow
+	if window == "" {
+		window = "24h"
+	}
+	payload := map[string]any{
+		"input_file_id":     file.ID,
+		"endpoint":          batchEndpoint,
+		"completion_window": window,
+	}
+	if len(req.Metadata) > 0 {
+		payload["metadata"] = req.Metadata
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化批量任务请求失败: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/batches", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	return p.doBatch(httpReq, "创建批量任务")
+}
+
+// GetBatch 查询批量任务状态
+func (p *Provider) GetBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/batches/"+batchID, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+	return p.doBatch(httpReq, "查询批量任务")
+}
+
+// CancelBatch 取消批量任务
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/batches/"+batchID+"/cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+	return p.doBatch(httpReq, "取消批量任务")
+}
+
+// GetBatchResults 下载批量任务的成功与失败结果，按 CustomID 索引
+//
+// 任务未结束时输出文件可能不存在，此时返回已有的部分结果。
+func (p *Provider) GetBatchResults(ctx context.Context, batchID string) (map[string]llm.BatchResult, error) {
+	job, err := p.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]llm.BatchResult, job.RequestCounts.Total)
+	for _, fileID := range []string{job.OutputFileID, job.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := p.readBatchOutput(ctx, fileID, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// readBatchOutput 解析结果文件的每一行写入 results
+func (p *Provider) readBatchOutput(ctx context.Context, fileID string, results map[string]llm.BatchResult) error {
+	body, err := p.DownloadFile(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("下载批量结果文件失败: %w", err)
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20) // 单行可能包含较长的补全结果
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var out batchOutputLine
+		if err := json.Unmarshal(line, &out); err != nil {
+			return fmt.Errorf("解析批量结果失败: %w", err)
+		}
+
+		result := llm.BatchResult{CustomID: out.CustomID}
+		if out.Response != nil {
+			result.StatusCode = out.Response.StatusCode
+			if out.Response.StatusCode == http.StatusOK {
+				var resp openAIResponse
+				if err := json.Unmarshal(out.Response.Body, &resp); err != nil {
+					return fmt.Errorf("解析批量结果 %s 失败: %w", out.CustomID, err)
+				}
+				result.Response = p.parseResponse(&resp)
+			} else {
+				result.Error = string(out.Response.Body)
+			}
+		}
+		if out.Error != nil && out.Error.Message != "" {
+			result.Error = out.Error.Message
+		}
+		results[out.CustomID] = result
+	}
+	return scanner.Err()
+}
+
+// doBatch 发送批量任务请求并转换为统一结构
+func (p *Provider) doBatch(httpReq *http.Request, action string) (*llm.BatchJob, error) {
+	var result batchObject
+	if err := p.doJSON(httpReq, action, &result); err != nil {
+		return nil, err
+	}
+	return result.toJob(), nil
+}
+
+// encodeBatchInput 将批量请求编码为 JSONL
+func encodeBatchInput(items []llm.BatchItem, body BatchBodyFunc) ([]byte, error) {
+	var buf bytes.Buffer
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		id := item.CustomID
+		if id == "" {
+			id = "request-" + strconv.Itoa(i)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("batch item %d: duplicate custom_id %q", i, id)
+		}
+		seen[id] = true
+
+		data, err := body(item.Request)
+		if err != nil {
+			return nil, fmt.Errorf("batch item %s: %w", id, err)
+		}
+		line, err := json.Marshal(batchInputLine{
+			CustomID: id,
+			Method:   "POST",
+			URL:      batchEndpoint,
+			Body:     data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// batchInputLine 批量输入文件的单行
+type batchInputLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// batchOutputLine 批量结果文件的单行
+type batchOutputLine struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// batchObject OpenAI Batch API 任务对象
+type batchObject struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	InputFileID   string `json:"input_file_id"`
+	OutputFileID  string `json:"output_file_id"`
+	ErrorFileID   string `json:"error_file_id"`
+	CreatedAt     int64  `json:"created_at"`
+	CompletedAt   int64  `json:"completed_at"`
+	RequestCounts struct {
+		Total     int `json:"total"`
+		Completed int `json:"completed"`
+		Failed    int `json:"failed"`
+	} `json:"request_counts"`
+	Errors *struct {
+		Data []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Line    int    `json:"line"`
+		} `json:"data"`
+	} `json:"errors"`
+}
+
+// toJob 转换为统一的批量任务结构
+func (b *batchObject) toJob() *llm.BatchJob {
+	job := &llm.BatchJob{
+		ID:           b.ID,
+		Status:       llm.BatchStatus(b.Status),
+		InputFileID:  b.InputFileID,
+		OutputFileID: b.OutputFileID,
+		ErrorFileID:  b.ErrorFileID,
+		CreatedAt:    b.CreatedAt,
+		CompletedAt:  b.CompletedAt,
+		RequestCounts: llm.BatchRequestCounts{
+			Total:     b.RequestCounts.Total,
+			Completed: b.RequestCounts.Completed,
+			Failed:    b.RequestCounts.Failed,
+		},
+	}
+	if b.Errors != nil {
+		for _, e := range b.Errors.Data {
+			job.Errors = append(job.Errors, fmt.Sprintf("line %d: %s: %s", e.Line, e.Code, e.Message))
+		}
+	}
+	return job
+}
+
+// 确保 OpenAI Provider 实现了 BatchProvider 接口
+var _ llm.BatchProvider = (*Provider)(nil)
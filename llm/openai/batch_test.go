@@ -0,0 +1,90 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestBatch_CreateAndResults(t *testing.T) {
+	var uploaded string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /files", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("purpose") != "batch" {
+			t.Errorf("purpose = %q", r.FormValue("purpose"))
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		data, _ := io.ReadAll(f)
+		uploaded = string(data)
+		io.WriteString(w, `{"id":"file-in","filename":"batch.jsonl","purpose":"batch"}`)
+	})
+	mux.HandleFunc("POST /batches", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["input_file_id"] != "file-in" || body["endpoint"] != "/v1/chat/completions" || body["completion_window"] != "24h" {
+			t.Errorf("unexpected batch body: %v", body)
+		}
+		io.WriteString(w, `{"id":"batch-1","status":"validating","input_file_id":"file-in"}`)
+	})
+	mux.HandleFunc("GET /batches/batch-1", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"id":"batch-1","status":"completed","output_file_id":"file-out","error_file_id":"file-err","request_counts":{"total":2,"completed":1,"failed":1}}`)
+	})
+	mux.HandleFunc("GET /files/file-out/content", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"custom_id":"a","response":{"status_code":200,"body":{"id":"c1","model":"gpt-4o-mini","choices":[{"index":0,"message":{"role":"assistant","content":"positive"},"finish_reason":"stop"}]}}}`+"\n")
+	})
+	mux.HandleFunc("GET /files/file-err/content", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"custom_id":"request-1","response":null,"error":{"code":"invalid","message":"bad request"}}`+"\n")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL), WithModel("gpt-4o-mini"))
+	job, err := p.CreateBatch(t.Context(), llm.BatchRequest{Items: []llm.BatchItem{
+		{CustomID: "a", Request: llm.CompletionRequest{Messages: llm.NewMessages("", "good")}},
+		{Request: llm.CompletionRequest{Messages: llm.NewMessages("", "bad")}},
+	}})
+	if err != nil {
+		t.Fatalf("CreateBatch error: %v", err)
+	}
+	if job.ID != "batch-1" || job.Status != llm.BatchValidating {
+		t.Errorf("unexpected job: %+v", job)
+	}
+
+	lines := strings.Split(strings.TrimSpace(uploaded), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"custom_id":"a"`) || !strings.Contains(lines[1], `"custom_id":"request-1"`) {
+		t.Errorf("unexpected JSONL input: %s", uploaded)
+	}
+	if !strings.Contains(lines[0], `"model":"gpt-4o-mini"`) {
+		t.Errorf("default model not applied: %s", lines[0])
+	}
+
+	results, err := p.GetBatchResults(t.Context(), "batch-1")
+	if err != nil {
+		t.Fatalf("GetBatchResults error: %v", err)
+	}
+	if r := results["a"]; r.Response == nil || r.Response.Content != "positive" {
+		t.Errorf("unexpected result a: %+v", r)
+	}
+	if r := results["request-1"]; r.Response != nil || r.Error != "bad request" {
+		t.Errorf("unexpected result request-1: %+v", r)
+	}
+}
+
+func TestBatch_DuplicateCustomID(t *testing.T) {
+	p := New("key", WithBaseURL("http://127.0.0.1:0"))
+	_, err := p.CreateBatch(t.Context(), llm.BatchRequest{Items: []llm.BatchItem{
+		{CustomID: "x", Request: llm.CompletionRequest{Messages: llm.NewMessages("", "1")}},
+		{CustomID: "x", Request: llm.CompletionRequest{Messages: llm.NewMessages("", "2")}},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "duplicate custom_id") {
+		t.Errorf("err = %v, want duplicate custom_id", err)
+	}
+}
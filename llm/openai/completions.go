@@ -0,0 +1,148 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// CompleteText 调用 /completions 端点执行文本补全
+// 设置 Suffix 时为 FIM 补全；Profile.FIMPrompt 非 nil 时前后缀拼接为提示词发送
+func (p *Provider) CompleteText(ctx context.Context, req llm.TextCompletionRequest) (*llm.TextCompletionResponse, error) {
+	resp, err := p.postCompletions(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result textCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析文本补全响应失败: %w", err)
+	}
+
+	out := &llm.TextCompletionResponse{
+		ID:    result.ID,
+		Model: result.Model,
+	}
+	if result.Usage != nil {
+		out.Usage = result.Usage.ToUsage()
+	}
+	if len(result.Choices) > 0 {
+		out.Text = result.Choices[0].Text
+		out.FinishReason = result.Choices[0].FinishReason
+	}
+	return out, nil
+}
+
+// StreamText 调用 /completions 端点执行流式文本补全
+func (p *Provider) StreamText(ctx context.Context, req llm.TextCompletionRequest) (*streamx.Stream, error) {
+	resp, err := p.postCompletions(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.TextCompletionFormat), nil
+}
+
+// postCompletions 发送 /completions 请求，非 2xx 时读取响应体并返回错误
+func (p *Provider) postCompletions(ctx context.Context, req llm.TextCompletionRequest, stream bool) (*http.Response, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+
+	body, err := p.buildCompletionsBody(req, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := p.baseURL + "/completions"
+	if p.profile.CompletionsURL != nil {
+		endpoint = p.profile.CompletionsURL(p.baseURL)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("文本补全请求失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("%s completions api error: %s (failed to read body: %v)", p.profile.Name, resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("%s completions api error: %s, body: %s", p.profile.Name, resp.Status, string(bodyBytes))
+	}
+	return resp, nil
+}
+
+// buildCompletionsBody 构建 /completions 请求体
+func (p *Provider) buildCompletionsBody(req llm.TextCompletionRequest, stream bool) ([]byte, error) {
+	payload := map[string]any{
+		"model":  req.Model,
+		"prompt": req.Prompt,
+		"stream": stream,
+	}
+	if req.Suffix != "" {
+		if p.profile.FIMPrompt != nil {
+			payload["prompt"] = p.profile.FIMPrompt(req.Prompt, req.Suffix)
+		} else {
+			payload["suffix"] = req.Suffix
+		}
+	}
+	if stream && !p.profile.DisableStreamUsage {
+		payload["stream_options"] = map[string]any{"include_usage": true}
+	}
+	if req.Echo {
+		payload["echo"] = true
+	}
+	if len(req.Stop) > 0 {
+		payload["stop"] = req.Stop
+	}
+	if req.MaxTokens > 0 {
+		payload["max_tokens"] = req.MaxTokens
+	}
+	if req.Temperature != nil {
+		payload["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		payload["top_p"] = *req.TopP
+	}
+	if req.Seed != nil {
+		payload["seed"] = *req.Seed
+	}
+
+	// legacy 接口沿用 max_tokens 命名，仅移除厂商不支持的参数
+	for _, param := range p.profile.Unsupported {
+		delete(payload, param)
+	}
+
+	if err := llm.MergeExtraBody(payload, req.ExtraBody, "stream_options"); err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+// textCompletionResponse /completions 非流式响应结构
+type textCompletionResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int    `json:"index"`
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *streamx.OpenAIUsage `json:"usage,omitempty"`
+}
+
+// 确保 OpenAI Provider 实现了 CompletionTextProvider 接口
+var _ llm.CompletionTextProvider = (*Provider)(nil)
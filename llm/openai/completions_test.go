@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestCompleteText_FIM(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/beta/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["prompt"] != "def add(a, b):\n" || body["suffix"] != "\n\nprint(add(1, 2))" || body["max_tokens"] != float64(64) {
+			t.Errorf("unexpected body: %v", body)
+		}
+		if _, ok := body["n"]; ok {
+			t.Errorf("unsupported param sent: %v", body)
+		}
+		io.WriteString(w, `{"id":"cmpl-1","object":"text_completion","model":"deepseek-chat","choices":[{"index":0,"text":"    return a + b","finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":6,"total_tokens":18,"prompt_cache_hit_tokens":8}}`)
+	}))
+	defer srv.Close()
+
+	p := NewCompatible(Profile{
+		Name:        "deepseek",
+		BaseURL:     srv.URL + "/v1",
+		Unsupported: []string{"n"},
+		CompletionsURL: func(baseURL string) string {
+			return strings.TrimSuffix(baseURL, "/v1") + "/beta/completions"
+		},
+	}, "key")
+	resp, err := p.CompleteText(t.Context(), llm.TextCompletionRequest{
+		Model:     "deepseek-chat",
+		Prompt:    "def add(a, b):\n",
+		Suffix:    "\n\nprint(add(1, 2))",
+		MaxTokens: 64,
+	})
+	if err != nil {
+		t.Fatalf("CompleteText error: %v", err)
+	}
+	if resp.Text != "    return a + b" || resp.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 18 || resp.Usage.CachedTokens != 8 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestStreamText_FIMPrompt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["prompt"] != "<|fim_prefix|>a = <|fim_suffix|>\n<|fim_middle|>" || body["suffix"] != nil {
+			t.Errorf("unexpected prompt: %v", body)
+		}
+		if body["stream"] != true || body["stream_options"] == nil {
+			t.Errorf("unexpected stream params: %v", body)
+		}
+		io.WriteString(w, "data: {\"id\":\"cmpl-2\",\"choices\":[{\"index\":0,\"text\":\"1\"}]}\n\n"+
+			"data: {\"id\":\"cmpl-2\",\"choices\":[{\"index\":0,\"text\":\"\",\"finish_reason\":\"stop\"}]}\n\n"+
+			"data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	p := NewCompatible(Profile{Name: "qwen", BaseURL: srv.URL, FIMPrompt: QwenFIMPrompt}, "key")
+	stream, err := p.StreamText(t.Context(), llm.TextCompletionRequest{Model: "qwen2.5-coder-32b-instruct", Prompt: "a = ", Suffix: "\n"})
+	if err != nil {
+		t.Fatalf("StreamText error: %v", err)
+	}
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.Content != "1" || result.FinishReason != "stop" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
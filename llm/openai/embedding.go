@@ -15,6 +15,9 @@ import (
 // 默认 Embedding 模型和维度
 const (
 	defaultEmbeddingModel = "text-embedding-3-small"
+
+	// maxEmbeddingInputs 单次请求的最大输入条数
+	maxEmbeddingInputs = 2048
 )
 
 // EmbeddingDimension 返回指定 Embedding 模型的默认向量维度
@@ -68,14 +71,43 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 	if len(texts) == 0 {
 		return nil, nil
 	}
-	if ctx.Err() != nil {
-		return nil, ctx.Err()
+	resp, err := p.CreateEmbeddings(ctx, llm.EmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// CreateEmbeddings 按完整请求参数生成嵌入
+//
+// 超过 maxEmbeddingInputs 条输入时自动分批；Dimensions 映射为 dimensions（仅 text-embedding-3 系列支持），
+// Base64 映射为 encoding_format=base64。OpenAI 不区分输入类型，InputType 被忽略。
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultEmbeddingModel
+	}
+	if len(req.Input) == 0 {
+		return &llm.EmbedResponse{Model: req.Model}, nil
 	}
+	return llm.EmbedBatches(ctx, req, maxEmbeddingInputs, func(ctx context.Context, input []string) (*llm.EmbedResponse, error) {
+		return p.embedBatch(ctx, req, input)
+	})
+}
 
-	// 构建请求体
-	payload := embeddingRequest{
-		Model: model,
-		Input: texts,
+// embedBatch 发送单批 /embeddings 请求
+func (p *Provider) embedBatch(ctx context.Context, req llm.EmbedRequest, input []string) (*llm.EmbedResponse, error) {
+	payload := map[string]any{
+		"model": req.Model,
+		"input": input,
+	}
+	if req.Dimensions > 0 {
+		payload["dimensions"] = req.Dimensions
+	}
+	if req.Base64 {
+		payload["encoding_format"] = "base64"
+	}
+	if err := llm.MergeExtraBody(payload, req.ExtraBody); err != nil {
+		return nil, err
 	}
 
 	body, err := json.Marshal(payload)
@@ -98,42 +130,27 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, readErr := io.ReadAll(resp.Body)
 		if readErr != nil {
-			return nil, fmt.Errorf("openai embedding api error: %s (failed to read body: %v)", resp.Status, readErr)
+			return nil, fmt.Errorf("%s embedding api error: %s (failed to read body: %v)", p.profile.Name, resp.Status, readErr)
 		}
-		return nil, fmt.Errorf("openai embedding api error: %s, body: %s", resp.Status, string(bodyBytes))
+		return nil, fmt.Errorf("%s embedding api error: %s, body: %s", p.profile.Name, resp.Status, string(bodyBytes))
 	}
 
-	var result embeddingResponse
+	var result EmbeddingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("解析 embedding 响应失败: %w", err)
 	}
-
-	// API 返回结果可能乱序，按 index 排序
-	sort.Slice(result.Data, func(i, j int) bool {
-		return result.Data[i].Index < result.Data[j].Index
-	})
-
-	embeddings := make([][]float32, len(result.Data))
-	for i, item := range result.Data {
-		embeddings[i] = item.Embedding
-	}
-
-	return embeddings, nil
+	return result.ToEmbedResponse()
 }
 
-// embeddingRequest OpenAI Embedding API 请求结构
-type embeddingRequest struct {
-	Model string   `json:"model"`
-	Input []string `json:"input"`
-}
-
-// embeddingResponse OpenAI Embedding API 响应结构
-type embeddingResponse struct {
+// EmbeddingResponse OpenAI 兼容 Embedding API 的响应结构
+// 通义千问等兼容厂商的 Embedding 实现可直接复用
+type EmbeddingResponse struct {
 	Object string `json:"object"`
 	Data   []struct {
-		Object    string    `json:"object"`
-		Index     int       `json:"index"`
-		Embedding []float32 `json:"embedding"`
+		Object string `json:"object"`
+		Index  int    `json:"index"`
+		// Embedding 为 float 数组，encoding_format=base64 时为 base64 字符串
+		Embedding json.RawMessage `json:"embedding"`
 	} `json:"data"`
 	Model string `json:"model"`
 	Usage struct {
@@ -142,5 +159,37 @@ type embeddingResponse struct {
 	} `json:"usage"`
 }
 
+// ToEmbedResponse 按 index 排序并解码向量
+func (r *EmbeddingResponse) ToEmbedResponse() (*llm.EmbedResponse, error) {
+	// API 返回结果可能乱序，按 index 排序
+	sort.Slice(r.Data, func(i, j int) bool {
+		return r.Data[i].Index < r.Data[j].Index
+	})
+
+	out := &llm.EmbedResponse{
+		Model:      r.Model,
+		Embeddings: make([][]float32, len(r.Data)),
+		Usage: llm.Usage{
+			PromptTokens: r.Usage.PromptTokens,
+			TotalTokens:  r.Usage.TotalTokens,
+		},
+	}
+	for i, item := range r.Data {
+		var encoded string
+		if err := json.Unmarshal(item.Embedding, &encoded); err == nil {
+			vec, err := llm.DecodeBase64Embedding(encoded)
+			if err != nil {
+				return nil, err
+			}
+			out.Embeddings[i] = vec
+			continue
+		}
+		if err := json.Unmarshal(item.Embedding, &out.Embeddings[i]); err != nil {
+			return nil, fmt.Errorf("解析 embedding 向量失败: %w", err)
+		}
+	}
+	return out, nil
+}
+
 // 确保 OpenAI Provider 实现了 EmbeddingProvider 接口
-var _ llm.EmbeddingProvider = (*Provider)(nil)
+var _ llm.EmbedRequestProvider = (*Provider)(nil)
@@ -0,0 +1,56 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestCreateEmbeddings_Base64Dimensions(t *testing.T) {
+	encode := func(vec ...float32) string {
+		buf := make([]byte, 4*len(vec))
+		for i, v := range vec {
+			binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+		}
+		return base64.StdEncoding.EncodeToString(buf)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["dimensions"] != float64(2) || body["encoding_format"] != "base64" {
+			t.Errorf("unexpected body: %v", body)
+		}
+		// 乱序返回，验证按 index 排序
+		fmt.Fprintf(w, `{"model":"text-embedding-3-small","data":[{"index":1,"embedding":%q},{"index":0,"embedding":%q}],"usage":{"prompt_tokens":4,"total_tokens":4}}`,
+			encode(0, 2), encode(3, 4))
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.CreateEmbeddings(t.Context(), llm.EmbedRequest{
+		Input:      []string{"a", "b"},
+		Dimensions: 2,
+		Base64:     true,
+		Normalize:  true,
+	})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if len(resp.Embeddings) != 2 || resp.Usage.TotalTokens != 4 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if v := resp.Embeddings[0]; math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Errorf("embeddings[0] = %v, want normalized [0.6 0.8]", v)
+	}
+	if v := resp.Embeddings[1]; v[0] != 0 || v[1] != 1 {
+		t.Errorf("embeddings[1] = %v, want [0 1]", v)
+	}
+}
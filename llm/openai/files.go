@@ -0,0 +1,128 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// File 上传到服务端的文件（Files API）
+type File struct {
+	ID        string `json:"id"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status,omitempty"`
+}
+
+// UploadFile 调用 /files 端点上传文件
+//
+// purpose 为文件用途，批量任务输入使用 "batch"。
+func (p *Provider) UploadFile(ctx context.Context, filename, purpose string, data io.Reader) (*File, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("purpose", purpose); err != nil {
+		return nil, err
+	}
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, data); err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/files", &buf)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuth(httpReq)
+	httpReq.Header.Set("Content-Type", w.FormDataContentType())
+
+	var file File
+	if err := p.doJSON(httpReq, "上传文件", &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// GetFile 查询文件信息
+func (p *Provider) GetFile(ctx context.Context, fileID string) (*File, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/files/"+fileID, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	var file File
+	if err := p.doJSON(httpReq, "查询文件", &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// DownloadFile 下载文件内容，调用方负责关闭返回的 io.ReadCloser
+func (p *Provider) DownloadFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/files/"+fileID+"/content", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuth(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("下载文件请求失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, readAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// DeleteFile 删除文件
+func (p *Provider) DeleteFile(ctx context.Context, fileID string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", p.baseURL+"/files/"+fileID, nil)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(httpReq)
+	return p.doJSON(httpReq, "删除文件", nil)
+}
+
+// doJSON 发送请求并将 JSON 响应解码到 out（out 为 nil 时丢弃响应体）
+func (p *Provider) doJSON(httpReq *http.Request, action string, out any) error {
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s请求失败: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return readAPIError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析%s响应失败: %w", action, err)
+	}
+	return nil
+}
+
+// readAPIError 读取非 2xx 响应体并构造错误
+func readAPIError(resp *http.Response) error {
+	bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 限制 1MB
+	if readErr != nil {
+		return fmt.Errorf("openai api error: %s (failed to read body: %v)", resp.Status, readErr)
+	}
+	return fmt.Errorf("openai api error: %s, body: %s", resp.Status, string(bodyBytes))
+}
@@ -3,10 +3,13 @@ package openai
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/hexagon-codes/ai-core/llm"
 )
@@ -21,14 +24,14 @@ func (p *Provider) GenerateImage(ctx context.Context, req llm.ImageRequest) (*ll
 	}
 
 	payload := imageGenRequest{
-		Model:  req.Model,
-		Prompt: req.Prompt,
-	}
-	if req.Size != "" {
-		payload.Size = req.Size
-	}
-	if req.N > 0 {
-		payload.N = req.N
+		Model:          req.Model,
+		Prompt:         req.Prompt,
+		Size:           req.Size,
+		N:              req.N,
+		ResponseFormat: req.ResponseFormat,
+		Quality:        req.Quality,
+		Style:          req.Style,
+		Background:     req.Background,
 	}
 
 	body, err := json.Marshal(payload)
@@ -42,9 +45,123 @@ func (p *Provider) GenerateImage(ctx context.Context, req llm.ImageRequest) (*ll
 	}
 	p.setHeaders(httpReq)
 
+	return p.doImageRequest(httpReq, "图片生成")
+}
+
+// EditImage 调用 /images/edits 端点编辑图片
+//
+// 原图与蒙版以 multipart/form-data 上传；多张原图（gpt-image-1）使用 image[] 字段。
+// 未指定 Model 时单张原图使用 dall-e-2，多张原图使用 gpt-image-1（聊天模型不能用于此端点）。
+func (p *Provider) EditImage(ctx context.Context, req llm.ImageEditRequest) (*llm.ImageResponse, error) {
+	if len(req.Images) == 0 {
+		return nil, fmt.Errorf("image edit requires at least one image")
+	}
+	if req.Model == "" {
+		req.Model = "dall-e-2"
+		if len(req.Images) > 1 {
+			req.Model = "gpt-image-1"
+		}
+	}
+
+	fields := map[string]string{
+		"model":           req.Model,
+		"prompt":          req.Prompt,
+		"size":            req.Size,
+		"response_format": req.ResponseFormat,
+		"quality":         req.Quality,
+		"background":      req.Background,
+	}
+	if req.N > 0 {
+		fields["n"] = strconv.Itoa(req.N)
+	}
+
+	imageField := "image"
+	if len(req.Images) > 1 {
+		imageField = "image[]"
+	}
+	files := make([]formFile, 0, len(req.Images)+1)
+	for _, img := range req.Images {
+		files = append(files, formFile{field: imageField, file: img})
+	}
+	if req.Mask != nil {
+		files = append(files, formFile{field: "mask", file: *req.Mask})
+	}
+
+	return p.postImageForm(ctx, "/images/edits", "图片编辑", fields, files)
+}
+
+// CreateImageVariation 调用 /images/variations 端点生成图片变体
+func (p *Provider) CreateImageVariation(ctx context.Context, req llm.ImageVariationRequest) (*llm.ImageResponse, error) {
+	if req.Image.Data == nil {
+		return nil, fmt.Errorf("image variation requires an image")
+	}
+	if req.Model == "" {
+		req.Model = "dall-e-2"
+	}
+
+	fields := map[string]string{
+		"model":           req.Model,
+		"size":            req.Size,
+		"response_format": req.ResponseFormat,
+	}
+	if req.N > 0 {
+		fields["n"] = strconv.Itoa(req.N)
+	}
+
+	return p.postImageForm(ctx, "/images/variations", "图片变体", fields, []formFile{{field: "image", file: req.Image}})
+}
+
+// formFile multipart 表单中的文件字段
+type formFile struct {
+	field string
+	file  llm.ImageFile
+}
+
+// postImageForm 以 multipart/form-data 提交图片请求
+func (p *Provider) postImageForm(ctx context.Context, path, action string, fields map[string]string, files []formFile) (*llm.ImageResponse, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, f := range files {
+		name := f.file.Name
+		if name == "" {
+			name = "image.png"
+		}
+		part, err := w.CreateFormFile(f.field, name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, f.file.Data); err != nil {
+			return nil, fmt.Errorf("读取图片失败: %w", err)
+		}
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := w.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+path, &buf)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuth(httpReq)
+	httpReq.Header.Set("Content-Type", w.FormDataContentType())
+
+	return p.doImageRequest(httpReq, action)
+}
+
+// doImageRequest 发送图片请求并解析响应，b64_json 结果会解码到 Bytes
+func (p *Provider) doImageRequest(httpReq *http.Request, action string) (*llm.ImageResponse, error) {
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("图片生成请求失败: %w", err)
+		return nil, fmt.Errorf("%s请求失败: %w", action, err)
 	}
 	defer resp.Body.Close()
 
@@ -58,15 +175,23 @@ func (p *Provider) GenerateImage(ctx context.Context, req llm.ImageRequest) (*ll
 
 	var result imageGenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("解析图片生成响应失败: %w", err)
+		return nil, fmt.Errorf("解析%s响应失败: %w", action, err)
 	}
 
 	images := make([]llm.ImageData, len(result.Data))
 	for i, img := range result.Data {
 		images[i] = llm.ImageData{
 			URL:           img.URL,
+			B64JSON:       img.B64JSON,
 			RevisedPrompt: img.RevisedPrompt,
 		}
+		if img.B64JSON != "" {
+			data, err := base64.StdEncoding.DecodeString(img.B64JSON)
+			if err != nil {
+				return nil, fmt.Errorf("解码图片 %d 失败: %w", i, err)
+			}
+			images[i].Bytes = data
+		}
 	}
 
 	return &llm.ImageResponse{Data: images}, nil
@@ -74,19 +199,27 @@ func (p *Provider) GenerateImage(ctx context.Context, req llm.ImageRequest) (*ll
 
 // imageGenRequest OpenAI Images API 请求结构
 type imageGenRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Size   string `json:"size,omitempty"`
-	N      int    `json:"n,omitempty"`
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	Size           string `json:"size,omitempty"`
+	N              int    `json:"n,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Style          string `json:"style,omitempty"`
+	Background     string `json:"background,omitempty"`
 }
 
 // imageGenResponse OpenAI Images API 响应结构
 type imageGenResponse struct {
 	Data []struct {
 		URL           string `json:"url"`
+		B64JSON       string `json:"b64_json,omitempty"`
 		RevisedPrompt string `json:"revised_prompt,omitempty"`
 	} `json:"data"`
 }
 
-// 确保 OpenAI Provider 实现了 ImageProvider 接口
-var _ llm.ImageProvider = (*Provider)(nil)
+// 确保 OpenAI Provider 实现了图片相关接口
+var (
+	_ llm.ImageProvider     = (*Provider)(nil)
+	_ llm.ImageEditProvider = (*Provider)(nil)
+)
@@ -0,0 +1,67 @@
+package openai
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestGenerateImage_DecodesB64JSON(t *testing.T) {
+	png := []byte("\x89PNG")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"response_format":"b64_json"`) || !strings.Contains(string(body), `"quality":"hd"`) {
+			t.Errorf("unexpected body: %s", body)
+		}
+		io.WriteString(w, `{"data":[{"b64_json":"`+base64.StdEncoding.EncodeToString(png)+`"}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.GenerateImage(t.Context(), llm.ImageRequest{Prompt: "cat", ResponseFormat: "b64_json", Quality: "hd"})
+	if err != nil {
+		t.Fatalf("GenerateImage error: %v", err)
+	}
+	if len(resp.Data) != 1 || string(resp.Data[0].Bytes) != string(png) {
+		t.Errorf("unexpected bytes: %+v", resp.Data)
+	}
+}
+
+func TestEditImage_UploadsImageAndMask(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/edits" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		// 未指定模型时不能退回聊天模型
+		if r.FormValue("prompt") != "add a hat" || r.FormValue("n") != "2" || r.FormValue("model") != "dall-e-2" {
+			t.Errorf("unexpected fields: %v", r.MultipartForm.Value)
+		}
+		if len(r.MultipartForm.File["image"]) != 1 || len(r.MultipartForm.File["mask"]) != 1 {
+			t.Errorf("unexpected files: %v", r.MultipartForm.File)
+		}
+		io.WriteString(w, `{"data":[{"url":"https://example.com/a.png"},{"url":"https://example.com/b.png"}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.EditImage(t.Context(), llm.ImageEditRequest{
+		Prompt: "add a hat",
+		Images: []llm.ImageFile{{Name: "cat.png", Data: strings.NewReader("img")}},
+		Mask:   &llm.ImageFile{Name: "mask.png", Data: strings.NewReader("mask")},
+		N:      2,
+	})
+	if err != nil {
+		t.Fatalf("EditImage error: %v", err)
+	}
+	if len(resp.Data) != 2 {
+		t.Errorf("expected 2 images, got %d", len(resp.Data))
+	}
+}
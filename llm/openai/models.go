@@ -0,0 +1,68 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// ListModels 调用 /models 获取可用模型，并与 Profile 中的模型元数据合并
+// 结果按 llm.DefaultModelCatalogTTL 缓存；仅返回对话模型（过滤 Embedding、语音、图片等）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.catalog.List(ctx)
+}
+
+// fetchModels 请求 /models 端点
+func (p *Provider) fetchModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setAuth(httpReq)
+
+	var result struct {
+		Data []struct {
+			ID            string `json:"id"`
+			ContextLength int    `json:"context_length,omitempty"` // Moonshot、SiliconFlow 等
+			MaxModelLen   int    `json:"max_model_len,omitempty"`  // vLLM
+		} `json:"data"`
+	}
+	if err := p.doJSON(httpReq, "模型列表", &result); err != nil {
+		return nil, err
+	}
+
+	models := make([]llm.ModelInfo, 0, len(result.Data))
+	for _, m := range result.Data {
+		if !isChatModel(m.ID) {
+			continue
+		}
+		info := llm.ModelInfo{ID: m.ID, MaxTokens: m.ContextLength}
+		if m.MaxModelLen > 0 {
+			info.MaxTokens = m.MaxModelLen
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// nonChatModelKeywords 非对话模型的名称特征
+var nonChatModelKeywords = []string{
+	"embed", "rerank", "moderation", "whisper", "tts", "transcribe", "asr",
+	"dall-e", "gpt-image", "sora", "audio", "realtime", "davinci", "babbage",
+}
+
+// isChatModel 判断模型是否为对话模型
+func isChatModel(id string) bool {
+	lower := strings.ToLower(id)
+	for _, kw := range nonChatModelKeywords {
+		if strings.Contains(lower, kw) {
+			return false
+		}
+	}
+	return true
+}
+
+// 确保实现了 ModelLister 接口
+var _ llm.ModelLister = (*Provider)(nil)
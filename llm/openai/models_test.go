@@ -0,0 +1,51 @@
+package openai
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListModels(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/models" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		io.WriteString(w, `{"data":[{"id":"gpt-4o-2024-11-20"},{"id":"gpt-9"},{"id":"text-embedding-3-small"},{"id":"whisper-1"},{"id":"local","max_model_len":4096}]}`)
+	}))
+	defer srv.Close()
+
+	p := New("sk-test", WithBaseURL(srv.URL))
+	models, err := p.ListModels(t.Context())
+	if err != nil {
+		t.Fatalf("ListModels error: %v", err)
+	}
+
+	byID := make(map[string]int)
+	for i, m := range models {
+		byID[m.ID] = i
+	}
+	if _, ok := byID["whisper-1"]; ok {
+		t.Error("non-chat models should be filtered")
+	}
+	if i, ok := byID["gpt-4o-2024-11-20"]; !ok || models[i].MaxTokens != 128000 {
+		t.Errorf("snapshot should inherit gpt-4o metadata: %+v", models)
+	}
+	if i, ok := byID["local"]; !ok || models[i].MaxTokens != 4096 {
+		t.Errorf("remote context length should be kept: %+v", models)
+	}
+	if _, ok := byID["gpt-9"]; !ok {
+		t.Error("new model should be listed")
+	}
+
+	if got := p.Models(); len(got) != len(models) {
+		t.Errorf("Models should return refreshed catalog, got %d", len(got))
+	}
+	p.ListModels(t.Context())
+	if calls != 1 {
+		t.Errorf("calls = %d, want cached", calls)
+	}
+}
@@ -0,0 +1,57 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+const defaultModerationModel = "omni-moderation-latest"
+
+// Moderate 调用 /moderations 端点审核文本
+//
+// 审核接口对 OpenAI 用户免费，可配合 llm.WithGuard 中间件使用。
+func (p *Provider) Moderate(ctx context.Context, req llm.ModerationRequest) (*llm.ModerationResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultModerationModel
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化审核请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("审核请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("openai moderation api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("openai moderation api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	var result llm.ModerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析审核响应失败: %w", err)
+	}
+	return &result, nil
+}
+
+// 确保 OpenAI Provider 实现了 ModerationProvider 接口
+var _ llm.ModerationProvider = (*Provider)(nil)
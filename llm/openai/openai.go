@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
@@ -28,6 +29,8 @@ type Provider struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
+	profile    Profile
+	catalog    *llm.ModelCatalog
 }
 
 // Option 是 Provider 的配置选项
@@ -57,37 +60,64 @@ func WithHTTPClient(client *http.Client) Option {
 // New 创建 OpenAI Provider
 // apiKey 可以为空，会从环境变量 OPENAI_API_KEY 读取
 func New(apiKey string, opts ...Option) *Provider {
-	if apiKey == "" {
-		apiKey = os.Getenv("OPENAI_API_KEY")
+	return NewCompatible(openAIProfile, apiKey, opts...)
+}
+
+// NewCompatible 按厂商 Profile 创建 OpenAI 兼容 Provider
+// apiKey 可以为空，会依次从 profile.APIKeyEnv 列出的环境变量读取
+func NewCompatible(profile Profile, apiKey string, opts ...Option) *Provider {
+	for _, env := range profile.APIKeyEnv {
+		if apiKey != "" {
+			break
+		}
+		apiKey = os.Getenv(env)
+	}
+	if profile.ReasoningField == "" {
+		profile.ReasoningField = "reasoning_content"
+	}
+	if profile.Reasoning == nil {
+		profile.Reasoning = ReasoningEffort
+	}
+	if profile.CharsPerToken <= 0 {
+		profile.CharsPerToken = 4
 	}
 
 	p := &Provider{
 		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		model:   defaultModel,
+		baseURL: profile.BaseURL,
+		model:   profile.DefaultModel,
 		// 不设全局 Timeout — 流式请求的超时由调用方 context 控制
 		// http.Client.Timeout 对流式响应会在整个读取期间生效，
 		// thinking 模型（Qwen3/DeepSeek-R1）可能需要数分钟
 		httpClient: httpx.RawClient(httpx.WithResponseHeaderTimeout(120 * time.Second)),
+		profile:    profile,
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.catalog = llm.NewModelCatalog(profile.Models, p.fetchModels, 0)
 
 	return p
 }
 
 // Name 返回提供者名称
 func (p *Provider) Name() string {
-	return "openai"
+	return p.profile.Name
 }
 
 // Complete 执行非流式补全请求
+// 厂商不支持 n 参数时，n>1 通过并发请求模拟
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
 	if req.Model == "" {
 		req.Model = p.model
 	}
+	if req.N > 1 && !p.profile.supports("n") {
+		return llm.CompleteChoices(ctx, p, req)
+	}
+	if err := p.checkRequest(req); err != nil {
+		return nil, err
+	}
 
 	body, err := p.buildRequestBody(req, false)
 	if err != nil {
@@ -103,8 +133,8 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
-		logger.WarnContext(ctx, "openai http request failed",
-			logger.Component("openai"),
+		logger.WarnContext(ctx, p.profile.Name+" http request failed",
+			logger.Component(p.profile.Name),
 			logger.Action("complete"),
 			logger.String("model", req.Model),
 			logger.String("base_url", p.baseURL),
@@ -117,29 +147,29 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, readErr := io.ReadAll(resp.Body)
 		if readErr != nil {
-			logger.ErrorContext(ctx, "openai api error and body read failed",
-				logger.Component("openai"),
+			logger.ErrorContext(ctx, p.profile.Name+" api error and body read failed",
+				logger.Component(p.profile.Name),
 				logger.Action("complete"),
 				logger.String("model", req.Model),
 				logger.Status(resp.StatusCode),
 				logger.Err(readErr),
 			)
-			return nil, fmt.Errorf("openai api error: %s (failed to read body: %v)", resp.Status, readErr)
+			return nil, fmt.Errorf("%s api error: %s (failed to read body: %v)", p.profile.Name, resp.Status, readErr)
 		}
-		logger.ErrorContext(ctx, "openai api non-2xx response",
-			logger.Component("openai"),
+		logger.ErrorContext(ctx, p.profile.Name+" api non-2xx response",
+			logger.Component(p.profile.Name),
 			logger.Action("complete"),
 			logger.String("model", req.Model),
 			logger.Status(resp.StatusCode),
 			logger.String("body", string(bodyBytes)),
 		)
-		return nil, fmt.Errorf("openai api error: %s, body: %s", resp.Status, string(bodyBytes))
+		return nil, fmt.Errorf("%s api error: %s, body: %s", p.profile.Name, resp.Status, string(bodyBytes))
 	}
 
 	var result openAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		logger.WarnContext(ctx, "openai response json decode failed",
-			logger.Component("openai"),
+		logger.WarnContext(ctx, p.profile.Name+" response json decode failed",
+			logger.Component(p.profile.Name),
 			logger.Action("complete"),
 			logger.String("model", req.Model),
 			logger.Err(err),
@@ -147,7 +177,11 @@ func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*ll
 		return nil, err
 	}
 
-	return p.parseResponse(&result), nil
+	response := p.parseResponse(&result)
+	if req.Reasoning.Excluded() {
+		response.StripReasoning()
+	}
+	return response, nil
 }
 
 // Stream 执行流式补全请求
@@ -155,6 +189,12 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 	if req.Model == "" {
 		req.Model = p.model
 	}
+	if req.N > 1 && !p.profile.supports("n") {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
+	}
+	if err := p.checkRequest(req); err != nil {
+		return nil, err
+	}
 
 	body, err := p.buildRequestBody(req, true)
 	if err != nil {
@@ -170,8 +210,8 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
-		logger.WarnContext(ctx, "openai stream http request failed",
-			logger.Component("openai"),
+		logger.WarnContext(ctx, p.profile.Name+" stream http request failed",
+			logger.Component(p.profile.Name),
 			logger.Action("stream"),
 			logger.String("model", req.Model),
 			logger.String("base_url", p.baseURL),
@@ -184,102 +224,86 @@ func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*stre
 		bodyBytes, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if readErr != nil {
-			logger.ErrorContext(ctx, "openai stream api error and body read failed",
-				logger.Component("openai"),
+			logger.ErrorContext(ctx, p.profile.Name+" stream api error and body read failed",
+				logger.Component(p.profile.Name),
 				logger.Action("stream"),
 				logger.String("model", req.Model),
 				logger.Status(resp.StatusCode),
 				logger.Err(readErr),
 			)
-			return nil, fmt.Errorf("openai api error: %s (failed to read body: %v)", resp.Status, readErr)
+			return nil, fmt.Errorf("%s api error: %s (failed to read body: %v)", p.profile.Name, resp.Status, readErr)
 		}
-		logger.ErrorContext(ctx, "openai stream api non-2xx response",
-			logger.Component("openai"),
+		logger.ErrorContext(ctx, p.profile.Name+" stream api non-2xx response",
+			logger.Component(p.profile.Name),
 			logger.Action("stream"),
 			logger.String("model", req.Model),
 			logger.Status(resp.StatusCode),
 			logger.String("body", string(bodyBytes)),
 		)
-		return nil, fmt.Errorf("openai api error: %s, body: %s", resp.Status, string(bodyBytes))
+		return nil, fmt.Errorf("%s api error: %s, body: %s", p.profile.Name, resp.Status, string(bodyBytes))
 	}
 
 	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.OpenAIFormat), nil
 }
 
 // Models 返回可用模型列表
+// ListModels 成功后返回远端目录，否则返回 Profile 中的内置列表
 func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
-		{
-			ID:          "gpt-4o",
-			Name:        "GPT-4o",
-			Description: "Most capable model, great for complex tasks",
-			MaxTokens:   128000,
-			InputCost:   2.50,
-			OutputCost:  10.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gpt-4o-mini",
-			Name:        "GPT-4o Mini",
-			Description: "Fast and cost-effective for simpler tasks",
-			MaxTokens:   128000,
-			InputCost:   0.15,
-			OutputCost:  0.60,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "gpt-4-turbo",
-			Name:        "GPT-4 Turbo",
-			Description: "Previous generation flagship model",
-			MaxTokens:   128000,
-			InputCost:   10.00,
-			OutputCost:  30.00,
-			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
-		},
-		{
-			ID:          "o1",
-			Name:        "o1",
-			Description: "Reasoning model for complex tasks",
-			MaxTokens:   200000,
-			InputCost:   15.00,
-			OutputCost:  60.00,
-			Features:    []string{llm.FeatureStreaming},
-		},
-		{
-			ID:          "o3-mini",
-			Name:        "o3-mini",
-			Description: "Fast reasoning model",
-			MaxTokens:   200000,
-			InputCost:   1.10,
-			OutputCost:  4.40,
-			Features:    []string{llm.FeatureStreaming},
-		},
-	}
+	return p.catalog.Models()
 }
 
 // CountTokens 计算消息的 Token 数量（简化实现）
 func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
-	// 简化估算：约 4 个字符一个 token
-	var total int
+	// 简化估算：按 Profile 的每 Token 字符数换算（英文约 4，中英混合约 2.5）
+	var chars int
 	for _, msg := range messages {
-		total += len(msg.Content) / 4
+		chars += len(msg.Content)
 	}
-	return total, nil
+	return int(float64(chars) / p.profile.CharsPerToken), nil
 }
 
 // setHeaders 设置请求头
 func (p *Provider) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
+	p.setAuth(req)
+}
+
+// setAuth 按 Profile 设置认证请求头
+func (p *Provider) setAuth(req *http.Request) {
+	if h := p.profile.AuthHeader; h != "" {
+		req.Header.Set(h, p.apiKey)
+		return
+	}
 	req.Header.Set("Authorization", "Bearer "+p.apiKey)
 }
 
+// checkRequest 检查请求是否使用了厂商不支持的能力
+func (p *Provider) checkRequest(req llm.CompletionRequest) error {
+	if req.WantsLogprobs() && !p.profile.supports("logprobs") {
+		return &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		if p.profile.Constraints == nil {
+			return &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+		}
+		if err := req.Constraint.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // buildRequestBody 构建请求体
 func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]byte, error) {
 	payload := map[string]any{
 		"model":    req.Model,
-		"messages": convertMessages(req.Messages),
+		"messages": p.convertMessages(llm.ApplyReasoningPolicy(req.Messages, req.ReasoningHistory)),
 		"stream":   stream,
 	}
+	if stream && !p.profile.DisableStreamUsage {
+		// 要求在最后一个块中返回 usage，否则流式请求无法统计 Token
+		payload["stream_options"] = map[string]any{"include_usage": true}
+	}
 
 	if len(req.Tools) > 0 {
 		payload["tools"] = req.Tools
@@ -290,6 +314,15 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 	if req.MaxTokens > 0 {
 		payload["max_tokens"] = req.MaxTokens
 	}
+	if req.N > 1 {
+		payload["n"] = req.N
+	}
+	if req.WantsLogprobs() {
+		payload["logprobs"] = true
+		if req.TopLogprobs > 0 {
+			payload["top_logprobs"] = req.TopLogprobs
+		}
+	}
 	if req.Temperature != nil {
 		payload["temperature"] = *req.Temperature
 	}
@@ -302,6 +335,32 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 	if req.User != "" {
 		payload["user"] = req.User
 	}
+	if req.Seed != nil {
+		payload["seed"] = *req.Seed
+	}
+	if req.PresencePenalty != nil {
+		payload["presence_penalty"] = *req.PresencePenalty
+	}
+	if req.FrequencyPenalty != nil {
+		payload["frequency_penalty"] = *req.FrequencyPenalty
+	}
+	if req.TopK != nil {
+		payload["top_k"] = *req.TopK
+	}
+	if req.RepetitionPenalty != nil {
+		payload["repetition_penalty"] = *req.RepetitionPenalty
+	}
+	if req.ParallelToolCalls != nil && len(req.Tools) > 0 {
+		payload["parallel_tool_calls"] = *req.ParallelToolCalls
+	}
+	if req.Reasoning != nil {
+		p.profile.Reasoning(payload, req.Reasoning)
+	}
+	if req.Constraint != nil && p.profile.Constraints != nil {
+		if err := p.profile.Constraints(payload, req.Constraint); err != nil {
+			return nil, err
+		}
+	}
 
 	// ResponseFormat 支持
 	if req.ResponseFormat != nil {
@@ -326,11 +385,26 @@ func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]b
 		}
 	}
 
+	// 移除厂商不支持的参数并按厂商命名改写，ExtraBody 中的参数原样发送
+	for _, param := range p.profile.Unsupported {
+		delete(payload, param)
+	}
+	for from, to := range p.profile.RenameParams {
+		if v, ok := payload[from]; ok {
+			delete(payload, from)
+			payload[to] = v
+		}
+	}
+
+	if err := llm.MergeExtraBody(payload, req.ExtraBody, "stream_options"); err != nil {
+		return nil, err
+	}
+
 	return json.Marshal(payload)
 }
 
 // convertMessages 转换消息格式
-func convertMessages(messages []llm.Message) []map[string]any {
+func (p *Provider) convertMessages(messages []llm.Message) []map[string]any {
 	result := make([]map[string]any, len(messages))
 	for i, msg := range messages {
 		m := map[string]any{
@@ -369,8 +443,31 @@ func convertMessages(messages []llm.Message) []map[string]any {
 		if msg.Name != "" {
 			m["name"] = msg.Name
 		}
+		if msg.Reasoning != "" && msg.Role == llm.RoleAssistant {
+			m[p.profile.ReasoningField] = msg.Reasoning
+		}
+		if len(msg.ToolCalls) > 0 {
+			toolCalls := make([]map[string]any, len(msg.ToolCalls))
+			for j, tc := range msg.ToolCalls {
+				toolCalls[j] = map[string]any{
+					"id":   tc.ID,
+					"type": "function",
+					"function": map[string]any{
+						"name":      tc.Name,
+						"arguments": tc.Arguments,
+					},
+				}
+			}
+			m["tool_calls"] = toolCalls
+		}
+		if msg.ToolCallID != "" {
+			m["tool_call_id"] = msg.ToolCallID
+		}
 		result[i] = m
 	}
+	if n := len(messages); n > 0 && p.profile.PrefixCompletion && messages[n-1].Role == llm.RoleAssistant {
+		result[n-1]["prefix"] = true
+	}
 	return result
 }
 
@@ -382,9 +479,11 @@ type openAIResponse struct {
 	Choices []struct {
 		Index   int `json:"index"`
 		Message struct {
-			Role      string `json:"role"`
-			Content   string `json:"content"`
-			ToolCalls []struct {
+			Role             string `json:"role"`
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content,omitempty"` // DeepSeek-R1、QwQ、豆包深度思考
+			Reasoning        string `json:"reasoning,omitempty"`         // 部分兼容服务（vLLM 等）
+			ToolCalls        []struct {
 				ID       string `json:"id"`
 				Type     string `json:"type"`
 				Function struct {
@@ -393,13 +492,10 @@ type openAIResponse struct {
 				} `json:"function"`
 			} `json:"tool_calls,omitempty"`
 		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
+		Logprobs     *streamx.OpenAILogprobs `json:"logprobs,omitempty"`
+		FinishReason string                  `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage streamx.OpenAIUsage `json:"usage"`
 }
 
 // parseResponse 解析响应
@@ -408,27 +504,35 @@ func (p *Provider) parseResponse(resp *openAIResponse) *llm.CompletionResponse {
 		ID:      resp.ID,
 		Model:   resp.Model,
 		Created: resp.Created,
-		Usage: llm.Usage{
-			PromptTokens:     resp.Usage.PromptTokens,
-			CompletionTokens: resp.Usage.CompletionTokens,
-			TotalTokens:      resp.Usage.TotalTokens,
-		},
+		Usage:   resp.Usage.ToUsage(),
 	}
 
-	if len(resp.Choices) > 0 {
-		choice := resp.Choices[0]
-		result.Content = choice.Message.Content
-		result.FinishReason = choice.FinishReason
-
+	choices := make([]llm.Choice, len(resp.Choices))
+	for i, choice := range resp.Choices {
+		c := llm.Choice{
+			Index:        choice.Index,
+			Content:      choice.Message.Content,
+			Reasoning:    choice.Message.ReasoningContent,
+			FinishReason: choice.FinishReason,
+		}
+		if c.Reasoning == "" {
+			c.Reasoning = choice.Message.Reasoning
+		}
+		if choice.Logprobs != nil {
+			c.Logprobs = choice.Logprobs.Content
+		}
 		for _, tc := range choice.Message.ToolCalls {
-			result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
+			c.ToolCalls = append(c.ToolCalls, llm.ToolCall{
 				ID:        tc.ID,
 				Type:      tc.Type,
 				Name:      tc.Function.Name,
 				Arguments: tc.Function.Arguments,
 			})
 		}
+		choices[i] = c
 	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Index < choices[j].Index })
+	result.SetChoices(choices)
 
 	return result
 }
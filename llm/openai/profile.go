@@ -0,0 +1,216 @@
+package openai
+
+import (
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/schema"
+)
+
+// Profile 描述一个 OpenAI 兼容厂商的差异
+//
+// 各厂商的 /chat/completions 协议大体一致，差异集中在认证方式、模型目录、
+// 支持的参数、参数命名和推理开关上。新增厂商只需提供一份 Profile：
+//
+//	p := openai.NewCompatible(openai.Profile{
+//	    Name:         "my-vendor",
+//	    BaseURL:      "https://api.example.com/v1",
+//	    DefaultModel: "example-chat",
+//	    APIKeyEnv:    []string{"EXAMPLE_API_KEY"},
+//	    Unsupported:  []string{"n", "logprobs"},
+//	}, "")
+//
+// 常见厂商的 Profile 见 llm/compat 包。
+type Profile struct {
+	// Name Provider 名称（Name() 的返回值，同时用于日志与错误信息）
+	Name string
+
+	// BaseURL 默认 API 基础 URL
+	BaseURL string
+
+	// DefaultModel 默认模型
+	DefaultModel string
+
+	// RerankModel Rerank 请求未指定模型时使用的重排序模型
+	// 为空时请求必须指定 Model（聊天模型不能用于 /rerank）
+	RerankModel string
+
+	// APIKeyEnv apiKey 为空时依次读取的环境变量
+	APIKeyEnv []string
+
+	// AuthHeader 认证请求头
+	// 为空时发送 "Authorization: Bearer <key>"；设置后以该请求头直接发送 key（如 "api-key"）
+	AuthHeader string
+
+	// Models 模型目录（Models() 的返回值）
+	Models []llm.ModelInfo
+
+	// Unsupported 厂商不支持的请求参数（请求体字段名）
+	//   - "n": 通过并发请求模拟 n>1，流式 n>1 返回 *llm.UnsupportedError
+	//   - "logprobs": 请求 logprobs 时返回 *llm.UnsupportedError
+	//   - 其余参数在发送前移除（仍可通过 ExtraBody 显式传递）
+	Unsupported []string
+
+	// RenameParams 请求参数改名（如 {"max_tokens": "max_completion_tokens"}）
+	RenameParams map[string]string
+
+	// ReasoningField 历史 assistant 消息回传推理内容时使用的字段名，默认 "reasoning_content"
+	ReasoningField string
+
+	// Reasoning 将推理控制写入请求体，nil 时使用 ReasoningEffort
+	Reasoning func(payload map[string]any, cfg *llm.ReasoningConfig)
+
+	// Constraints 将约束解码参数写入请求体，nil 表示不支持 CompletionRequest.Constraint
+	// 厂商不支持某类约束时应返回 *llm.UnsupportedError
+	Constraints func(payload map[string]any, c *llm.Constraint) error
+
+	// PrefixCompletion 对话前缀续写：最后一条消息为 assistant 时标记 prefix: true，
+	// 模型从该消息末尾继续生成（DeepSeek beta、Mistral）
+	PrefixCompletion bool
+
+	// CompletionsURL 文本补全（/completions）端点，nil 时为 BaseURL + "/completions"
+	// 用于补全接口与 Chat 接口路径不同的厂商（如 DeepSeek 的 FIM 位于 /beta）
+	CompletionsURL func(baseURL string) string
+
+	// FIMPrompt 将前缀与后缀拼接为模型的 FIM 提示词，nil 时通过 suffix 参数发送后缀
+	// 用于不支持 suffix 参数、需使用 FIM 特殊 Token 的模型（如 Qwen2.5-Coder）
+	FIMPrompt func(prefix, suffix string) string
+
+	// DisableStreamUsage 流式请求不发送 stream_options.include_usage（部分服务会拒绝该参数）
+	DisableStreamUsage bool
+
+	// CharsPerToken CountTokens 估算时每个 Token 对应的字符数，默认 4（英文）
+	CharsPerToken float64
+}
+
+// supports 检查厂商是否支持指定请求参数
+func (p *Profile) supports(param string) bool {
+	for _, name := range p.Unsupported {
+		if name == param {
+			return false
+		}
+	}
+	return true
+}
+
+// QwenFIMPrompt 使用 Qwen2.5-Coder 的 FIM 特殊 Token 拼接提示词
+func QwenFIMPrompt(prefix, suffix string) string {
+	return "<|fim_prefix|>" + prefix + "<|fim_suffix|>" + suffix + "<|fim_middle|>"
+}
+
+// ReasoningEffort 使用 OpenAI 的 reasoning_effort 参数（o 系列/GPT-5 推理模型）
+// 非推理模型会拒绝该参数，调用方应先检查 FeatureReasoning
+func ReasoningEffort(payload map[string]any, cfg *llm.ReasoningConfig) {
+	if effort := cfg.EffortLevel(); effort != "" {
+		payload["reasoning_effort"] = string(effort)
+	}
+}
+
+// ThinkingToggle 使用 thinking{type: enabled|disabled} 开关（火山方舟、智谱 GLM）
+// 仅支持开关，不支持预算
+func ThinkingToggle(payload map[string]any, cfg *llm.ReasoningConfig) {
+	if !cfg.Specified() {
+		return
+	}
+	thinkingType := "disabled"
+	if cfg.Enabled() {
+		thinkingType = "enabled"
+	}
+	payload["thinking"] = map[string]any{"type": thinkingType}
+}
+
+// EnableThinking 使用 enable_thinking/thinking_budget 参数（通义千问 Qwen3/QwQ、SiliconFlow）
+func EnableThinking(payload map[string]any, cfg *llm.ReasoningConfig) {
+	if !cfg.Specified() {
+		return
+	}
+	payload["enable_thinking"] = cfg.Enabled()
+	if budget := cfg.Budget(); budget > 0 {
+		payload["thinking_budget"] = budget
+	}
+}
+
+// GuidedDecoding 使用 vLLM 的 guided_json/guided_regex/guided_grammar/guided_choice 参数
+func GuidedDecoding(payload map[string]any, c *llm.Constraint) error {
+	switch {
+	case c.JSONSchema != nil:
+		payload["guided_json"] = c.JSONSchema
+	case c.Regex != "":
+		payload["guided_regex"] = c.Regex
+	case c.Grammar != "":
+		payload["guided_grammar"] = c.Grammar
+	case len(c.Choice) > 0:
+		payload["guided_choice"] = c.Choice
+	}
+	return nil
+}
+
+// LlamaCppGrammar 使用 llama.cpp server 的 json_schema/grammar 参数
+// 选项列表转换为 GBNF 语法；不支持正则约束
+func LlamaCppGrammar(payload map[string]any, c *llm.Constraint) error {
+	switch {
+	case c.JSONSchema != nil:
+		payload["json_schema"] = c.JSONSchema
+	case c.Grammar != "":
+		payload["grammar"] = c.Grammar
+	case len(c.Choice) > 0:
+		payload["grammar"] = schema.ChoiceGBNF(c.Choice)
+	case c.Regex != "":
+		return &llm.UnsupportedError{Provider: "llama.cpp", Feature: "regex constraint"}
+	}
+	return nil
+}
+
+// openAIProfile OpenAI 官方 API
+var openAIProfile = Profile{
+	Name:         "openai",
+	BaseURL:      defaultBaseURL,
+	DefaultModel: defaultModel,
+	APIKeyEnv:    []string{"OPENAI_API_KEY"},
+	Unsupported:  []string{"top_k", "repetition_penalty"},
+	Models: []llm.ModelInfo{
+		{
+			ID:          "gpt-4o",
+			Name:        "GPT-4o",
+			Description: "Most capable model, great for complex tasks",
+			MaxTokens:   128000,
+			InputCost:   2.50,
+			OutputCost:  10.00,
+			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "gpt-4o-mini",
+			Name:        "GPT-4o Mini",
+			Description: "Fast and cost-effective for simpler tasks",
+			MaxTokens:   128000,
+			InputCost:   0.15,
+			OutputCost:  0.60,
+			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "gpt-4-turbo",
+			Name:        "GPT-4 Turbo",
+			Description: "Previous generation flagship model",
+			MaxTokens:   128000,
+			InputCost:   10.00,
+			OutputCost:  30.00,
+			Features:    []string{llm.FeatureVision, llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+		},
+		{
+			ID:          "o1",
+			Name:        "o1",
+			Description: "Reasoning model for complex tasks",
+			MaxTokens:   200000,
+			InputCost:   15.00,
+			OutputCost:  60.00,
+			Features:    []string{llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+		{
+			ID:          "o3-mini",
+			Name:        "o3-mini",
+			Description: "Fast reasoning model",
+			MaxTokens:   200000,
+			InputCost:   1.10,
+			OutputCost:  4.40,
+			Features:    []string{llm.FeatureStreaming, llm.FeatureReasoning},
+		},
+	},
+}
@@ -0,0 +1,220 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+const chatReply = `{"id":"c1","model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`
+
+func TestNewCompatible_ProfileQuirks(t *testing.T) {
+	var (
+		body   map[string]any
+		header http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		json.NewDecoder(r.Body).Decode(&body)
+		io.WriteString(w, chatReply)
+	}))
+	defer srv.Close()
+
+	p := NewCompatible(Profile{
+		Name:           "vendor",
+		BaseURL:        srv.URL,
+		DefaultModel:   "vendor-chat",
+		AuthHeader:     "api-key",
+		Unsupported:    []string{"seed", "top_k"},
+		RenameParams:   map[string]string{"max_tokens": "max_completion_tokens"},
+		ReasoningField: "reasoning",
+		Reasoning:      ThinkingToggle,
+	}, "secret")
+
+	seed, topK := 7, 40
+	_, err := p.Complete(t.Context(), llm.CompletionRequest{
+		Messages: []llm.Message{
+			{Role: llm.RoleUser, Content: "hi"},
+			{Role: llm.RoleAssistant, Reasoning: "thought", ToolCalls: []llm.ToolCallRef{{ID: "call_1", Name: "lookup", Arguments: `{}`}}},
+			{Role: llm.RoleTool, Content: "42", ToolCallID: "call_1"},
+		},
+		MaxTokens:        100,
+		Seed:             &seed,
+		TopK:             &topK,
+		Reasoning:        &llm.ReasoningConfig{Effort: llm.ReasoningEffortHigh},
+		ReasoningHistory: llm.ReasoningPreserve,
+		ExtraBody:        map[string]any{"seed": 9},
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+
+	if got := header.Get("api-key"); got != "secret" {
+		t.Errorf("api-key header = %q", got)
+	}
+	if got := header.Get("Authorization"); got != "" {
+		t.Errorf("unexpected Authorization header %q", got)
+	}
+	if body["model"] != "vendor-chat" {
+		t.Errorf("model = %v", body["model"])
+	}
+	if _, ok := body["top_k"]; ok {
+		t.Error("unsupported top_k should be dropped")
+	}
+	if body["seed"] != float64(9) {
+		t.Errorf("seed from ExtraBody should be kept, got %v", body["seed"])
+	}
+	if _, ok := body["max_tokens"]; ok || body["max_completion_tokens"] != float64(100) {
+		t.Errorf("max_tokens not renamed: %v", body)
+	}
+	if _, ok := body["reasoning_effort"]; ok {
+		t.Error("reasoning_effort should not be sent with ThinkingToggle")
+	}
+	if thinking, _ := body["thinking"].(map[string]any); thinking["type"] != "enabled" {
+		t.Errorf("thinking = %v", body["thinking"])
+	}
+
+	msgs := body["messages"].([]any)
+	assistant := msgs[1].(map[string]any)
+	if assistant["reasoning"] != "thought" {
+		t.Errorf("assistant reasoning field = %v", assistant)
+	}
+	calls, _ := assistant["tool_calls"].([]any)
+	if len(calls) != 1 || calls[0].(map[string]any)["id"] != "call_1" {
+		t.Errorf("tool_calls = %v", assistant["tool_calls"])
+	}
+	if msgs[2].(map[string]any)["tool_call_id"] != "call_1" {
+		t.Errorf("tool message = %v", msgs[2])
+	}
+}
+
+func TestNewCompatible_UnsupportedN(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["n"]; ok {
+			t.Errorf("n should not be sent: %v", body["n"])
+		}
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		io.WriteString(w, chatReply)
+	}))
+	defer srv.Close()
+
+	p := NewCompatible(Profile{Name: "vendor", BaseURL: srv.URL, Unsupported: []string{"n", "logprobs"}}, "k")
+	req := llm.CompletionRequest{Model: "m", Messages: llm.NewMessages("", "hi"), N: 3}
+
+	resp, err := p.Complete(t.Context(), req)
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if calls != 3 || len(resp.Choices) != 3 {
+		t.Errorf("calls = %d, choices = %d, want 3", calls, len(resp.Choices))
+	}
+
+	var unsupported *llm.UnsupportedError
+	if _, err := p.Stream(t.Context(), req); !errors.As(err, &unsupported) {
+		t.Errorf("Stream with n>1 error = %v, want UnsupportedError", err)
+	}
+	if _, err := p.Complete(t.Context(), llm.CompletionRequest{Model: "m", Messages: llm.NewMessages("", "hi"), Logprobs: true}); !errors.As(err, &unsupported) {
+		t.Errorf("logprobs error = %v, want UnsupportedError", err)
+	}
+}
+
+func TestNewCompatible_Defaults(t *testing.T) {
+	t.Setenv("VENDOR_KEY_A", "")
+	t.Setenv("VENDOR_KEY_B", "from-env")
+
+	p := NewCompatible(Profile{Name: "vendor", APIKeyEnv: []string{"VENDOR_KEY_A", "VENDOR_KEY_B"}}, "")
+	if p.apiKey != "from-env" {
+		t.Errorf("apiKey = %q", p.apiKey)
+	}
+	if p.Name() != "vendor" {
+		t.Errorf("Name = %q", p.Name())
+	}
+	n, _ := p.CountTokens([]llm.Message{{Content: "12345678"}})
+	if n != 2 {
+		t.Errorf("CountTokens = %d, want 2", n)
+	}
+	if New("k").Name() != "openai" || len(New("k").Models()) == 0 {
+		t.Error("openai profile not applied")
+	}
+}
+
+func TestNewCompatible_Constraints(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		io.WriteString(w, chatReply)
+	}))
+	defer srv.Close()
+	msgs := []llm.Message{{Role: llm.RoleUser, Content: "hi"}}
+
+	vllm := NewCompatible(Profile{Name: "vllm", BaseURL: srv.URL, Constraints: GuidedDecoding}, "k")
+	if _, err := vllm.Complete(t.Context(), llm.CompletionRequest{Messages: msgs, Constraint: &llm.Constraint{Regex: `\d+`}}); err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if body["guided_regex"] != `\d+` {
+		t.Errorf("vllm body = %v", body)
+	}
+
+	llamacpp := NewCompatible(Profile{Name: "llamacpp", BaseURL: srv.URL, Constraints: LlamaCppGrammar}, "k")
+	if _, err := llamacpp.Complete(t.Context(), llm.CompletionRequest{Messages: msgs, Constraint: &llm.Constraint{Choice: []string{"yes", "no"}}}); err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if body["grammar"] != "root ::= \"yes\" | \"no\"\n" {
+		t.Errorf("llama.cpp body = %v", body)
+	}
+
+	var unsupported *llm.UnsupportedError
+	_, err := llamacpp.Complete(t.Context(), llm.CompletionRequest{Messages: msgs, Constraint: &llm.Constraint{Regex: "a+"}})
+	if !errors.As(err, &unsupported) {
+		t.Errorf("llama.cpp regex error = %v", err)
+	}
+	_, err = New("k", WithBaseURL(srv.URL)).Complete(t.Context(), llm.CompletionRequest{Messages: msgs, Constraint: &llm.Constraint{Grammar: "root ::= \"a\""}})
+	if !errors.As(err, &unsupported) {
+		t.Errorf("openai constraint error = %v", err)
+	}
+	_, err = vllm.Complete(t.Context(), llm.CompletionRequest{Messages: msgs, Constraint: &llm.Constraint{Regex: "a", Grammar: "g"}})
+	if err == nil || errors.As(err, &unsupported) {
+		t.Errorf("multiple constraints error = %v", err)
+	}
+}
+
+func TestReasoningMappers_Unspecified(t *testing.T) {
+	unspecified := &llm.ReasoningConfig{Exclude: true}
+	for name, mapper := range map[string]func(map[string]any, *llm.ReasoningConfig){
+		"ReasoningEffort": ReasoningEffort,
+		"ThinkingToggle":  ThinkingToggle,
+		"EnableThinking":  EnableThinking,
+	} {
+		payload := map[string]any{}
+		mapper(payload, unspecified)
+		if len(payload) != 0 {
+			t.Errorf("%s with unspecified config set %v", name, payload)
+		}
+	}
+
+	payload := map[string]any{}
+	EnableThinking(payload, &llm.ReasoningConfig{BudgetTokens: 2048})
+	if payload["enable_thinking"] != true || payload["thinking_budget"] != 2048 {
+		t.Errorf("EnableThinking payload = %v", payload)
+	}
+	payload = map[string]any{}
+	ThinkingToggle(payload, &llm.ReasoningConfig{Effort: llm.ReasoningEffortNone})
+	if payload["thinking"].(map[string]any)["type"] != "disabled" {
+		t.Errorf("ThinkingToggle payload = %v", payload)
+	}
+}
@@ -0,0 +1,22 @@
+package openai
+
+import "github.com/hexagon-codes/ai-core/llm"
+
+func init() {
+	llm.RegisterProvider("openai", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		return New(cfg.APIKey, ConfigOptions(cfg)...), nil
+	})
+}
+
+// ConfigOptions 将通用配置中的 BaseURL、Model 转换为 Option
+// 供基于 OpenAI 兼容协议的 Provider 注册工厂复用
+func ConfigOptions(cfg llm.ProviderConfig) []Option {
+	var opts []Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Model != "" {
+		opts = append(opts, WithModel(cfg.Model))
+	}
+	return opts
+}
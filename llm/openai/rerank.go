@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// Rerank 调用 Jina/Cohere 风格的 /rerank 端点重排序文档
+//
+// OpenAI 官方不提供重排序接口，此方法用于 baseURL 指向 Jina、Cohere 兼容网关、
+// SiliconFlow、vLLM 等提供 /rerank 的服务。
+// req.Model 为空时使用 Profile.RerankModel，二者均为空时返回错误。
+func (p *Provider) Rerank(ctx context.Context, req llm.RerankRequest) (*llm.RerankResponse, error) {
+	if req.Model == "" {
+		req.Model = p.profile.RerankModel
+	}
+	if req.Model == "" {
+		return nil, fmt.Errorf("%s rerank: model is required", p.Name())
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化重排序请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("重排序请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("openai rerank api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("openai rerank api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	var result rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析重排序响应失败: %w", err)
+	}
+
+	out := &llm.RerankResponse{
+		ID:    result.ID,
+		Model: result.Model,
+		Usage: llm.Usage{
+			PromptTokens: result.Usage.PromptTokens,
+			TotalTokens:  result.Usage.TotalTokens,
+		},
+		Results: make([]llm.RerankResult, len(result.Results)),
+	}
+	for i, r := range result.Results {
+		out.Results[i] = llm.RerankResult{
+			Index:          r.Index,
+			RelevanceScore: r.RelevanceScore,
+			Document:       rerankDocumentText(r.Document),
+		}
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool {
+		return out.Results[i].RelevanceScore > out.Results[j].RelevanceScore
+	})
+	return out, nil
+}
+
+// rerankResponse Jina/Cohere 风格的重排序响应结构
+type rerankResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Results []struct {
+		Index          int             `json:"index"`
+		RelevanceScore float64         `json:"relevance_score"`
+		Document       json.RawMessage `json:"document,omitempty"`
+	} `json:"results"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// rerankDocumentText 提取回带的文档原文
+// Jina/Cohere 返回 {"text": "..."}，部分兼容服务直接返回字符串
+func rerankDocumentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var doc struct {
+		Text string `json:"text"`
+	}
+	_ = json.Unmarshal(raw, &doc)
+	return doc.Text
+}
+
+// 确保 OpenAI Provider 实现了 RerankProvider 接口
+var _ llm.RerankProvider = (*Provider)(nil)
@@ -0,0 +1,46 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestRerank_Model(t *testing.T) {
+	var gotModel any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		gotModel = body["model"]
+		fmt.Fprint(w, `{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.1}]}`)
+	}))
+	defer srv.Close()
+
+	req := llm.RerankRequest{Query: "q", Documents: []string{"a", "b"}}
+
+	// 未指定模型且 Profile 没有重排序模型时不能退回聊天模型
+	if _, err := New("key", WithBaseURL(srv.URL)).Rerank(t.Context(), req); err == nil {
+		t.Error("Rerank without model should fail")
+	}
+
+	p := NewCompatible(Profile{Name: "gateway", BaseURL: srv.URL, DefaultModel: "chat", RerankModel: "reranker"}, "key")
+	resp, err := p.Rerank(t.Context(), req)
+	if err != nil {
+		t.Fatalf("Rerank error: %v", err)
+	}
+	if gotModel != "reranker" {
+		t.Errorf("model = %v, want reranker", gotModel)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Index != 1 {
+		t.Errorf("results = %+v", resp.Results)
+	}
+
+	req.Model = "explicit"
+	if _, err := p.Rerank(t.Context(), req); err != nil || gotModel != "explicit" {
+		t.Errorf("model = %v, err = %v, want explicit", gotModel, err)
+	}
+}
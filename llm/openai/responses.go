@@ -0,0 +1,387 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// ResponsesProvider 基于 OpenAI Responses API（/v1/responses）实现 llm.Provider
+//
+// 相比 /chat/completions，Responses API 支持：
+//   - 服务端对话状态：通过 CompletionRequest.PreviousResponseID 续接上一轮，
+//     推理项（reasoning item）与工具调用由服务端自动回传，无需客户端保存
+//   - 内置工具：web_search_preview、file_search、code_interpreter 等
+//   - 推理摘要：推理模型的思考摘要写入 CompletionResponse.Reasoning
+//
+// 模型列表、Token 计数、Files/Batch 等能力复用 Provider。
+type ResponsesProvider struct {
+	*Provider
+	builtinTools []map[string]any
+}
+
+// NewResponses 创建基于 Responses API 的 Provider
+// 参数与 New 相同，apiKey 为空时从环境变量 OPENAI_API_KEY 读取
+func NewResponses(apiKey string, opts ...Option) *ResponsesProvider {
+	return &ResponsesProvider{Provider: New(apiKey, opts...)}
+}
+
+// WithBuiltinTools 追加每次请求都携带的内置工具，返回自身以便链式调用
+//
+// 每个工具为 Responses API 的原始工具定义，如:
+//
+//	p := openai.NewResponses(key).WithBuiltinTools(
+//	    map[string]any{"type": "web_search_preview"},
+//	    map[string]any{"type": "file_search", "vector_store_ids": []string{"vs_123"}},
+//	)
+//
+// 无需额外配置的内置工具也可直接放入 CompletionRequest.Tools（Type 设为工具类型，Function 留空）。
+func (p *ResponsesProvider) WithBuiltinTools(tools ...map[string]any) *ResponsesProvider {
+	p.builtinTools = append(p.builtinTools, tools...)
+	return p
+}
+
+// Name 返回提供者名称
+func (p *ResponsesProvider) Name() string {
+	return "openai-responses"
+}
+
+// Complete 执行非流式补全请求
+func (p *ResponsesProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if err := p.checkRequest(req); err != nil {
+		return nil, err
+	}
+	if req.N > 1 {
+		// Responses API 不支持 n 参数，通过并发请求模拟
+		return llm.CompleteChoices(ctx, p, req)
+	}
+
+	resp, err := p.post(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result responsesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析 responses 响应失败: %w", err)
+	}
+	if result.Status == "failed" && result.Error != nil {
+		return nil, fmt.Errorf("openai responses failed: %s: %s", result.Error.Code, result.Error.Message)
+	}
+
+	response := result.toResponse()
+	if req.Reasoning.Excluded() {
+		response.StripReasoning()
+	}
+	return response, nil
+}
+
+// Stream 执行流式补全请求
+func (p *ResponsesProvider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
+	if err := p.checkRequest(req); err != nil {
+		return nil, err
+	}
+	if req.N > 1 {
+		return nil, &llm.UnsupportedError{Provider: p.Name(), Feature: "streaming with n>1"}
+	}
+
+	resp, err := p.post(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.ResponsesFormat), nil
+}
+
+// checkRequest 检查 Responses API 不支持的参数
+func (p *ResponsesProvider) checkRequest(req llm.CompletionRequest) error {
+	if req.WantsLogprobs() {
+		return &llm.UnsupportedError{Provider: p.Name(), Feature: "logprobs"}
+	}
+	if req.Constraint != nil {
+		return &llm.UnsupportedError{Provider: p.Name(), Feature: "constrained decoding"}
+	}
+	if len(req.Stop) > 0 {
+		return &llm.UnsupportedError{Provider: p.Name(), Feature: "stop sequences"}
+	}
+	return nil
+}
+
+// post 发送 /responses 请求，非 2xx 时读取响应体并返回错误
+func (p *ResponsesProvider) post(ctx context.Context, req llm.CompletionRequest, stream bool) (*http.Response, error) {
+	if req.Model == "" {
+		req.Model = p.model
+	}
+
+	body, err := p.buildResponsesBody(req, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/responses", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("openai responses api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("openai responses api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+	return resp, nil
+}
+
+// buildResponsesBody 构建 Responses API 请求体
+func (p *ResponsesProvider) buildResponsesBody(req llm.CompletionRequest, stream bool) ([]byte, error) {
+	payload := map[string]any{
+		"model":  req.Model,
+		"input":  convertResponsesInput(llm.ApplyReasoningPolicy(req.Messages, req.ReasoningHistory)),
+		"stream": stream,
+	}
+	if req.PreviousResponseID != "" {
+		payload["previous_response_id"] = req.PreviousResponseID
+	}
+
+	if tools := p.convertTools(req.Tools); len(tools) > 0 {
+		payload["tools"] = tools
+		if req.ParallelToolCalls != nil {
+			payload["parallel_tool_calls"] = *req.ParallelToolCalls
+		}
+	}
+	if req.ToolChoice != nil {
+		payload["tool_choice"] = convertResponsesToolChoice(req.ToolChoice)
+	}
+	if req.MaxTokens > 0 {
+		payload["max_output_tokens"] = req.MaxTokens
+	}
+	if req.Temperature != nil {
+		payload["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		payload["top_p"] = *req.TopP
+	}
+	if req.User != "" {
+		payload["user"] = req.User
+	}
+	if effort := req.Reasoning.EffortLevel(); effort != "" {
+		reasoning := map[string]any{"effort": string(effort)}
+		if !req.Reasoning.Excluded() {
+			// 请求推理摘要，写入 CompletionResponse.Reasoning
+			reasoning["summary"] = "auto"
+		}
+		payload["reasoning"] = reasoning
+	}
+
+	if req.ResponseFormat != nil {
+		switch req.ResponseFormat.Type {
+		case "json_object":
+			payload["text"] = map[string]any{"format": map[string]any{"type": "json_object"}}
+		case "json_schema":
+			if js := req.ResponseFormat.JSONSchema; js != nil {
+				format := map[string]any{
+					"type":   "json_schema",
+					"name":   js.Name,
+					"schema": js.Schema,
+					"strict": js.Strict,
+				}
+				if js.Description != "" {
+					format["description"] = js.Description
+				}
+				payload["text"] = map[string]any{"format": format}
+			}
+		}
+	}
+
+	if err := llm.MergeExtraBody(payload, req.ExtraBody); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(payload)
+}
+
+// convertTools 转换工具定义
+// 函数工具展平为 {type, name, description, parameters}；其余类型视为内置工具，仅保留 type
+func (p *ResponsesProvider) convertTools(tools []llm.ToolDefinition) []map[string]any {
+	result := make([]map[string]any, 0, len(tools)+len(p.builtinTools))
+	for _, t := range tools {
+		if t.Type != "" && t.Type != "function" {
+			result = append(result, map[string]any{"type": t.Type})
+			continue
+		}
+		result = append(result, map[string]any{
+			"type":        "function",
+			"name":        t.Function.Name,
+			"description": t.Function.Description,
+			"parameters":  t.Function.Parameters,
+		})
+	}
+	return append(result, p.builtinTools...)
+}
+
+// convertResponsesToolChoice 转换工具选择策略
+// Chat Completions 风格的 {"type":"function","function":{"name":...}} 需展平为 {"type":"function","name":...}
+func convertResponsesToolChoice(choice any) any {
+	m, ok := choice.(map[string]any)
+	if !ok {
+		return choice
+	}
+	if fn, ok := m["function"].(map[string]any); ok {
+		return map[string]any{"type": "function", "name": fn["name"]}
+	}
+	return m
+}
+
+// convertResponsesInput 转换消息为 Responses API 的 input 项
+//
+// assistant 消息中的工具调用展开为 function_call 项，tool 消息转换为 function_call_output 项。
+func convertResponsesInput(messages []llm.Message) []map[string]any {
+	items := make([]map[string]any, 0, len(messages))
+	for _, msg := range messages {
+		switch msg.Role {
+		case llm.RoleTool:
+			items = append(items, map[string]any{
+				"type":    "function_call_output",
+				"call_id": msg.ToolCallID,
+				"output":  msg.Content,
+			})
+			continue
+		case llm.RoleAssistant:
+			if msg.Content != "" || len(msg.ToolCalls) == 0 {
+				items = append(items, map[string]any{"role": "assistant", "content": msg.Content})
+			}
+			for _, tc := range msg.ToolCalls {
+				items = append(items, map[string]any{
+					"type":      "function_call",
+					"call_id":   tc.ID,
+					"name":      tc.Name,
+					"arguments": tc.Arguments,
+				})
+			}
+			continue
+		}
+
+		item := map[string]any{"role": string(msg.Role)}
+		if msg.HasMultiContent() {
+			parts := make([]map[string]any, 0, len(msg.MultiContent))
+			for _, part := range msg.MultiContent {
+				switch part.Type {
+				case "image_url":
+					if part.ImageURL == nil {
+						continue
+					}
+					p := map[string]any{"type": "input_image", "image_url": part.ImageURL.URL}
+					if part.ImageURL.Detail != "" {
+						p["detail"] = part.ImageURL.Detail
+					}
+					parts = append(parts, p)
+				default: // "text"
+					parts = append(parts, map[string]any{"type": "input_text", "text": part.Text})
+				}
+			}
+			item["content"] = parts
+		} else {
+			item["content"] = msg.Content
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// responsesResponse Responses API 响应结构
+type responsesResponse struct {
+	ID                string `json:"id"`
+	Model             string `json:"model"`
+	CreatedAt         int64  `json:"created_at"`
+	Status            string `json:"status"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details,omitempty"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+	Output []struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`
+		Content []struct {
+			Type    string `json:"type"`
+			Text    string `json:"text,omitempty"`
+			Refusal string `json:"refusal,omitempty"`
+		} `json:"content,omitempty"`
+		Summary []struct {
+			Text string `json:"text"`
+		} `json:"summary,omitempty"`
+		CallID    string `json:"call_id,omitempty"`
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"output"`
+	Usage streamx.ResponsesUsage `json:"usage"`
+}
+
+// toResponse 转换为统一的补全响应
+func (r *responsesResponse) toResponse() *llm.CompletionResponse {
+	var (
+		content   strings.Builder
+		reasoning []string
+		choice    llm.Choice
+	)
+	for _, item := range r.Output {
+		switch item.Type {
+		case "message":
+			for _, c := range item.Content {
+				switch c.Type {
+				case "output_text":
+					content.WriteString(c.Text)
+				case "refusal":
+					content.WriteString(c.Refusal)
+				}
+			}
+		case "reasoning":
+			for _, s := range item.Summary {
+				reasoning = append(reasoning, s.Text)
+			}
+		case "function_call":
+			choice.ToolCalls = append(choice.ToolCalls, llm.ToolCall{
+				ID:        item.CallID,
+				Type:      "function",
+				Name:      item.Name,
+				Arguments: item.Arguments,
+			})
+		}
+	}
+
+	incompleteReason := ""
+	if r.IncompleteDetails != nil {
+		incompleteReason = r.IncompleteDetails.Reason
+	}
+	choice.Content = content.String()
+	choice.Reasoning = strings.Join(reasoning, "\n\n")
+	choice.FinishReason = streamx.ResponsesFinishReason(r.Status, incompleteReason, len(choice.ToolCalls) > 0)
+
+	result := &llm.CompletionResponse{
+		ID:      r.ID,
+		Model:   r.Model,
+		Created: r.CreatedAt,
+		Usage:   r.Usage.ToUsage(),
+	}
+	result.SetChoices([]llm.Choice{choice})
+	return result
+}
+
+// 确保 ResponsesProvider 实现了 Provider 接口
+var _ llm.Provider = (*ResponsesProvider)(nil)
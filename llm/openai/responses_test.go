@@ -0,0 +1,64 @@
+package openai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestResponsesProvider_Complete(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/responses" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["previous_response_id"] != "resp_0" || body["max_output_tokens"] != float64(100) {
+			t.Errorf("unexpected body: %v", body)
+		}
+		input := body["input"].([]any)
+		if len(input) != 2 {
+			t.Fatalf("input = %v", input)
+		}
+		if call := input[0].(map[string]any); call["type"] != "function_call" || call["call_id"] != "call_1" {
+			t.Errorf("unexpected function_call item: %v", call)
+		}
+		if out := input[1].(map[string]any); out["type"] != "function_call_output" || out["output"] != "sunny" {
+			t.Errorf("unexpected function_call_output item: %v", out)
+		}
+		tools := body["tools"].([]any)
+		if len(tools) != 2 || tools[0].(map[string]any)["name"] != "weather" || tools[1].(map[string]any)["type"] != "web_search_preview" {
+			t.Errorf("unexpected tools: %v", tools)
+		}
+		io.WriteString(w, `{"id":"resp_1","model":"o4-mini","status":"completed","output":[
+			{"type":"reasoning","id":"rs_1","summary":[{"type":"summary_text","text":"checked weather"}]},
+			{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"It is sunny."}]}
+		],"usage":{"input_tokens":20,"output_tokens":8,"total_tokens":28,"input_tokens_details":{"cached_tokens":4}}}`)
+	}))
+	defer srv.Close()
+
+	p := NewResponses("key", WithBaseURL(srv.URL)).WithBuiltinTools(map[string]any{"type": "web_search_preview"})
+	resp, err := p.Complete(t.Context(), llm.CompletionRequest{
+		Model: "o4-mini",
+		Messages: []llm.Message{
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCallRef{{ID: "call_1", Name: "weather", Arguments: `{}`}}},
+			{Role: llm.RoleTool, ToolCallID: "call_1", Content: "sunny"},
+		},
+		Tools:              []llm.ToolDefinition{llm.NewToolDefinition("weather", "查询天气", nil)},
+		MaxTokens:          100,
+		PreviousResponseID: "resp_0",
+	})
+	if err != nil {
+		t.Fatalf("Complete error: %v", err)
+	}
+	if resp.ID != "resp_1" || resp.Content != "It is sunny." || resp.Reasoning != "checked weather" || resp.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 28 || resp.Usage.CachedTokens != 4 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
@@ -30,6 +30,9 @@ func (p *Provider) CreateVideoTask(ctx context.Context, req llm.VideoRequest) (*
 	if req.Duration > 0 {
 		payload.Duration = req.Duration
 	}
+	payload.ImageURL = req.ImageURL
+	payload.FPS = req.FPS
+	payload.WithAudio = req.WithAudio
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -123,10 +126,13 @@ func (p *Provider) QueryVideoTask(ctx context.Context, taskID string) (*llm.Vide
 
 // videoGenRequest 视频生成请求结构
 type videoGenRequest struct {
-	Model    string `json:"model"`
-	Prompt   string `json:"prompt"`
-	Size     string `json:"size,omitempty"`
-	Duration int    `json:"duration,omitempty"`
+	Model     string `json:"model"`
+	Prompt    string `json:"prompt"`
+	Size      string `json:"size,omitempty"`
+	Duration  int    `json:"duration,omitempty"`
+	ImageURL  string `json:"image_url,omitempty"`
+	FPS       int    `json:"fps,omitempty"`
+	WithAudio bool   `json:"with_audio,omitempty"`
 }
 
 // videoGenResponse 视频生成创建响应
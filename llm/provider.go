@@ -2,6 +2,9 @@ package llm
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"sort"
 
 	"github.com/hexagon-codes/ai-core/schema"
 	"github.com/hexagon-codes/ai-core/streamx"
@@ -50,6 +53,12 @@ type (
 
 	// Stream LLM 流式响应
 	Stream = streamx.Stream
+
+	// Choice 单个生成选项（n>1 时每个选项一个）
+	Choice = streamx.ChoiceResult
+
+	// TokenLogprob 输出 Token 的对数概率
+	TokenLogprob = streamx.TokenLogprob
 )
 
 // 重新导出 schema 类型
@@ -74,6 +83,15 @@ type Provider interface {
 	CountTokens(messages []Message) (int, error)
 }
 
+// ModelLister 定义可从远端获取模型目录的 Provider
+//
+// ListModels 请求厂商的 /models（或等价接口），与内置的模型元数据（上下文长度、特性、价格）合并，
+// 结果带缓存；成功后 Models() 也返回最新目录。
+// router.Router.RefreshModels 据此发现新发布的模型。
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+}
+
 // EmbeddingProvider 定义支持向量嵌入的 Provider
 type EmbeddingProvider interface {
 	Provider
@@ -85,6 +103,69 @@ type EmbeddingProvider interface {
 	EmbedWithModel(ctx context.Context, model string, texts []string) ([][]float32, error)
 }
 
+// EmbedRequestProvider 定义支持完整嵌入请求参数的 Provider（可选能力）
+// 未实现时调用方可退回 EmbedWithModel，仅 Model、BatchSize 与 Normalize 生效
+type EmbedRequestProvider interface {
+	EmbeddingProvider
+
+	// CreateEmbeddings 按完整请求参数生成嵌入（维度、输入类型、编码、归一化），并返回用量
+	// 输入超过 Provider 单次上限时自动分批请求
+	CreateEmbeddings(ctx context.Context, req EmbedRequest) (*EmbedResponse, error)
+}
+
+// EmbedInputType 嵌入输入类型
+// 部分模型对检索查询与被检索文档使用不同的编码方式，区分后可提升召回效果
+type EmbedInputType string
+
+const (
+	// EmbedInputQuery 检索查询（Qwen text_type=query、Gemini RETRIEVAL_QUERY）
+	EmbedInputQuery EmbedInputType = "query"
+
+	// EmbedInputDocument 被检索的文档（Qwen text_type=document、Gemini RETRIEVAL_DOCUMENT）
+	EmbedInputDocument EmbedInputType = "document"
+)
+
+// EmbedRequest 向量嵌入请求
+type EmbedRequest struct {
+	// Model 模型名称，为空时使用 Provider 的默认 Embedding 模型
+	Model string `json:"model"`
+
+	// Input 要生成嵌入的文本列表
+	Input []string `json:"input"`
+
+	// Dimensions 输出向量维度，0 表示模型默认维度
+	// 仅支持可变维度的模型（OpenAI text-embedding-3、Qwen text-embedding-v3/v4、Gemini、部分 Ollama 模型）
+	Dimensions int `json:"dimensions,omitempty"`
+
+	// InputType 输入类型，为空时不区分；不支持的 Provider 忽略
+	InputType EmbedInputType `json:"input_type,omitempty"`
+
+	// Base64 以 base64 编码传输向量以减少响应体积，结果仍解码为 []float32
+	// 不支持的 Provider 忽略（OpenAI 支持）
+	Base64 bool `json:"base64,omitempty"`
+
+	// Normalize 对结果做 L2 归一化，便于直接用点积计算余弦相似度
+	Normalize bool `json:"normalize,omitempty"`
+
+	// BatchSize 单次请求的最大输入数，0 表示使用 Provider 上限
+	BatchSize int `json:"batch_size,omitempty"`
+
+	// ExtraBody 额外的请求体参数，与 CompletionRequest.ExtraBody 语义一致
+	ExtraBody map[string]any `json:"extra_body,omitempty"`
+}
+
+// EmbedResponse 向量嵌入响应
+type EmbedResponse struct {
+	// Model 实际使用的模型
+	Model string `json:"model"`
+
+	// Embeddings 向量列表，与 Input 一一对应
+	Embeddings [][]float32 `json:"embeddings"`
+
+	// Usage Token 用量（分批请求时为各批之和；不返回用量的 Provider 为零值）
+	Usage Usage `json:"usage"`
+}
+
 // ImageProvider 定义支持图片生成的 Provider
 //
 // 遵循 OpenAI Images API 规范（/images/generations），
@@ -107,6 +188,88 @@ type ImageRequest struct {
 
 	// N 生成图片数量，默认 1
 	N int `json:"n,omitempty"`
+
+	// ResponseFormat 返回格式："url"（默认）或 "b64_json"
+	// 厂商 URL 通常数小时内过期，需要持久化时建议使用 b64_json 或 ImageResponse.Download
+	ResponseFormat string `json:"response_format,omitempty"`
+
+	// Quality 图片质量（dall-e-3: "standard"/"hd"；gpt-image-1: "low"/"medium"/"high"）
+	Quality string `json:"quality,omitempty"`
+
+	// Style 风格（仅 dall-e-3："vivid"/"natural"）
+	Style string `json:"style,omitempty"`
+
+	// Background 背景（仅 gpt-image-1："transparent"/"opaque"/"auto"）
+	Background string `json:"background,omitempty"`
+}
+
+// ImageEditProvider 定义支持图片编辑与变体的 Provider
+//
+// 遵循 OpenAI Images API 规范（/images/edits、/images/variations），
+// 图片以 multipart/form-data 上传。
+type ImageEditProvider interface {
+	// EditImage 根据提示词编辑图片，Mask 透明区域为待重绘区域
+	EditImage(ctx context.Context, req ImageEditRequest) (*ImageResponse, error)
+
+	// CreateImageVariation 生成图片变体（仅 dall-e-2 支持）
+	CreateImageVariation(ctx context.Context, req ImageVariationRequest) (*ImageResponse, error)
+}
+
+// ImageFile 待上传的图片文件
+type ImageFile struct {
+	// Name 文件名，服务端据扩展名识别格式（如 "photo.png"）
+	Name string
+
+	// Data 图片数据
+	Data io.Reader
+}
+
+// ImageEditRequest 图片编辑请求
+type ImageEditRequest struct {
+	// Model 模型名称（如 "gpt-image-1"、"dall-e-2"）
+	Model string `json:"model,omitempty"`
+
+	// Prompt 编辑描述
+	Prompt string `json:"prompt"`
+
+	// Images 原图，dall-e-2 仅支持一张，gpt-image-1 支持多张参考图
+	Images []ImageFile `json:"-"`
+
+	// Mask 蒙版（可选），透明像素标记需要编辑的区域，尺寸须与原图一致
+	Mask *ImageFile `json:"-"`
+
+	// Size 输出尺寸
+	Size string `json:"size,omitempty"`
+
+	// N 生成数量
+	N int `json:"n,omitempty"`
+
+	// ResponseFormat 返回格式："url" 或 "b64_json"
+	ResponseFormat string `json:"response_format,omitempty"`
+
+	// Quality 图片质量
+	Quality string `json:"quality,omitempty"`
+
+	// Background 背景（仅 gpt-image-1）
+	Background string `json:"background,omitempty"`
+}
+
+// ImageVariationRequest 图片变体请求
+type ImageVariationRequest struct {
+	// Model 模型名称，默认 "dall-e-2"
+	Model string `json:"model,omitempty"`
+
+	// Image 原图（正方形 PNG，小于 4MB）
+	Image ImageFile `json:"-"`
+
+	// Size 输出尺寸
+	Size string `json:"size,omitempty"`
+
+	// N 生成数量
+	N int `json:"n,omitempty"`
+
+	// ResponseFormat 返回格式："url" 或 "b64_json"
+	ResponseFormat string `json:"response_format,omitempty"`
 }
 
 // ImageResponse 图片生成响应
@@ -117,9 +280,15 @@ type ImageResponse struct {
 
 // ImageData 单张生成图片
 type ImageData struct {
-	// URL 图片访问地址
+	// URL 图片访问地址（ResponseFormat 为 url 时返回，通常会过期）
 	URL string `json:"url,omitempty"`
 
+	// B64JSON Base64 编码的图片（ResponseFormat 为 b64_json 时返回）
+	B64JSON string `json:"b64_json,omitempty"`
+
+	// Bytes 图片原始字节，由 B64JSON 解码或通过 ImageResponse.Download 下载得到
+	Bytes []byte `json:"-"`
+
 	// RevisedPrompt 模型修正后的提示词（部分模型返回）
 	RevisedPrompt string `json:"revised_prompt,omitempty"`
 }
@@ -149,6 +318,15 @@ type VideoRequest struct {
 
 	// Duration 视频时长（秒），为空时使用模型默认值
 	Duration int `json:"duration,omitempty"`
+
+	// ImageURL 首帧图片地址（图生视频），支持 URL 或 base64 data URI
+	ImageURL string `json:"image_url,omitempty"`
+
+	// FPS 帧率，为空时使用模型默认值
+	FPS int `json:"fps,omitempty"`
+
+	// WithAudio 是否生成音效（CogVideoX 等支持）
+	WithAudio bool `json:"with_audio,omitempty"`
 }
 
 // VideoTaskStatus 视频任务状态
@@ -179,6 +357,421 @@ type VideoTask struct {
 	Error string `json:"error,omitempty"`
 }
 
+// TranscriptionProvider 定义支持语音转文字的 Provider
+//
+// 遵循 OpenAI Audio API 规范（/audio/transcriptions），
+// Whisper、gpt-4o-transcribe 及 DashScope 兼容模式的识别模型均兼容此接口。
+type TranscriptionProvider interface {
+	// Transcribe 将音频转写为文本
+	Transcribe(ctx context.Context, req TranscriptionRequest) (*TranscriptionResponse, error)
+}
+
+// TranscriptionFormat 转写结果格式
+type TranscriptionFormat string
+
+const (
+	TranscriptionJSON        TranscriptionFormat = "json"         // 仅文本
+	TranscriptionVerboseJSON TranscriptionFormat = "verbose_json" // 含语言、时长与分段时间戳
+	TranscriptionText        TranscriptionFormat = "text"         // 纯文本
+	TranscriptionSRT         TranscriptionFormat = "srt"          // SRT 字幕
+	TranscriptionVTT         TranscriptionFormat = "vtt"          // WebVTT 字幕
+)
+
+// TranscriptionRequest 语音转文字请求
+type TranscriptionRequest struct {
+	// Model 模型名称（如 "whisper-1"），为空时使用 Provider 默认转写模型
+	Model string `json:"model,omitempty"`
+
+	// Audio 音频数据（文件或内存中的字节均可，如 os.File、bytes.Reader）
+	Audio io.Reader `json:"-"`
+
+	// FileName 音频文件名，服务端据扩展名识别格式（如 "meeting.mp3"）
+	FileName string `json:"file_name"`
+
+	// Language 音频语言（ISO-639-1，如 "zh"），为空时自动检测
+	Language string `json:"language,omitempty"`
+
+	// Prompt 提示文本，用于纠正专有名词或延续上一段风格
+	Prompt string `json:"prompt,omitempty"`
+
+	// Format 结果格式，默认 json
+	Format TranscriptionFormat `json:"format,omitempty"`
+
+	// Temperature 采样温度 (0-1)
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// TimestampGranularities 时间戳粒度（"segment"、"word"），仅 verbose_json 有效
+	TimestampGranularities []string `json:"timestamp_granularities,omitempty"`
+}
+
+// TranscriptionResponse 语音转文字响应
+type TranscriptionResponse struct {
+	// Text 转写文本；Format 为 srt/vtt 时为完整字幕内容
+	Text string `json:"text"`
+
+	// Language 识别出的语言（verbose_json）
+	Language string `json:"language,omitempty"`
+
+	// Duration 音频时长，单位秒（verbose_json）
+	Duration float64 `json:"duration,omitempty"`
+
+	// Segments 分段结果（verbose_json）
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+
+	// Words 逐词时间戳（verbose_json 且包含 "word" 粒度）
+	Words []TranscriptionWord `json:"words,omitempty"`
+}
+
+// TranscriptionSegment 转写分段
+type TranscriptionSegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"` // 起始时间（秒）
+	End   float64 `json:"end"`   // 结束时间（秒）
+	Text  string  `json:"text"`
+}
+
+// TranscriptionWord 逐词时间戳
+type TranscriptionWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// SpeechProvider 定义支持文字转语音的 Provider
+//
+// 遵循 OpenAI Audio API 规范（/audio/speech）。
+type SpeechProvider interface {
+	// SynthesizeSpeech 将文本合成为语音，返回音频流
+	SynthesizeSpeech(ctx context.Context, req SpeechRequest) (*SpeechResponse, error)
+}
+
+// SpeechRequest 文字转语音请求
+type SpeechRequest struct {
+	// Model 模型名称（如 "tts-1"、"gpt-4o-mini-tts"），为空时使用 Provider 默认语音模型
+	Model string `json:"model,omitempty"`
+
+	// Input 待合成的文本
+	Input string `json:"input"`
+
+	// Voice 音色（如 "alloy"），为空时使用 Provider 默认音色
+	Voice string `json:"voice,omitempty"`
+
+	// Format 音频格式（mp3、opus、aac、flac、wav、pcm），默认 mp3
+	Format string `json:"format,omitempty"`
+
+	// Speed 语速 (0.25-4.0)，0 表示默认 1.0
+	Speed float64 `json:"speed,omitempty"`
+
+	// Instructions 语气/风格指令（仅 gpt-4o-mini-tts 等模型支持）
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// SpeechResponse 文字转语音响应
+type SpeechResponse struct {
+	// Audio 音频数据流，调用方读取完毕后必须 Close
+	Audio io.ReadCloser
+
+	// ContentType 音频 MIME 类型（如 "audio/mpeg"）
+	ContentType string
+}
+
+// ModerationProvider 定义支持内容审核的 Provider
+//
+// 遵循 OpenAI Moderations API 规范（/moderations），可配合 WithGuard 中间件
+// 对输入和输出做策略检查。
+type ModerationProvider interface {
+	// Moderate 审核一批文本，结果与输入一一对应
+	Moderate(ctx context.Context, req ModerationRequest) (*ModerationResponse, error)
+}
+
+// ModerationRequest 内容审核请求
+type ModerationRequest struct {
+	// Model 审核模型（如 "omni-moderation-latest"），为空时使用 Provider 默认值
+	Model string `json:"model,omitempty"`
+
+	// Input 待审核的文本列表
+	Input []string `json:"input"`
+}
+
+// ModerationResponse 内容审核响应
+type ModerationResponse struct {
+	// ID 审核请求标识
+	ID string `json:"id,omitempty"`
+
+	// Model 实际使用的审核模型
+	Model string `json:"model,omitempty"`
+
+	// Results 审核结果，与 Input 顺序一致
+	Results []ModerationResult `json:"results"`
+}
+
+// Flagged 检查是否有任一输入被标记
+func (r *ModerationResponse) Flagged() bool {
+	for _, result := range r.Results {
+		if result.Flagged {
+			return true
+		}
+	}
+	return false
+}
+
+// ModerationResult 单条文本的审核结果
+type ModerationResult struct {
+	// Flagged 是否违反策略
+	Flagged bool `json:"flagged"`
+
+	// Categories 各类别是否命中（如 "hate"、"violence"、"self-harm"）
+	Categories map[string]bool `json:"categories"`
+
+	// CategoryScores 各类别的置信分数 (0-1)
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
+// FlaggedCategories 返回命中的类别名称
+func (r ModerationResult) FlaggedCategories() []string {
+	var names []string
+	for name, hit := range r.Categories {
+		if hit {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RerankProvider 定义支持重排序的 Provider
+//
+// 对检索召回的候选文档按与查询的相关性重新打分，常用于 RAG 的二阶段精排。
+// 支持 DashScope gte-rerank 以及 Jina/Cohere 风格的 /rerank 端点。
+type RerankProvider interface {
+	// Rerank 对候选文档重排序，结果按相关性降序排列
+	Rerank(ctx context.Context, req RerankRequest) (*RerankResponse, error)
+}
+
+// RerankRequest 重排序请求
+type RerankRequest struct {
+	// Model 重排序模型（如 "gte-rerank-v2"、"jina-reranker-v2-base-multilingual"）
+	Model string `json:"model,omitempty"`
+
+	// Query 查询文本
+	Query string `json:"query"`
+
+	// Documents 候选文档
+	Documents []string `json:"documents"`
+
+	// TopN 返回前 N 个结果，0 表示返回全部
+	TopN int `json:"top_n,omitempty"`
+
+	// ReturnDocuments 是否在结果中回带文档原文
+	ReturnDocuments bool `json:"return_documents,omitempty"`
+}
+
+// RerankResponse 重排序响应
+type RerankResponse struct {
+	// ID 请求标识
+	ID string `json:"id,omitempty"`
+
+	// Model 实际使用的模型
+	Model string `json:"model,omitempty"`
+
+	// Results 重排序结果，按 RelevanceScore 降序
+	Results []RerankResult `json:"results"`
+
+	// Usage Token 使用统计
+	Usage Usage `json:"usage"`
+}
+
+// RerankResult 单个文档的重排序结果
+type RerankResult struct {
+	// Index 文档在 RerankRequest.Documents 中的下标
+	Index int `json:"index"`
+
+	// RelevanceScore 相关性分数，越大越相关
+	RelevanceScore float64 `json:"relevance_score"`
+
+	// Document 文档原文（仅 ReturnDocuments 为 true 时返回）
+	Document string `json:"document,omitempty"`
+}
+
+// CompletionTextProvider 定义支持文本补全（legacy /completions）的 Provider
+//
+// 与 Chat 补全不同，输入为原始提示词而非消息列表，适合代码补全等场景。
+// 设置 Suffix 时为中间填充（FIM, fill-in-the-middle）：模型生成 Prompt 与 Suffix 之间的内容。
+// DeepSeek（beta）、Ollama、通义千问 Coder 及 OpenAI 兼容的 /completions 接口实现了此接口。
+type CompletionTextProvider interface {
+	// CompleteText 执行文本补全
+	CompleteText(ctx context.Context, req TextCompletionRequest) (*TextCompletionResponse, error)
+
+	// StreamText 流式文本补全，Chunk.Content 为增量文本
+	StreamText(ctx context.Context, req TextCompletionRequest) (*streamx.Stream, error)
+}
+
+// TextCompletionRequest 文本补全请求
+type TextCompletionRequest struct {
+	// Model 模型名称，为空时使用 Provider 默认模型
+	Model string `json:"model"`
+
+	// Prompt 提示词（FIM 模式下为光标前的前缀）
+	Prompt string `json:"prompt"`
+
+	// Suffix 光标后的后缀，非空时为 FIM 模式
+	Suffix string `json:"suffix,omitempty"`
+
+	// Echo 是否在结果中包含提示词
+	Echo bool `json:"echo,omitempty"`
+
+	// Stop 停止序列
+	Stop []string `json:"stop,omitempty"`
+
+	// MaxTokens 最大生成 Token 数，0 表示使用服务端默认值
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Temperature 温度
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// TopP Top-P 采样
+	TopP *float64 `json:"top_p,omitempty"`
+
+	// Seed 随机种子
+	Seed *int `json:"seed,omitempty"`
+
+	// ExtraBody 额外的请求体参数，与 CompletionRequest.ExtraBody 语义一致
+	ExtraBody map[string]any `json:"extra_body,omitempty"`
+}
+
+// TextCompletionResponse 文本补全响应
+type TextCompletionResponse struct {
+	// ID 响应 ID
+	ID string `json:"id"`
+
+	// Model 实际使用的模型
+	Model string `json:"model"`
+
+	// Text 生成的文本（Echo 为 true 时包含提示词）
+	Text string `json:"text"`
+
+	// FinishReason 结束原因：stop、length 等
+	FinishReason string `json:"finish_reason"`
+
+	// Usage Token 用量
+	Usage Usage `json:"usage"`
+}
+
+// BatchProvider 定义支持批量推理的 Provider
+//
+// 遵循 OpenAI Batch API：请求写入 JSONL 文件上传后异步执行（通常 24 小时内完成），
+// 价格约为实时调用的一半，适合离线分类、打标等不要求时效的场景。
+// OpenAI、通义千问兼容模式、火山方舟均兼容此模式。
+type BatchProvider interface {
+	// CreateBatch 上传请求并创建批量任务
+	CreateBatch(ctx context.Context, req BatchRequest) (*BatchJob, error)
+
+	// GetBatch 查询批量任务状态
+	GetBatch(ctx context.Context, batchID string) (*BatchJob, error)
+
+	// GetBatchResults 下载已结束任务的结果，按 CustomID 索引
+	GetBatchResults(ctx context.Context, batchID string) (map[string]BatchResult, error)
+
+	// CancelBatch 取消批量任务
+	CancelBatch(ctx context.Context, batchID string) (*BatchJob, error)
+}
+
+// BatchItem 批量任务中的单个请求
+type BatchItem struct {
+	// CustomID 调用方指定的请求标识，用于回查结果；为空时自动生成 "request-<序号>"
+	CustomID string `json:"custom_id"`
+
+	// Request 补全请求
+	Request CompletionRequest `json:"request"`
+}
+
+// BatchRequest 创建批量任务的请求
+type BatchRequest struct {
+	// Items 批量请求，CustomID 不可重复
+	Items []BatchItem `json:"items"`
+
+	// CompletionWindow 完成时限，默认 "24h"
+	CompletionWindow string `json:"completion_window,omitempty"`
+
+	// Metadata 任务元数据
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchStatus 批量任务状态
+type BatchStatus string
+
+const (
+	BatchValidating BatchStatus = "validating"
+	BatchFailed     BatchStatus = "failed"
+	BatchInProgress BatchStatus = "in_progress"
+	BatchFinalizing BatchStatus = "finalizing"
+	BatchCompleted  BatchStatus = "completed"
+	BatchExpired    BatchStatus = "expired"
+	BatchCancelling BatchStatus = "cancelling"
+	BatchCancelled  BatchStatus = "cancelled"
+)
+
+// Done 判断任务是否已结束（不会再变化）
+func (s BatchStatus) Done() bool {
+	switch s {
+	case BatchFailed, BatchCompleted, BatchExpired, BatchCancelled:
+		return true
+	}
+	return false
+}
+
+// BatchJob 批量任务
+type BatchJob struct {
+	// ID 任务唯一标识
+	ID string `json:"id"`
+
+	// Status 任务状态
+	Status BatchStatus `json:"status"`
+
+	// InputFileID 输入文件 ID
+	InputFileID string `json:"input_file_id,omitempty"`
+
+	// OutputFileID 成功结果文件 ID（任务结束后才有值）
+	OutputFileID string `json:"output_file_id,omitempty"`
+
+	// ErrorFileID 失败结果文件 ID（存在失败请求时才有值）
+	ErrorFileID string `json:"error_file_id,omitempty"`
+
+	// CreatedAt 创建时间（Unix 秒）
+	CreatedAt int64 `json:"created_at,omitempty"`
+
+	// CompletedAt 完成时间（Unix 秒）
+	CompletedAt int64 `json:"completed_at,omitempty"`
+
+	// RequestCounts 请求计数
+	RequestCounts BatchRequestCounts `json:"request_counts"`
+
+	// Errors 任务级错误（如输入文件校验失败）
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BatchRequestCounts 批量任务请求计数
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// BatchResult 批量任务中单个请求的结果
+type BatchResult struct {
+	// CustomID 对应 BatchItem.CustomID
+	CustomID string `json:"custom_id"`
+
+	// StatusCode 该请求的 HTTP 状态码
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Response 补全结果（成功时有值）
+	Response *CompletionResponse `json:"response,omitempty"`
+
+	// Error 错误信息（失败时有值）
+	Error string `json:"error,omitempty"`
+}
+
 // ToolDefinition 定义一个工具给 LLM 使用
 type ToolDefinition struct {
 	Type     string          `json:"type"`
@@ -222,6 +815,10 @@ type CompletionRequest struct {
 	// MaxTokens 最大生成 Token 数
 	MaxTokens int `json:"max_tokens,omitempty"`
 
+	// N 生成的选项数量，0 或 1 表示单个
+	// 不支持原生 n 参数的 Provider 通过并发请求模拟（见 CompleteChoices）
+	N int `json:"n,omitempty"`
+
 	// Temperature 采样温度 (0-2)
 	Temperature *float64 `json:"temperature,omitempty"`
 
@@ -231,6 +828,40 @@ type CompletionRequest struct {
 	// Stop 停止词列表
 	Stop []string `json:"stop,omitempty"`
 
+	// Seed 随机种子，相同种子与参数下尽量返回确定性结果
+	Seed *int `json:"seed,omitempty"`
+
+	// PresencePenalty 存在惩罚 (-2~2)，正值鼓励谈论新话题
+	PresencePenalty *float64 `json:"presence_penalty,omitempty"`
+
+	// FrequencyPenalty 频率惩罚 (-2~2)，正值降低逐字重复
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+
+	// TopK 仅从概率最高的 K 个 Token 中采样
+	// 支持：Anthropic、Gemini、Qwen、Ollama；OpenAI 兼容服务（vLLM 等）可通过 ExtraBody 传递
+	TopK *int `json:"top_k,omitempty"`
+
+	// RepetitionPenalty 重复惩罚（乘性，1.0 表示不惩罚）
+	// 支持：Qwen、Ollama（repeat_penalty）、ERNIE（penalty_score）
+	RepetitionPenalty *float64 `json:"repetition_penalty,omitempty"`
+
+	// ParallelToolCalls 是否允许单轮返回多个工具调用，nil 表示使用 Provider 默认值
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+
+	// ExtraBody 厂商特有参数，原样合并到请求体顶层
+	//
+	// 用于透传尚未类型化的参数（如 Qwen 的 enable_search），避免为单个参数分叉 Provider。
+	// 不能覆盖 Provider 自身构造的字段（model、messages 及已由类型化字段设置的参数），
+	// 冲突时请求直接返回 ErrReservedParam。
+	ExtraBody map[string]any `json:"extra_body,omitempty"`
+
+	// Logprobs 是否返回输出 Token 的对数概率
+	// 仅 OpenAI/DeepSeek/Qwen/Ollama 支持，其余 Provider 返回 *UnsupportedError
+	Logprobs bool `json:"logprobs,omitempty"`
+
+	// TopLogprobs 每个位置返回的候选 Token 数量（0-20），设置后隐含 Logprobs
+	TopLogprobs int `json:"top_logprobs,omitempty"`
+
 	// User 用户标识（用于追踪和滥用检测）
 	User string `json:"user,omitempty"`
 
@@ -246,10 +877,35 @@ type CompletionRequest struct {
 	//   - Gemini: 通过 responseMimeType 支持 "application/json"
 	//   - Anthropic: 不原生支持，可通过 tool_use 模拟
 	//   - Qwen/Ark: 支持 "json_object"（兼容 OpenAI）
-	//   - Ollama: 通过 format 参数支持 "json"
+	//   - Ollama: 通过 format 参数支持 "json_object" 与 "json_schema"（发送完整 Schema）
 	//
 	// 不支持的 Provider 会忽略此字段，上层应降级为 Prompt 工程
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// Constraint 约束解码（语法/正则/Schema 约束采样），nil 表示不约束
+	//
+	// 与 ResponseFormat 不同，约束在采样阶段强制生效，主要由本地推理服务支持：
+	//   - vLLM: guided_json / guided_regex / guided_grammar / guided_choice
+	//   - llama.cpp server: json_schema / grammar（GBNF）/ 选项列表
+	//   - Ollama: 仅 JSONSchema（format 参数）
+	//
+	// 不支持所设置约束的 Provider 返回 *UnsupportedError
+	Constraint *Constraint `json:"constraint,omitempty"`
+
+	// Reasoning 推理控制（强度/预算/是否返回推理内容），nil 表示使用模型默认行为
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
+
+	// ReasoningHistory 历史 assistant 消息中推理内容的回传策略
+	//
+	// 默认（空值）等同于 ReasoningStrip：发送前剥离历史推理内容，
+	// DeepSeek 等 Provider 在请求中携带 reasoning_content 会直接报错。
+	ReasoningHistory ReasoningPolicy `json:"reasoning_history,omitempty"`
+
+	// PreviousResponseID 上一轮响应的 ID，用于服务端保存的多轮对话状态
+	//
+	// 仅 OpenAI Responses API（openai.ResponsesProvider）支持：设置后 Messages 只需包含本轮新增消息，
+	// 上一轮的推理项、工具调用等由服务端续接。其余 Provider 忽略此字段。
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
 }
 
 // ResponseFormat 响应格式定义
@@ -283,6 +939,35 @@ type ResponseFormatJSONSchema struct {
 	Strict bool `json:"strict,omitempty"`
 }
 
+// Constraint 约束解码配置，各字段互斥，只应设置其中一个
+type Constraint struct {
+	// JSONSchema 输出必须符合的 JSON Schema
+	JSONSchema *Schema `json:"json_schema,omitempty"`
+
+	// Regex 输出必须匹配的正则表达式
+	Regex string `json:"regex,omitempty"`
+
+	// Grammar 输出必须符合的 GBNF 语法（可用 schema.ToGBNF 从 Schema 生成）
+	Grammar string `json:"grammar,omitempty"`
+
+	// Choice 输出必须是其中之一
+	Choice []string `json:"choice,omitempty"`
+}
+
+// Validate 检查是否恰好设置了一种约束
+func (c *Constraint) Validate() error {
+	n := 0
+	for _, set := range []bool{c.JSONSchema != nil, c.Regex != "", c.Grammar != "", len(c.Choice) > 0} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("constraint: exactly one of json_schema, regex, grammar, choice must be set, got %d", n)
+	}
+	return nil
+}
+
 // CompletionResponse 表示补全响应
 type CompletionResponse struct {
 	// ID 响应的唯一标识符
@@ -297,16 +982,46 @@ type CompletionResponse struct {
 	// ToolCalls 工具调用列表（如果有）
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 
+	// Reasoning 推理/思考过程（reasoning_content、thinking 等，模型未返回时为空）
+	Reasoning string `json:"reasoning,omitempty"`
+
+	// Logprobs 输出 Token 的对数概率（请求开启 Logprobs 时返回）
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+
+	// Choices 全部生成选项（按 Index 排序）
+	// 顶层的 Content/Reasoning/ToolCalls/FinishReason 与 Choices[0] 一致
+	Choices []Choice `json:"choices,omitempty"`
+
 	// Usage Token 使用统计
 	Usage Usage `json:"usage"`
 
 	// FinishReason 结束原因
 	FinishReason string `json:"finish_reason,omitempty"`
 
+	// ContentFilter 厂商内置内容过滤的判定结果（目前仅 Azure OpenAI 返回），未返回时为 nil
+	ContentFilter *ContentFilterResults `json:"content_filter,omitempty"`
+
 	// Created 创建时间戳
 	Created int64 `json:"created"`
 }
 
+// ContentFilterResults 厂商内置内容过滤对输入和输出的判定
+//
+// 类别名称沿用厂商原始命名（如 Azure 的 "hate"、"self_harm"、"jailbreak"），
+// CategoryScores 为归一化的严重程度：safe=0、low≈0.33、medium≈0.67、high=1。
+type ContentFilterResults struct {
+	// Prompt 输入的判定
+	Prompt ModerationResult `json:"prompt"`
+
+	// Completion 输出的判定（对应 Choices[0]）
+	Completion ModerationResult `json:"completion"`
+}
+
+// WantsLogprobs 检查请求是否需要返回 Token 对数概率
+func (r *CompletionRequest) WantsLogprobs() bool {
+	return r.Logprobs || r.TopLogprobs > 0
+}
+
 // HasToolCalls 检查响应是否包含工具调用
 func (r *CompletionResponse) HasToolCalls() bool {
 	return len(r.ToolCalls) > 0
@@ -355,6 +1070,7 @@ const (
 	FeatureEmbedding       = "embedding"        // 向量嵌入
 	FeatureImageGeneration = "image_generation" // 图片生成
 	FeatureVideoGeneration = "video_generation" // 视频生成
+	FeatureReasoning       = "reasoning"        // 推理控制（CompletionRequest.Reasoning）
 )
 
 // ============== 便捷函数 ==============
@@ -251,3 +251,79 @@ func TestUsage(t *testing.T) {
 		t.Errorf("TotalTokens = %d, want 150", usage.TotalTokens)
 	}
 }
+
+func TestApplyReasoningPolicy(t *testing.T) {
+	history := []Message{
+		UserMessage("q"),
+		{Role: RoleAssistant, Content: "a", Reasoning: "r"},
+	}
+
+	stripped := ApplyReasoningPolicy(history, "")
+	if stripped[1].Reasoning != "" {
+		t.Errorf("default policy should strip reasoning, got %q", stripped[1].Reasoning)
+	}
+	if history[1].Reasoning != "r" {
+		t.Error("ApplyReasoningPolicy must not modify the input slice")
+	}
+
+	preserved := ApplyReasoningPolicy(history, ReasoningPreserve)
+	if preserved[1].Reasoning != "r" {
+		t.Errorf("preserve policy should keep reasoning, got %q", preserved[1].Reasoning)
+	}
+}
+
+func TestCompletionResponse_ToMessage(t *testing.T) {
+	resp := &CompletionResponse{
+		Content:   "done",
+		Reasoning: "thinking",
+		ToolCalls: []ToolCall{{ID: "call_1", Name: "search", Arguments: `{"q":"go"}`}},
+	}
+	msg := resp.ToMessage()
+	if msg.Role != RoleAssistant || msg.Content != "done" || msg.Reasoning != "thinking" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].ID != "call_1" || msg.ToolCalls[0].Name != "search" {
+		t.Errorf("unexpected tool calls: %+v", msg.ToolCalls)
+	}
+}
+
+func TestReasoningConfig(t *testing.T) {
+	var nilCfg *ReasoningConfig
+	if nilCfg.Enabled() || nilCfg.Budget() != 0 || nilCfg.EffortLevel() != "" || nilCfg.Excluded() {
+		t.Error("nil config should be a no-op")
+	}
+
+	// 只设置 Exclude 时视为未指定，不开启推理
+	excludeOnly := &ReasoningConfig{Exclude: true}
+	if excludeOnly.Specified() || excludeOnly.Enabled() || !excludeOnly.Excluded() {
+		t.Error("exclude-only config should leave reasoning unspecified")
+	}
+	if !(&ReasoningConfig{BudgetTokens: 1024}).Enabled() || !(&ReasoningConfig{Effort: ReasoningEffortLow}).Enabled() {
+		t.Error("effort or budget should enable reasoning")
+	}
+
+	off := &ReasoningConfig{Effort: ReasoningEffortNone, BudgetTokens: 4096}
+	if off.Enabled() || off.Budget() != 0 {
+		t.Errorf("effort=none should disable reasoning, budget=%d", off.Budget())
+	}
+
+	tests := []struct {
+		cfg        ReasoningConfig
+		wantBudget int
+		wantEffort ReasoningEffort
+	}{
+		{ReasoningConfig{Effort: ReasoningEffortHigh}, 24576, ReasoningEffortHigh},
+		{ReasoningConfig{BudgetTokens: 2000}, 2000, ReasoningEffortLow},
+		{ReasoningConfig{BudgetTokens: 5000}, 5000, ReasoningEffortMedium},
+		{ReasoningConfig{BudgetTokens: 32000}, 32000, ReasoningEffortHigh},
+		{ReasoningConfig{}, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.Budget(); got != tt.wantBudget {
+			t.Errorf("%+v Budget() = %d, want %d", tt.cfg, got, tt.wantBudget)
+		}
+		if got := tt.cfg.EffortLevel(); got != tt.wantEffort {
+			t.Errorf("%+v EffortLevel() = %q, want %q", tt.cfg, got, tt.wantEffort)
+		}
+	}
+}
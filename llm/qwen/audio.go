@@ -0,0 +1,40 @@
+package qwen
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+const (
+	defaultTranscriptionModel = "qwen3-asr-flash"
+	defaultSpeechModel        = "qwen-tts"
+	defaultSpeechVoice        = "Cherry"
+)
+
+// Transcribe 调用 DashScope 兼容模式的 /audio/transcriptions 端点转写音频
+//
+// 协议与 OpenAI 一致，复用 openai.Provider 的实现，仅替换默认模型。
+func (p *Provider) Transcribe(ctx context.Context, req llm.TranscriptionRequest) (*llm.TranscriptionResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultTranscriptionModel
+	}
+	return p.compatible().Transcribe(ctx, req)
+}
+
+// SynthesizeSpeech 调用 DashScope 兼容模式的 /audio/speech 端点合成语音
+func (p *Provider) SynthesizeSpeech(ctx context.Context, req llm.SpeechRequest) (*llm.SpeechResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultSpeechModel
+	}
+	if req.Voice == "" {
+		req.Voice = defaultSpeechVoice
+	}
+	return p.compatible().SynthesizeSpeech(ctx, req)
+}
+
+// 确保通义千问 Provider 实现了音频相关接口
+var (
+	_ llm.TranscriptionProvider = (*Provider)(nil)
+	_ llm.SpeechProvider        = (*Provider)(nil)
+)
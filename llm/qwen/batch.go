@@ -0,0 +1,32 @@
+package qwen
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// CreateBatch 创建批量任务
+//
+// DashScope 兼容模式的 Batch API 与 OpenAI 一致，文件、任务接口与单行请求体均复用 openai 实现。
+func (p *Provider) CreateBatch(ctx context.Context, req llm.BatchRequest) (*llm.BatchJob, error) {
+	return p.compatible().CreateBatch(ctx, req)
+}
+
+// GetBatch 查询批量任务状态
+func (p *Provider) GetBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	return p.compatible().GetBatch(ctx, batchID)
+}
+
+// GetBatchResults 下载批量任务结果，按 CustomID 索引
+func (p *Provider) GetBatchResults(ctx context.Context, batchID string) (map[string]llm.BatchResult, error) {
+	return p.compatible().GetBatchResults(ctx, batchID)
+}
+
+// CancelBatch 取消批量任务
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) (*llm.BatchJob, error) {
+	return p.compatible().CancelBatch(ctx, batchID)
+}
+
+// 确保通义千问 Provider 实现了 BatchProvider 接口
+var _ llm.BatchProvider = (*Provider)(nil)
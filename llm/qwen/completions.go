@@ -0,0 +1,32 @@
+package qwen
+
+import (
+	"context"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/streamx"
+)
+
+// defaultCoderModel 文本补全的默认模型
+// 兼容模式的 /completions 端点仅支持 Coder 系列模型
+const defaultCoderModel = "qwen2.5-coder-32b-instruct"
+
+// CompleteText 执行文本补全（FIM 代码补全）
+// 设置 Suffix 时前后缀按 <|fim_prefix|>…<|fim_suffix|>…<|fim_middle|> 拼接为提示词
+func (p *Provider) CompleteText(ctx context.Context, req llm.TextCompletionRequest) (*llm.TextCompletionResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultCoderModel
+	}
+	return p.compatible().CompleteText(ctx, req)
+}
+
+// StreamText 执行流式文本补全
+func (p *Provider) StreamText(ctx context.Context, req llm.TextCompletionRequest) (*streamx.Stream, error) {
+	if req.Model == "" {
+		req.Model = defaultCoderModel
+	}
+	return p.compatible().StreamText(ctx, req)
+}
+
+// 确保通义千问 Provider 实现了 CompletionTextProvider 接口
+var _ llm.CompletionTextProvider = (*Provider)(nil)
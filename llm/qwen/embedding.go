@@ -7,13 +7,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"sort"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
 )
 
-// 默认 Embedding 模型
-const defaultEmbeddingModel = "text-embedding-v3"
+const (
+	// 默认 Embedding 模型
+	defaultEmbeddingModel = "text-embedding-v3"
+
+	// maxEmbeddingInputs 兼容模式单次请求的最大输入条数
+	maxEmbeddingInputs = 10
+)
 
 // EmbeddingDimension 返回指定 Embedding 模型的默认向量维度
 //
@@ -64,10 +69,44 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 	if len(texts) == 0 {
 		return nil, nil
 	}
+	resp, err := p.CreateEmbeddings(ctx, llm.EmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+// CreateEmbeddings 按完整请求参数生成嵌入
+//
+// 兼容模式单次最多 maxEmbeddingInputs 条输入，超出时自动分批；
+// Dimensions 映射为 dimensions（text-embedding-v3/v4 支持 64~2048 的若干档位），
+// InputType 映射为 text_type（query/document）。兼容模式仅返回 float 向量，Base64 被忽略。
+func (p *Provider) CreateEmbeddings(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultEmbeddingModel
+	}
+	if len(req.Input) == 0 {
+		return &llm.EmbedResponse{Model: req.Model}, nil
+	}
+	return llm.EmbedBatches(ctx, req, maxEmbeddingInputs, func(ctx context.Context, input []string) (*llm.EmbedResponse, error) {
+		return p.embedBatch(ctx, req, input)
+	})
+}
 
+// embedBatch 发送单批 /embeddings 请求
+func (p *Provider) embedBatch(ctx context.Context, req llm.EmbedRequest, input []string) (*llm.EmbedResponse, error) {
 	payload := map[string]any{
-		"model": model,
-		"input": texts,
+		"model": req.Model,
+		"input": input,
+	}
+	if req.Dimensions > 0 {
+		payload["dimensions"] = req.Dimensions
+	}
+	if req.InputType != "" {
+		payload["text_type"] = string(req.InputType)
+	}
+	if err := llm.MergeExtraBody(payload, req.ExtraBody); err != nil {
+		return nil, err
 	}
 
 	body, err := json.Marshal(payload)
@@ -95,31 +134,12 @@ func (p *Provider) EmbedWithModel(ctx context.Context, model string, texts []str
 		return nil, fmt.Errorf("qwen embedding api error: %s, body: %s", resp.Status, string(bodyBytes))
 	}
 
-	var result embeddingResponse
+	var result openai.EmbeddingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("解析 embedding 响应失败: %w", err)
 	}
-
-	// 按 index 排序确保顺序正确
-	sort.Slice(result.Data, func(i, j int) bool {
-		return result.Data[i].Index < result.Data[j].Index
-	})
-
-	embeddings := make([][]float32, len(result.Data))
-	for i, item := range result.Data {
-		embeddings[i] = item.Embedding
-	}
-
-	return embeddings, nil
-}
-
-// embeddingResponse OpenAI 兼容 Embedding API 响应结构
-type embeddingResponse struct {
-	Data []struct {
-		Index     int       `json:"index"`
-		Embedding []float32 `json:"embedding"`
-	} `json:"data"`
+	return result.ToEmbedResponse()
 }
 
 // 确保实现了 EmbeddingProvider 接口
-var _ llm.EmbeddingProvider = (*Provider)(nil)
+var _ llm.EmbedRequestProvider = (*Provider)(nil)
@@ -0,0 +1,54 @@
+package qwen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestCreateEmbeddings_Batching(t *testing.T) {
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input      []string `json:"input"`
+			TextType   string   `json:"text_type"`
+			Dimensions int      `json:"dimensions"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.TextType != "query" || body.Dimensions != 512 {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		sizes = append(sizes, len(body.Input))
+		items := make([]string, len(body.Input))
+		for i := range body.Input {
+			items[i] = fmt.Sprintf(`{"index":%d,"embedding":[%d]}`, i, i)
+		}
+		fmt.Fprintf(w, `{"data":[%s],"usage":{"prompt_tokens":%d,"total_tokens":%d}}`, strings.Join(items, ","), len(body.Input), len(body.Input))
+	}))
+	defer srv.Close()
+
+	input := make([]string, 23)
+	for i := range input {
+		input[i] = fmt.Sprintf("text %d", i)
+	}
+	p := New("key", WithBaseURL(srv.URL))
+	resp, err := p.CreateEmbeddings(t.Context(), llm.EmbedRequest{
+		Input:      input,
+		Dimensions: 512,
+		InputType:  llm.EmbedInputQuery,
+	})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings error: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[10 10 3]" {
+		t.Errorf("batch sizes = %v, want [10 10 3]", sizes)
+	}
+	if len(resp.Embeddings) != 23 || resp.Embeddings[12][0] != 2 || resp.Usage.TotalTokens != 23 {
+		t.Errorf("unexpected response: %d embeddings, usage %+v", len(resp.Embeddings), resp.Usage)
+	}
+}
@@ -2,19 +2,16 @@
 package qwen
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
+	"maps"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/llm/openai"
 	"github.com/hexagon-codes/ai-core/streamx"
 	"github.com/hexagon-codes/toolkit/net/httpx"
-	"github.com/hexagon-codes/toolkit/util/logger"
 )
 
 const (
@@ -28,6 +25,7 @@ type Provider struct {
 	baseURL    string
 	model      string
 	httpClient *http.Client
+	chat       *openai.Provider // 对话与批量接口的兼容实现，New 中创建
 }
 
 // Option 是 Provider 的配置选项
@@ -74,6 +72,11 @@ func New(apiKey string, opts ...Option) *Provider {
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.chat = openai.NewCompatible(profile, p.apiKey,
+		openai.WithBaseURL(p.baseURL),
+		openai.WithModel(p.model),
+		openai.WithHTTPClient(p.httpClient),
+	)
 
 	return p
 }
@@ -85,131 +88,72 @@ func (p *Provider) Name() string {
 
 // Complete 执行非流式补全请求
 func (p *Provider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.CompletionResponse, error) {
-	if req.Model == "" {
-		req.Model = p.model
-	}
-
-	body, err := p.buildRequestBody(req, false)
-	if err != nil {
-		return nil, err
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-
-	p.setHeaders(httpReq)
-
-	resp, err := p.httpClient.Do(httpReq)
-	if err != nil {
-		logger.WarnContext(ctx, "qwen http request failed",
-			logger.Component("qwen"),
-			logger.Action("complete"),
-			logger.String("model", req.Model),
-			logger.String("base_url", p.baseURL),
-			logger.Err(err),
-		)
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			logger.ErrorContext(ctx, "qwen api error and body read failed",
-				logger.Component("qwen"),
-				logger.Action("complete"),
-				logger.String("model", req.Model),
-				logger.Status(resp.StatusCode),
-				logger.Err(readErr),
-			)
-			return nil, fmt.Errorf("qwen api error: %s (failed to read body: %v)", resp.Status, readErr)
-		}
-		logger.ErrorContext(ctx, "qwen api non-2xx response",
-			logger.Component("qwen"),
-			logger.Action("complete"),
-			logger.String("model", req.Model),
-			logger.Status(resp.StatusCode),
-			logger.String("body", string(bodyBytes)),
-		)
-		return nil, fmt.Errorf("qwen api error: %s, body: %s", resp.Status, string(bodyBytes))
-	}
-
-	var result qwenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		logger.WarnContext(ctx, "qwen response json decode failed",
-			logger.Component("qwen"),
-			logger.Action("complete"),
-			logger.String("model", req.Model),
-			logger.Err(err),
-		)
-		return nil, err
-	}
-
-	return p.parseResponse(&result), nil
+	return p.compatible().Complete(ctx, searchFromMetadata(req))
 }
 
 // Stream 执行流式补全请求
 func (p *Provider) Stream(ctx context.Context, req llm.CompletionRequest) (*streamx.Stream, error) {
-	if req.Model == "" {
-		req.Model = p.model
-	}
+	return p.compatible().Stream(ctx, searchFromMetadata(req))
+}
 
-	body, err := p.buildRequestBody(req, true)
-	if err != nil {
-		return nil, err
+// searchFromMetadata 将旧版 Metadata["enable_search"] 映射到 ExtraBody，ExtraBody 已设置时以其为准
+// 返回的请求持有新的 ExtraBody，不修改调用方的 map。新代码应使用 ExtraBody
+func searchFromMetadata(req llm.CompletionRequest) llm.CompletionRequest {
+	if enableSearch, ok := req.Metadata["enable_search"].(bool); !ok || !enableSearch {
+		return req
 	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
+	if _, ok := req.ExtraBody["enable_search"]; ok {
+		return req
 	}
+	extra := make(map[string]any, len(req.ExtraBody)+1)
+	maps.Copy(extra, req.ExtraBody)
+	extra["enable_search"] = true
+	req.ExtraBody = extra
+	return req
+}
+
+// Models 返回可用模型列表
+func (p *Provider) Models() []llm.ModelInfo {
+	return p.compatible().Models()
+}
 
-	p.setHeaders(httpReq)
+// ListModels 获取远端模型目录并与内置元数据合并（带缓存）
+func (p *Provider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	return p.compatible().ListModels(ctx)
+}
 
-	resp, err := p.httpClient.Do(httpReq)
-	if err != nil {
-		logger.WarnContext(ctx, "qwen stream http request failed",
-			logger.Component("qwen"),
-			logger.Action("stream"),
-			logger.String("model", req.Model),
-			logger.String("base_url", p.baseURL),
-			logger.Err(err),
-		)
-		return nil, err
-	}
+// CountTokens 计算消息的 Token 数量（简化实现）
+func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
+	return p.compatible().CountTokens(messages)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, readErr := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if readErr != nil {
-			logger.ErrorContext(ctx, "qwen stream api error and body read failed",
-				logger.Component("qwen"),
-				logger.Action("stream"),
-				logger.String("model", req.Model),
-				logger.Status(resp.StatusCode),
-				logger.Err(readErr),
-			)
-			return nil, fmt.Errorf("qwen api error: %s (failed to read body: %v)", resp.Status, readErr)
-		}
-		logger.ErrorContext(ctx, "qwen stream api non-2xx response",
-			logger.Component("qwen"),
-			logger.Action("stream"),
-			logger.String("model", req.Model),
-			logger.Status(resp.StatusCode),
-			logger.String("body", string(bodyBytes)),
-		)
-		return nil, fmt.Errorf("qwen api error: %s, body: %s", resp.Status, string(bodyBytes))
-	}
+// setHeaders 设置请求头
+func (p *Provider) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+}
 
-	// 通义千问使用 OpenAI 兼容格式
-	return streamx.NewStreamWithContext(ctx, resp.Body, streamx.OpenAIFormat), nil
+// compatible 返回指向同一兼容模式端点、按通义千问 Profile 配置的 OpenAI Provider
+func (p *Provider) compatible() *openai.Provider {
+	return p.chat
 }
 
-// Models 返回可用模型列表
-func (p *Provider) Models() []llm.ModelInfo {
-	return []llm.ModelInfo{
+// profile 通义千问兼容模式的差异：
+//   - 不支持 frequency_penalty、user
+//   - 支持 top_k、repetition_penalty
+//   - 混合推理模型（Qwen3、QwQ）使用 enable_thinking/thinking_budget
+//   - 特有参数（如 enable_search）通过 ExtraBody 传递，兼容旧版 Metadata["enable_search"]
+//   - Coder 模型的 FIM 补全不支持 suffix 参数，需用特殊 Token 拼接提示词
+var profile = openai.Profile{
+	Name:          "qwen",
+	BaseURL:       defaultBaseURL,
+	DefaultModel:  defaultModel,
+	APIKeyEnv:     []string{"DASHSCOPE_API_KEY", "QWEN_API_KEY"},
+	Unsupported:   []string{"frequency_penalty", "user"},
+	Reasoning:     openai.EnableThinking,
+	FIMPrompt:     openai.QwenFIMPrompt,
+	CharsPerToken: 2.5, // 中英混合
+	Models: []llm.ModelInfo{
 		{
 			ID:          "qwen-max",
 			Name:        "Qwen Max",
@@ -235,7 +179,7 @@ func (p *Provider) Models() []llm.ModelInfo {
 			MaxTokens:   131072,
 			InputCost:   0.80,
 			OutputCost:  2.00,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
 		},
 		{
 			ID:          "qwen-turbo",
@@ -244,7 +188,7 @@ func (p *Provider) Models() []llm.ModelInfo {
 			MaxTokens:   131072,
 			InputCost:   0.30,
 			OutputCost:  0.60,
-			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming},
+			Features:    []string{llm.FeatureFunctions, llm.FeatureJSON, llm.FeatureStreaming, llm.FeatureReasoning},
 		},
 		{
 			ID:          "qwen-vl-max",
@@ -264,144 +208,21 @@ func (p *Provider) Models() []llm.ModelInfo {
 			OutputCost:  8.00,
 			Features:    []string{llm.FeatureVision, llm.FeatureStreaming},
 		},
-	}
-}
-
-// CountTokens 计算消息的 Token 数量（简化实现）
-func (p *Provider) CountTokens(messages []llm.Message) (int, error) {
-	// 简化估算：中文约 1.5 字符一个 token，英文约 4 字符一个 token
-	// 这里使用保守估计
-	var total int
-	for _, msg := range messages {
-		// 假设混合语言，使用 2.5 字符每 token
-		total += len(msg.Content) * 2 / 5
-	}
-	return total, nil
-}
-
-// setHeaders 设置请求头
-func (p *Provider) setHeaders(req *http.Request) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
-}
-
-// buildRequestBody 构建请求体
-func (p *Provider) buildRequestBody(req llm.CompletionRequest, stream bool) ([]byte, error) {
-	payload := map[string]any{
-		"model":    req.Model,
-		"messages": convertMessages(req.Messages),
-		"stream":   stream,
-	}
-
-	if len(req.Tools) > 0 {
-		payload["tools"] = req.Tools
-	}
-	if req.ToolChoice != nil {
-		payload["tool_choice"] = req.ToolChoice
-	}
-	if req.MaxTokens > 0 {
-		payload["max_tokens"] = req.MaxTokens
-	}
-	if req.Temperature != nil {
-		payload["temperature"] = *req.Temperature
-	}
-	if req.TopP != nil {
-		payload["top_p"] = *req.TopP
-	}
-	if len(req.Stop) > 0 {
-		payload["stop"] = req.Stop
-	}
-
-	// 通义千问特有参数
-	if req.Metadata != nil {
-		if enableSearch, ok := req.Metadata["enable_search"].(bool); ok && enableSearch {
-			payload["enable_search"] = true
-		}
-	}
-
-	// ResponseFormat 支持（兼容 OpenAI 格式）
-	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
-		payload["response_format"] = map[string]any{"type": "json_object"}
-	}
-
-	return json.Marshal(payload)
-}
-
-// convertMessages 转换消息格式
-func convertMessages(messages []llm.Message) []map[string]any {
-	result := make([]map[string]any, len(messages))
-	for i, msg := range messages {
-		m := map[string]any{
-			"role":    string(msg.Role),
-			"content": msg.Content,
-		}
-		if msg.Name != "" {
-			m["name"] = msg.Name
-		}
-		result[i] = m
-	}
-	return result
-}
-
-// 通义千问 API 响应结构（兼容 OpenAI 格式）
-type qwenResponse struct {
-	ID      string `json:"id"`
-	Model   string `json:"model"`
-	Created int64  `json:"created"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string `json:"role"`
-			Content   string `json:"content"`
-			ToolCalls []struct {
-				ID       string `json:"id"`
-				Type     string `json:"type"`
-				Function struct {
-					Name      string `json:"name"`
-					Arguments string `json:"arguments"`
-				} `json:"function"`
-			} `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-}
-
-// parseResponse 解析响应
-func (p *Provider) parseResponse(resp *qwenResponse) *llm.CompletionResponse {
-	result := &llm.CompletionResponse{
-		ID:      resp.ID,
-		Model:   resp.Model,
-		Created: resp.Created,
-		Usage: llm.Usage{
-			PromptTokens:     resp.Usage.PromptTokens,
-			CompletionTokens: resp.Usage.CompletionTokens,
-			TotalTokens:      resp.Usage.TotalTokens,
+		{
+			ID:          defaultCoderModel,
+			Name:        "Qwen2.5 Coder 32B",
+			Description: "通义千问代码模型，支持 FIM 代码补全",
+			MaxTokens:   131072,
+			InputCost:   2.00,
+			OutputCost:  6.00,
+			Features:    []string{llm.FeatureFunctions, llm.FeatureStreaming},
 		},
-	}
-
-	if len(resp.Choices) > 0 {
-		choice := resp.Choices[0]
-		result.Content = choice.Message.Content
-		result.FinishReason = choice.FinishReason
-
-		for _, tc := range choice.Message.ToolCalls {
-			result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
-				ID:        tc.ID,
-				Type:      tc.Type,
-				Name:      tc.Function.Name,
-				Arguments: tc.Function.Arguments,
-			})
-		}
-	}
-
-	return result
+	},
 }
 
-// 确保实现了 Provider 接口
+// 确保实现了 Provider、ModelLister 接口
 // EmbeddingProvider 接口验证在 embedding.go 中
-var _ llm.Provider = (*Provider)(nil)
+var (
+	_ llm.Provider    = (*Provider)(nil)
+	_ llm.ModelLister = (*Provider)(nil)
+)
@@ -0,0 +1,33 @@
+package qwen
+
+import (
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+func TestSearchFromMetadata(t *testing.T) {
+	extra := map[string]any{"top_k": 20}
+	req := searchFromMetadata(llm.CompletionRequest{
+		Metadata:  map[string]any{"enable_search": true},
+		ExtraBody: extra,
+	})
+	if req.ExtraBody["enable_search"] != true || req.ExtraBody["top_k"] != 20 {
+		t.Errorf("ExtraBody = %v", req.ExtraBody)
+	}
+	if _, ok := extra["enable_search"]; ok {
+		t.Error("caller's ExtraBody was modified")
+	}
+
+	req = searchFromMetadata(llm.CompletionRequest{
+		Metadata:  map[string]any{"enable_search": true},
+		ExtraBody: map[string]any{"enable_search": false},
+	})
+	if req.ExtraBody["enable_search"] != false {
+		t.Errorf("explicit ExtraBody should win, got %v", req.ExtraBody)
+	}
+
+	if req := searchFromMetadata(llm.CompletionRequest{}); req.ExtraBody != nil {
+		t.Errorf("ExtraBody = %v, want nil", req.ExtraBody)
+	}
+}
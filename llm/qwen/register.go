@@ -0,0 +1,16 @@
+package qwen
+
+import "github.com/hexagon-codes/ai-core/llm"
+
+func init() {
+	llm.RegisterProvider("qwen", func(cfg llm.ProviderConfig) (llm.Provider, error) {
+		var opts []Option
+		if cfg.BaseURL != "" {
+			opts = append(opts, WithBaseURL(cfg.BaseURL))
+		}
+		if cfg.Model != "" {
+			opts = append(opts, WithModel(cfg.Model))
+		}
+		return New(cfg.APIKey, opts...), nil
+	})
+}
@@ -0,0 +1,113 @@
+package qwen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// 默认重排序模型
+const defaultRerankModel = "gte-rerank-v2"
+
+// Rerank 调用 DashScope 文本重排序服务（gte-rerank）
+//
+// 重排序不在兼容模式下提供，使用 DashScope 原生接口：
+// {baseURL 去掉 /compatible-mode/v1}/api/v1/services/rerank/text-rerank/text-rerank
+func (p *Provider) Rerank(ctx context.Context, req llm.RerankRequest) (*llm.RerankResponse, error) {
+	if req.Model == "" {
+		req.Model = defaultRerankModel
+	}
+
+	payload := map[string]any{
+		"model": req.Model,
+		"input": map[string]any{
+			"query":     req.Query,
+			"documents": req.Documents,
+		},
+	}
+	parameters := map[string]any{"return_documents": req.ReturnDocuments}
+	if req.TopN > 0 {
+		parameters["top_n"] = req.TopN
+	}
+	payload["parameters"] = parameters
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化重排序请求失败: %w", err)
+	}
+
+	url := nativeBaseURL(p.baseURL) + "/services/rerank/text-rerank/text-rerank"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("重排序请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if readErr != nil {
+			return nil, fmt.Errorf("qwen rerank api error: %s (failed to read body: %v)", resp.Status, readErr)
+		}
+		return nil, fmt.Errorf("qwen rerank api error: %s, body: %s", resp.Status, string(bodyBytes))
+	}
+
+	var result rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析重排序响应失败: %w", err)
+	}
+
+	out := &llm.RerankResponse{
+		ID:      result.RequestID,
+		Model:   req.Model,
+		Usage:   llm.Usage{PromptTokens: result.Usage.TotalTokens, TotalTokens: result.Usage.TotalTokens},
+		Results: make([]llm.RerankResult, len(result.Output.Results)),
+	}
+	for i, r := range result.Output.Results {
+		out.Results[i] = llm.RerankResult{
+			Index:          r.Index,
+			RelevanceScore: r.RelevanceScore,
+			Document:       r.Document.Text,
+		}
+	}
+	return out, nil
+}
+
+// nativeBaseURL 由兼容模式地址推导 DashScope 原生接口地址
+func nativeBaseURL(baseURL string) string {
+	if base, ok := strings.CutSuffix(strings.TrimSuffix(baseURL, "/"), "/compatible-mode/v1"); ok {
+		return base + "/api/v1"
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// rerankResponse DashScope 重排序响应结构（结果已按相关性降序）
+type rerankResponse struct {
+	RequestID string `json:"request_id"`
+	Output    struct {
+		Results []struct {
+			Index          int     `json:"index"`
+			RelevanceScore float64 `json:"relevance_score"`
+			Document       struct {
+				Text string `json:"text"`
+			} `json:"document"`
+		} `json:"results"`
+	} `json:"output"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// 确保通义千问 Provider 实现了 RerankProvider 接口
+var _ llm.RerankProvider = (*Provider)(nil)
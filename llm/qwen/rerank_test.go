@@ -0,0 +1,16 @@
+package qwen
+
+import "testing"
+
+func TestNativeBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"https://dashscope.aliyuncs.com/compatible-mode/v1":       "https://dashscope.aliyuncs.com/api/v1",
+		"https://dashscope-intl.aliyuncs.com/compatible-mode/v1/": "https://dashscope-intl.aliyuncs.com/api/v1",
+		"http://proxy.local/api/v1":                               "http://proxy.local/api/v1",
+	}
+	for in, want := range tests {
+		if got := nativeBaseURL(in); got != want {
+			t.Errorf("nativeBaseURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
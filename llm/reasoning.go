@@ -0,0 +1,171 @@
+package llm
+
+// ReasoningPolicy 历史推理内容的回传策略
+//
+// 推理模型（DeepSeek-R1、QwQ、GLM 等）会返回独立的推理内容。
+// 多轮对话时，有的 Provider 要求剥离历史推理（DeepSeek 回传会报 400），
+// 有的则允许回传以延续推理上下文，因此由调用方通过 CompletionRequest.ReasoningHistory 显式选择。
+type ReasoningPolicy string
+
+const (
+	// ReasoningStrip 剥离历史推理内容（默认）
+	ReasoningStrip ReasoningPolicy = "strip"
+
+	// ReasoningPreserve 保留历史推理内容，由 Provider 按各自格式回传
+	//
+	// 目前回传的 Provider：OpenAI 兼容 Provider（Profile.ReasoningField，默认 reasoning_content）、
+	// 基于其实现的 Qwen 与 Ark，以及 Ollama（thinking 字段）。
+	// Anthropic 的 thinking 块需要签名、Gemini 需要 thought signature，Message 未保存签名，
+	// 这两个 Provider 不会回传历史推理内容；DeepSeek 始终剥离。
+	ReasoningPreserve ReasoningPolicy = "preserve"
+)
+
+// ApplyReasoningPolicy 按策略处理历史消息中的推理内容
+//
+// 策略为 ReasoningPreserve 时原样返回；否则返回剥离 Reasoning 后的副本，
+// 不修改调用方的切片。
+func ApplyReasoningPolicy(messages []Message, policy ReasoningPolicy) []Message {
+	if policy == ReasoningPreserve {
+		return messages
+	}
+
+	var out []Message
+	for i, msg := range messages {
+		if msg.Reasoning == "" {
+			continue
+		}
+		if out == nil {
+			out = make([]Message, len(messages))
+			copy(out, messages)
+		}
+		out[i].Reasoning = ""
+	}
+	if out == nil {
+		return messages
+	}
+	return out
+}
+
+// ToMessage 将响应转换为可追加到对话历史的 assistant 消息
+//
+// 推理内容会一并保留，是否回传由下一轮请求的 ReasoningHistory 决定。
+func (r *CompletionResponse) ToMessage() Message {
+	msg := Message{
+		Role:      RoleAssistant,
+		Content:   r.Content,
+		Reasoning: r.Reasoning,
+	}
+	for _, tc := range r.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, ToolCallRef{
+			ID:        tc.ID,
+			Name:      tc.Name,
+			Arguments: tc.Arguments,
+		})
+	}
+	return msg
+}
+
+// ReasoningEffort 推理强度等级
+type ReasoningEffort string
+
+const (
+	// ReasoningEffortNone 关闭推理（混合推理模型如 Qwen3、Claude 4、Gemini 2.5 Flash）
+	ReasoningEffortNone ReasoningEffort = "none"
+
+	// ReasoningEffortMinimal 最低推理强度
+	ReasoningEffortMinimal ReasoningEffort = "minimal"
+
+	// ReasoningEffortLow 低推理强度
+	ReasoningEffortLow ReasoningEffort = "low"
+
+	// ReasoningEffortMedium 中等推理强度
+	ReasoningEffortMedium ReasoningEffort = "medium"
+
+	// ReasoningEffortHigh 高推理强度
+	ReasoningEffortHigh ReasoningEffort = "high"
+)
+
+// 推理强度与 Token 预算的对应关系
+// 仅用于只接受其中一种参数的 Provider 之间的换算
+var reasoningEffortBudgets = map[ReasoningEffort]int{
+	ReasoningEffortMinimal: 1024,
+	ReasoningEffortLow:     2048,
+	ReasoningEffortMedium:  8192,
+	ReasoningEffortHigh:    24576,
+}
+
+// ReasoningConfig 统一的推理控制配置
+//
+// 各 Provider 将其映射为原生参数：
+//   - OpenAI: reasoning_effort
+//   - Anthropic: thinking.budget_tokens
+//   - Qwen: enable_thinking / thinking_budget
+//   - Ollama: think
+//   - Ark: thinking.type
+//   - Gemini: generationConfig.thinkingConfig
+//
+// Effort 与 BudgetTokens 可只设其一，缺失的一方按经验值换算；
+// 两者都未设置时视为未指定，Provider 不发送任何推理参数（保持模型默认行为），仅 Exclude 生效。
+// 模型是否支持可通过 ModelInfo.HasFeature(FeatureReasoning) 判断。
+type ReasoningConfig struct {
+	// Effort 推理强度，ReasoningEffortNone 表示关闭推理
+	Effort ReasoningEffort `json:"effort,omitempty"`
+
+	// BudgetTokens 推理 Token 预算，0 表示由 Effort 推导或使用 Provider 默认值
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+
+	// Exclude 不在响应中返回推理内容（推理仍会发生并计费）
+	// Gemini 在服务端关闭 includeThoughts，其余 Provider 在非流式响应中剔除
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// Specified 判断是否指定了推理开关或强度（Effort 或 BudgetTokens 非空）
+func (c *ReasoningConfig) Specified() bool {
+	return c != nil && (c.Effort != "" || c.BudgetTokens > 0)
+}
+
+// Enabled 判断是否显式开启推理，未指定时返回 false
+func (c *ReasoningConfig) Enabled() bool {
+	return c.Specified() && c.Effort != ReasoningEffortNone
+}
+
+// Budget 返回推理 Token 预算
+//
+// 优先使用 BudgetTokens，其次按 Effort 换算；两者都未设置时返回 0，
+// 由 Provider 决定是否使用默认值。
+func (c *ReasoningConfig) Budget() int {
+	if !c.Enabled() {
+		return 0
+	}
+	if c.BudgetTokens > 0 {
+		return c.BudgetTokens
+	}
+	return reasoningEffortBudgets[c.Effort]
+}
+
+// EffortLevel 返回推理强度等级
+//
+// 未设置 Effort 时按 BudgetTokens 就近换算为 low/medium/high；两者都未设置时返回空串。
+func (c *ReasoningConfig) EffortLevel() ReasoningEffort {
+	if c == nil {
+		return ""
+	}
+	if c.Effort != "" {
+		return c.Effort
+	}
+	switch {
+	case c.BudgetTokens <= 0:
+		return ""
+	case c.BudgetTokens <= reasoningEffortBudgets[ReasoningEffortLow]:
+		return ReasoningEffortLow
+	case c.BudgetTokens <= reasoningEffortBudgets[ReasoningEffortMedium]:
+		return ReasoningEffortMedium
+	default:
+		return ReasoningEffortHigh
+	}
+}
+
+// Excluded 判断是否需要从响应中剔除推理内容
+func (c *ReasoningConfig) Excluded() bool {
+	return c != nil && c.Exclude
+}
@@ -4,7 +4,8 @@ package llm
 //
 // 注：openai/deepseek 等 provider 构造函数无法在此 re-export，
 // 因为它们反向依赖 llm 包（会造成循环 import）。
-// provider 构造函数由 hexagon 顶层包 re-export。
+// provider 构造函数由 hexagon 顶层包 re-export；
+// 需要按名称构建时，使用各 provider 包注册的工厂（NewProvider）或 llm/config 包。
 
 import (
 	"io"
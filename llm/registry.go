@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProviderConfig 按类型构建 Provider 所需的配置
+//
+// APIKey、BaseURL、Model 为空时沿用各 Provider 的默认值（含环境变量）；
+// Options 承载 Provider 特有的配置，键名见各 Provider 包的注册说明。
+type ProviderConfig struct {
+	// Type Provider 类型（注册名，如 "openai"、"qwen"、"moonshot"）
+	Type string `json:"type"`
+
+	// APIKey API 密钥
+	APIKey string `json:"api_key,omitempty"`
+
+	// BaseURL API 基础 URL
+	BaseURL string `json:"base_url,omitempty"`
+
+	// Model 默认模型
+	Model string `json:"model,omitempty"`
+
+	// Options Provider 特有配置（如 Ark 的 endpoint_id、Azure 的 endpoint）
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// Option 返回 Provider 特有配置项，未设置时返回空串
+func (c ProviderConfig) Option(key string) string {
+	return c.Options[key]
+}
+
+// ProviderFactory 根据配置创建 Provider
+type ProviderFactory func(cfg ProviderConfig) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]ProviderFactory)
+)
+
+// RegisterProvider 注册 Provider 工厂
+//
+// 各 Provider 包在 init 中注册自身，导入即可使用：
+//
+//	import _ "github.com/hexagon-codes/ai-core/llm/openai"
+//
+//	p, err := llm.NewProvider(llm.ProviderConfig{Type: "openai", Model: "gpt-4o-mini"})
+//
+// 重复注册同一类型会覆盖之前的工厂。
+func RegisterProvider(typ string, factory ProviderFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[typ] = factory
+}
+
+// NewProvider 按类型创建已注册的 Provider
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[cfg.Type]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown provider type %q (registered: %s)", cfg.Type, strings.Join(ProviderTypes(), ", "))
+	}
+	return factory(cfg)
+}
+
+// ProviderTypes 返回已注册的 Provider 类型（按字母序）
+func ProviderTypes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	types := make([]string, 0, len(factories))
+	for typ := range factories {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
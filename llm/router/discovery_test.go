@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+type listerProvider struct {
+	mockRouterProvider
+	remote []llm.ModelInfo
+	err    error
+}
+
+func (p *listerProvider) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.models = p.remote
+	return p.remote, nil
+}
+
+func TestRouter_RefreshModels(t *testing.T) {
+	r := New(WithStrategy(StrategyModelMatch))
+	a := &listerProvider{
+		mockRouterProvider: mockRouterProvider{name: "a", models: []llm.ModelInfo{{ID: "shared"}}},
+		remote:             []llm.ModelInfo{{ID: "shared"}, {ID: "a-new"}},
+	}
+	b := &listerProvider{
+		mockRouterProvider: mockRouterProvider{name: "b"},
+		remote:             []llm.ModelInfo{{ID: "shared"}, {ID: "b-new"}},
+	}
+	down := &listerProvider{mockRouterProvider: mockRouterProvider{name: "down"}, err: errors.New("unreachable")}
+	r.Register("a", a).Register("b", b).Register("down", down)
+
+	err := r.RefreshModels(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "down") {
+		t.Errorf("error = %v, want failure for down", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	want := map[string]string{"shared": "a", "a-new": "a", "b-new": "b"}
+	for model, prov := range want {
+		if got := r.modelMap[model]; got != prov {
+			t.Errorf("modelMap[%s] = %q, want %q", model, got, prov)
+		}
+	}
+}
+
+func TestRouter_RefreshModelsThroughMiddleware(t *testing.T) {
+	r := New(WithStrategy(StrategyModelMatch))
+	inner := &listerProvider{
+		mockRouterProvider: mockRouterProvider{name: "a"},
+		remote:             []llm.ModelInfo{{ID: "a-new"}},
+	}
+	r.Register("a", llm.Chain(inner, llm.WithRetry(1, time.Millisecond), llm.WithTimeout(time.Second)))
+
+	if err := r.RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels failed: %v", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if got := r.modelMap["a-new"]; got != "a" {
+		t.Errorf("modelMap[a-new] = %q, want a", got)
+	}
+}
+
+func TestRouter_StartModelDiscoveryDefaultInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := New(WithStrategy(StrategyModelMatch))
+	r.Register("a", &listerProvider{
+		mockRouterProvider: mockRouterProvider{name: "a"},
+		remote:             []llm.ModelInfo{{ID: "a-new"}},
+	})
+
+	// interval <= 0 不应导致 time.NewTicker panic，且启动时立即刷新一次
+	r.StartModelDiscovery(ctx, 0)
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.mu.RLock()
+		got := r.modelMap["a-new"]
+		r.mu.RUnlock()
+		if got == "a" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("modelMap[a-new] = %q after initial refresh, want %q", got, "a")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -162,6 +162,79 @@ func (r *Router) Unregister(name string) {
 	}
 }
 
+// RefreshModels 刷新实现了 llm.ModelLister 的 Provider 的模型目录，
+// 并把新出现的模型映射到对应 Provider（已有映射保持不变）。
+// SmartRouter 的候选模型来自 Provider.Models()，刷新后即可路由到新模型。
+func (r *Router) RefreshModels(ctx context.Context) error {
+	r.mu.RLock()
+	names := make([]string, len(r.providerList))
+	copy(names, r.providerList)
+	providers := make(map[string]llm.Provider, len(r.providers))
+	for k, v := range r.providers {
+		providers[k] = v
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, name := range names {
+		lister, ok := modelLister(providers[name])
+		if !ok {
+			continue
+		}
+		models, err := lister.ListModels(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		r.mu.Lock()
+		if _, registered := r.providers[name]; registered {
+			for _, model := range models {
+				if _, exists := r.modelMap[model.ID]; !exists {
+					r.modelMap[model.ID] = name
+				}
+			}
+		}
+		r.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// modelLister 在 Provider 及其中间件包装的各层中查找 llm.ModelLister
+func modelLister(p llm.Provider) (llm.ModelLister, bool) {
+	for ; p != nil; p = llm.Unwrap(p) {
+		if lister, ok := p.(llm.ModelLister); ok {
+			return lister, true
+		}
+	}
+	return nil, false
+}
+
+// defaultDiscoveryInterval StartModelDiscovery 的默认刷新周期
+const defaultDiscoveryInterval = 10 * time.Minute
+
+// StartModelDiscovery 按 interval 周期性调用 RefreshModels，ctx 取消时停止
+// 启动时立即刷新一次；刷新失败会在下个周期重试。interval <= 0 时使用默认周期 10 分钟
+func (r *Router) StartModelDiscovery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultDiscoveryInterval
+	}
+	go func() {
+		_ = r.RefreshModels(ctx)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.RefreshModels(ctx)
+			}
+		}
+	}()
+}
+
 // Name 返回路由器名称
 func (r *Router) Name() string {
 	return "router"
@@ -0,0 +1,214 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============== 视频任务等待 ==============
+
+var (
+	// ErrVideoTaskFailed 表示视频任务以失败状态结束
+	// 可通过 errors.As 取得 *VideoTaskError 详情
+	ErrVideoTaskFailed = errors.New("llm: video task failed")
+
+	// ErrVideoWaitTimeout 表示等待超过 VideoWaitOptions.MaxWait 仍未结束
+	ErrVideoWaitTimeout = errors.New("llm: video task wait timeout")
+
+	// ErrVideoContentFiltered 表示任务因内容审核被拒绝
+	ErrVideoContentFiltered = errors.New("llm: video content filtered")
+
+	// ErrVideoQuotaExceeded 表示任务因额度、余额或限流失败
+	ErrVideoQuotaExceeded = errors.New("llm: video quota exceeded")
+
+	// ErrVideoInvalidInput 表示任务因参数或输入素材无效失败
+	ErrVideoInvalidInput = errors.New("llm: video invalid input")
+)
+
+// VideoTaskError 视频任务失败错误
+//
+// errors.Is 同时匹配 ErrVideoTaskFailed 和按失败原因归类的哨兵错误（如 ErrVideoContentFiltered）。
+type VideoTaskError struct {
+	// TaskID 任务 ID
+	TaskID string
+
+	// Reason 厂商返回的原始失败信息
+	Reason string
+
+	// Kind 归类后的失败原因，无法归类时为 nil
+	Kind error
+}
+
+// Error 实现 error 接口
+func (e *VideoTaskError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("llm: video task %s failed", e.TaskID)
+	}
+	return fmt.Sprintf("llm: video task %s failed: %s", e.TaskID, e.Reason)
+}
+
+// Is 使 errors.Is(err, ErrVideoTaskFailed) 及 errors.Is(err, e.Kind) 成立
+func (e *VideoTaskError) Is(target error) bool {
+	return target == ErrVideoTaskFailed || (e.Kind != nil && target == e.Kind)
+}
+
+// videoFailureKeywords 失败信息关键字到错误类型的映射（小写匹配）
+var videoFailureKeywords = []struct {
+	kind     error
+	keywords []string
+}{
+	{ErrVideoContentFiltered, []string{"sensitive", "content policy", "moderation", "safety", "违规", "敏感", "审核"}},
+	{ErrVideoQuotaExceeded, []string{"quota", "balance", "rate limit", "insufficient", "余额", "额度", "限流"}},
+	{ErrVideoInvalidInput, []string{"invalid", "parameter", "unsupported", "参数", "不支持"}},
+}
+
+// NewVideoTaskError 根据任务的失败信息构造 *VideoTaskError
+func NewVideoTaskError(task *VideoTask) *VideoTaskError {
+	err := &VideoTaskError{TaskID: task.ID, Reason: task.Error}
+	reason := strings.ToLower(task.Error)
+	for _, m := range videoFailureKeywords {
+		for _, kw := range m.keywords {
+			if strings.Contains(reason, kw) {
+				err.Kind = m.kind
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// VideoWaitOptions 视频任务轮询配置
+type VideoWaitOptions struct {
+	// InitialInterval 首次轮询间隔，默认 2s
+	InitialInterval time.Duration
+
+	// MaxInterval 轮询间隔上限，默认 30s
+	MaxInterval time.Duration
+
+	// Multiplier 每次轮询后间隔的放大倍数，默认 1.5
+	Multiplier float64
+
+	// MaxWait 最长等待时间，默认 10 分钟；超时返回 ErrVideoWaitTimeout
+	MaxWait time.Duration
+
+	// OnStatus 任务状态变化时回调（含首次查询到的状态）
+	OnStatus func(task *VideoTask)
+}
+
+func (o VideoWaitOptions) withDefaults() VideoWaitOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = 2 * time.Second
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = 30 * time.Second
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 1.5
+	}
+	if o.MaxWait <= 0 {
+		o.MaxWait = 10 * time.Minute
+	}
+	return o
+}
+
+// WaitVideoTask 轮询视频任务直至完成、失败、超时或 ctx 取消
+//
+// 轮询间隔按 Multiplier 指数增长并封顶于 MaxInterval。
+// 查询出错（网络抖动、5xx 等）时按同样的间隔重试，直至 MaxWait 或 ctx 取消。
+// 任务失败时返回最后一次查询到的任务和 *VideoTaskError；
+// 超时返回最后一次查询到的任务和 ErrVideoWaitTimeout（若最后一次查询出错，同时包装该错误）。
+//
+// 使用示例:
+//
+//	task, err := llm.WaitVideoTask(ctx, provider, taskID, llm.VideoWaitOptions{
+//	    OnStatus: func(t *llm.VideoTask) { log.Printf("video %s: %s", t.ID, t.Status) },
+//	})
+func WaitVideoTask(ctx context.Context, p VideoProvider, taskID string, opts VideoWaitOptions) (*VideoTask, error) {
+	opts = opts.withDefaults()
+	return waitVideoTask(ctx, p, taskID, "", opts)
+}
+
+// GenerateVideo 提交视频任务并等待其完成
+//
+// 等价于 CreateVideoTask 后调用 WaitVideoTask，OnStatus 会先收到提交时的初始状态。
+func GenerateVideo(ctx context.Context, p VideoProvider, req VideoRequest, opts VideoWaitOptions) (*VideoTask, error) {
+	opts = opts.withDefaults()
+
+	task, err := p.CreateVideoTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if opts.OnStatus != nil {
+		opts.OnStatus(task)
+	}
+	switch task.Status {
+	case VideoTaskCompleted:
+		return task, nil
+	case VideoTaskFailed:
+		return task, NewVideoTaskError(task)
+	}
+	return waitVideoTask(ctx, p, task.ID, task.Status, opts)
+}
+
+// waitVideoTask 轮询实现，last 为已知的上一个状态（用于判断状态变化）
+func waitVideoTask(ctx context.Context, p VideoProvider, taskID string, last VideoTaskStatus, opts VideoWaitOptions) (*VideoTask, error) {
+	deadline := time.NewTimer(opts.MaxWait)
+	defer deadline.Stop()
+
+	var task *VideoTask
+	var queryErr error
+	interval := opts.InitialInterval
+	for {
+		current, err := p.QueryVideoTask(ctx, taskID)
+		switch {
+		case err == nil:
+			queryErr = nil
+			task = current
+			if task.ID == "" {
+				task.ID = taskID
+			}
+
+			if task.Status != last {
+				last = task.Status
+				if opts.OnStatus != nil {
+					opts.OnStatus(task)
+				}
+			}
+			switch task.Status {
+			case VideoTaskCompleted:
+				return task, nil
+			case VideoTaskFailed:
+				return task, NewVideoTaskError(task)
+			}
+		case ctx.Err() != nil:
+			return task, ctx.Err()
+		case errors.As(err, new(*VideoTaskError)):
+			return task, err
+		default:
+			// 临时性错误：保留以便超时时一并返回，按退避间隔重试
+			queryErr = fmt.Errorf("query video task %s: %w", taskID, err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return task, ctx.Err()
+		case <-deadline.C:
+			timer.Stop()
+			if queryErr != nil {
+				return task, fmt.Errorf("%w: task %s after %s: %w", ErrVideoWaitTimeout, taskID, opts.MaxWait, queryErr)
+			}
+			return task, fmt.Errorf("%w: task %s still %s after %s", ErrVideoWaitTimeout, taskID, task.Status, opts.MaxWait)
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*opts.Multiplier), opts.MaxInterval)
+	}
+}
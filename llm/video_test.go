@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeVideoProvider 按顺序返回预设状态的 VideoProvider
+// errs 非空时前 len(errs) 次查询依次返回其中的非 nil 错误
+type fakeVideoProvider struct {
+	statuses []VideoTask
+	errs     []error
+	queries  int
+}
+
+func (f *fakeVideoProvider) CreateVideoTask(ctx context.Context, req VideoRequest) (*VideoTask, error) {
+	return &VideoTask{ID: "task-1", Status: VideoTaskQueued}, nil
+}
+
+func (f *fakeVideoProvider) QueryVideoTask(ctx context.Context, taskID string) (*VideoTask, error) {
+	if f.queries < len(f.errs) && f.errs[f.queries] != nil {
+		err := f.errs[f.queries]
+		f.queries++
+		return nil, err
+	}
+	i := min(f.queries, len(f.statuses)-1)
+	f.queries++
+	task := f.statuses[i]
+	return &task, nil
+}
+
+func fastWait() VideoWaitOptions {
+	return VideoWaitOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+}
+
+func TestGenerateVideo_ReportsTransitions(t *testing.T) {
+	p := &fakeVideoProvider{statuses: []VideoTask{
+		{ID: "task-1", Status: VideoTaskQueued},
+		{ID: "task-1", Status: VideoTaskProcessing},
+		{ID: "task-1", Status: VideoTaskProcessing},
+		{ID: "task-1", Status: VideoTaskCompleted, VideoURL: "https://example.com/v.mp4"},
+	}}
+
+	var seen []VideoTaskStatus
+	opts := fastWait()
+	opts.OnStatus = func(task *VideoTask) { seen = append(seen, task.Status) }
+
+	task, err := GenerateVideo(context.Background(), p, VideoRequest{Prompt: "cat"}, opts)
+	if err != nil {
+		t.Fatalf("GenerateVideo error: %v", err)
+	}
+	if task.VideoURL != "https://example.com/v.mp4" {
+		t.Errorf("VideoURL = %q", task.VideoURL)
+	}
+	want := []VideoTaskStatus{VideoTaskQueued, VideoTaskProcessing, VideoTaskCompleted}
+	if len(seen) != len(want) {
+		t.Fatalf("transitions = %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("transitions = %v, want %v", seen, want)
+			break
+		}
+	}
+}
+
+func TestWaitVideoTask_TypedFailure(t *testing.T) {
+	p := &fakeVideoProvider{statuses: []VideoTask{
+		{ID: "task-1", Status: VideoTaskFailed, Error: "prompt contains sensitive content"},
+	}}
+
+	_, err := WaitVideoTask(context.Background(), p, "task-1", fastWait())
+	if !errors.Is(err, ErrVideoTaskFailed) || !errors.Is(err, ErrVideoContentFiltered) {
+		t.Fatalf("err = %v, want ErrVideoTaskFailed and ErrVideoContentFiltered", err)
+	}
+	var taskErr *VideoTaskError
+	if !errors.As(err, &taskErr) || taskErr.TaskID != "task-1" {
+		t.Errorf("unexpected VideoTaskError: %+v", taskErr)
+	}
+}
+
+func TestWaitVideoTask_TimeoutAndCancel(t *testing.T) {
+	p := &fakeVideoProvider{statuses: []VideoTask{{ID: "task-1", Status: VideoTaskProcessing}}}
+
+	opts := fastWait()
+	opts.MaxWait = 10 * time.Millisecond
+	task, err := WaitVideoTask(context.Background(), p, "task-1", opts)
+	if !errors.Is(err, ErrVideoWaitTimeout) {
+		t.Fatalf("err = %v, want ErrVideoWaitTimeout", err)
+	}
+	if task == nil || task.Status != VideoTaskProcessing {
+		t.Errorf("expected last polled task, got %+v", task)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WaitVideoTask(ctx, p, "task-1", fastWait()); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestWaitVideoTask_RetriesQueryErrors(t *testing.T) {
+	transient := errors.New("503 service unavailable")
+	p := &fakeVideoProvider{
+		statuses: []VideoTask{{ID: "task-1", Status: VideoTaskCompleted}},
+		errs:     []error{transient, transient},
+	}
+	task, err := WaitVideoTask(context.Background(), p, "task-1", fastWait())
+	if err != nil || task.Status != VideoTaskCompleted {
+		t.Fatalf("task = %+v, err = %v, want completed after retries", task, err)
+	}
+	if p.queries != 3 {
+		t.Errorf("queries = %d, want 3", p.queries)
+	}
+
+	// 持续出错直至超时：同时包装超时与最后一次查询错误
+	down := &fakeVideoProvider{statuses: []VideoTask{{}}, errs: make([]error, 1000)}
+	for i := range down.errs {
+		down.errs[i] = transient
+	}
+	opts := fastWait()
+	opts.MaxWait = 10 * time.Millisecond
+	if _, err := WaitVideoTask(context.Background(), down, "task-1", opts); !errors.Is(err, ErrVideoWaitTimeout) || !errors.Is(err, transient) {
+		t.Errorf("err = %v, want ErrVideoWaitTimeout wrapping the query error", err)
+	}
+}
@@ -19,6 +19,13 @@ type Embedder interface {
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// QueryEmbedder 区分检索查询与文档编码的嵌入器（可选能力）
+// 实现后 Search 使用 EmbedQuery 生成查询向量
+type QueryEmbedder interface {
+	// EmbedQuery 生成检索查询的向量嵌入
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
 // VectorStore 向量存储接口
 // 可以由内存实现或外部向量数据库（Qdrant、Milvus 等）实现
 type VectorStore interface {
@@ -43,7 +50,7 @@ type VectorResult struct {
 	// ID 向量 ID
 	ID string `json:"id"`
 
-	// Score 相似度分数 (0-1, 越大越相似)
+	// Score 相似度分数，越大越相似（余弦相似度为 0-1，点积不做归一化）
 	Score float32 `json:"score"`
 
 	// Metadata 元数据
@@ -80,8 +87,23 @@ type VectorConfig struct {
 
 	// DefaultTopK 默认返回数量
 	DefaultTopK int
+
+	// Distance 默认内存向量存储的相似度度量，为空时使用余弦相似度
+	// 使用 DistanceDot 时 MinScore 作用于原始点积分数
+	Distance Distance
 }
 
+// Distance 相似度度量方式
+type Distance string
+
+const (
+	// DistanceCosine 余弦相似度（默认）
+	DistanceCosine Distance = "cosine"
+
+	// DistanceDot 点积，用于模长本身有意义的嵌入（如 BM25 稀疏编码）
+	DistanceDot Distance = "dot"
+)
+
 // DefaultVectorConfig 返回默认配置
 func DefaultVectorConfig() VectorConfig {
 	return VectorConfig{
@@ -116,6 +138,14 @@ func WithMinScore(score float32) VectorOption {
 	}
 }
 
+// WithDistance 设置默认内存向量存储的相似度度量
+// 使用 WithVectorStore 指定外部存储时由该存储自身决定度量方式
+func WithDistance(distance Distance) VectorOption {
+	return func(m *VectorMemory) {
+		m.config.Distance = distance
+	}
+}
+
 // WithVectorStore 设置向量存储
 func WithVectorStore(store VectorStore) VectorOption {
 	return func(m *VectorMemory) {
@@ -137,7 +167,7 @@ func NewVectorMemory(embedder Embedder, opts ...VectorOption) *VectorMemory {
 
 	// 如果没有设置向量存储，使用内存存储
 	if m.store == nil {
-		m.store = NewMemoryVectorStore(m.config.Dimension)
+		m.store = NewMemoryVectorStore(m.config.Dimension, WithStoreDistance(m.config.Distance))
 	}
 
 	return m
@@ -248,12 +278,20 @@ func (m *VectorMemory) Search(ctx context.Context, query SearchQuery) ([]Entry,
 	if len(query.Embedding) > 0 {
 		embedding = query.Embedding
 	} else if query.Query != "" {
-		embeddings, err := m.embedder.Embed(ctx, []string{query.Query})
-		if err != nil {
-			return nil, fmt.Errorf("generate query embedding: %w", err)
-		}
-		if len(embeddings) > 0 {
-			embedding = embeddings[0]
+		if qe, ok := m.embedder.(QueryEmbedder); ok {
+			e, err := qe.EmbedQuery(ctx, query.Query)
+			if err != nil {
+				return nil, fmt.Errorf("generate query embedding: %w", err)
+			}
+			embedding = e
+		} else {
+			embeddings, err := m.embedder.Embed(ctx, []string{query.Query})
+			if err != nil {
+				return nil, fmt.Errorf("generate query embedding: %w", err)
+			}
+			if len(embeddings) > 0 {
+				embedding = embeddings[0]
+			}
 		}
 	}
 
@@ -398,6 +436,7 @@ func generateVectorID() string {
 type MemoryVectorStore struct {
 	vectors   map[string]vectorEntry
 	dimension int
+	distance  Distance
 	mu        sync.RWMutex
 }
 
@@ -406,12 +445,26 @@ type vectorEntry struct {
 	metadata  map[string]any
 }
 
+// MemoryVectorStoreOption 内存向量存储配置选项
+type MemoryVectorStoreOption func(*MemoryVectorStore)
+
+// WithStoreDistance 设置相似度度量方式（默认余弦相似度）
+func WithStoreDistance(distance Distance) MemoryVectorStoreOption {
+	return func(s *MemoryVectorStore) {
+		s.distance = distance
+	}
+}
+
 // NewMemoryVectorStore 创建内存向量存储
-func NewMemoryVectorStore(dimension int) *MemoryVectorStore {
-	return &MemoryVectorStore{
+func NewMemoryVectorStore(dimension int, opts ...MemoryVectorStoreOption) *MemoryVectorStore {
+	s := &MemoryVectorStore{
 		vectors:   make(map[string]vectorEntry),
 		dimension: dimension,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Add 添加向量
@@ -449,7 +502,12 @@ func (s *MemoryVectorStore) Search(ctx context.Context, query []float32, topK in
 			}
 		}
 
-		score := cosineSimilarity(query, entry.embedding)
+		var score float32
+		if s.distance == DistanceDot {
+			score = dotProduct(query, entry.embedding)
+		} else {
+			score = cosineSimilarity(query, entry.embedding)
+		}
 		results = append(results, VectorResult{
 			ID:       id,
 			Score:    score,
@@ -513,6 +571,19 @@ func cosineSimilarity(a, b []float32) float32 {
 	return float32(dotProduct / (math.Sqrt(normA) * math.Sqrt(normB)))
 }
 
+// dotProduct 计算点积
+func dotProduct(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(sum)
+}
+
 // ============== 简单嵌入器实现 ==============
 
 // SimpleEmbedder 简单的基于函数的嵌入器
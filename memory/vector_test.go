@@ -182,3 +182,49 @@ func TestCosineSimilarity(t *testing.T) {
 		t.Errorf("expected ~-1.0, got %f", sim)
 	}
 }
+
+// queryEmbedder 查询与文档使用不同编码的嵌入器
+type queryEmbedder struct {
+	*mockEmbedder
+	queries []string
+}
+
+func (q *queryEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	q.queries = append(q.queries, text)
+	embeddings, err := q.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+func TestVectorMemory_SearchUsesQueryEmbedder(t *testing.T) {
+	embedder := &queryEmbedder{mockEmbedder: newMockEmbedder(8)}
+	mem := NewVectorMemory(embedder, WithMinScore(0))
+	ctx := context.Background()
+
+	if err := mem.Save(ctx, Entry{ID: "1", Content: "hello"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := mem.Search(ctx, SearchQuery{Query: "hello", Limit: 1}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(embedder.queries) != 1 || embedder.queries[0] != "hello" {
+		t.Errorf("EmbedQuery calls = %v, want [hello]", embedder.queries)
+	}
+}
+
+func TestMemoryVectorStore_DotDistance(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryVectorStore(2, WithStoreDistance(DistanceDot))
+	_ = store.Add(ctx, "short", []float32{1, 0}, nil)
+	_ = store.Add(ctx, "long", []float32{3, 3}, nil)
+
+	results, err := store.Search(ctx, []float32{1, 0.2}, 1)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "long" {
+		t.Errorf("dot top result = %+v, want long", results)
+	}
+}
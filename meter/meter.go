@@ -19,6 +19,12 @@ type Record struct {
 	InputTokens int `json:"input_tokens"`
 	// OutputTokens 输出/生成内容消耗的 Token 数
 	OutputTokens int `json:"output_tokens"`
+	// CachedTokens 命中提示词缓存的输入 Token 数（包含在 InputTokens 中）
+	CachedTokens int `json:"cached_tokens,omitempty"`
+	// CacheWriteTokens 写入提示词缓存的输入 Token 数（包含在 InputTokens 中）
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+	// ReasoningTokens 推理/思考 Token 数（包含在 OutputTokens 中）
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
 	// Timestamp 请求的时间戳
 	Timestamp time.Time `json:"timestamp"`
 	// Latency 请求延迟/响应时间
@@ -44,6 +50,10 @@ type Stats struct {
 	OutputTokens int64 `json:"output_tokens"`
 	// TotalTokens 总 Token 数（输入+输出）
 	TotalTokens int64 `json:"total_tokens"`
+	// CachedTokens 总缓存命中输入 Token 数
+	CachedTokens int64 `json:"cached_tokens,omitempty"`
+	// ReasoningTokens 总推理 Token 数
+	ReasoningTokens int64 `json:"reasoning_tokens,omitempty"`
 	// EstimatedCost 预估总成本（美元）
 	EstimatedCost float64 `json:"estimated_cost"`
 	// AvgLatency 平均延迟
@@ -68,6 +78,60 @@ type Pricing struct {
 	InputPrice float64
 	// OutputPrice 输出 Token 价格（美元/百万 Token）
 	OutputPrice float64
+	// CachedInputPrice 缓存命中输入 Token 价格（美元/百万 Token）
+	// 为 0 时按 InputPrice 计费
+	CachedInputPrice float64
+	// CacheWritePrice 缓存写入 Token 价格（美元/百万 Token）
+	// 为 0 时按 InputPrice 计费
+	CacheWritePrice float64
+	// ReasoningPrice 推理 Token 价格（美元/百万 Token）
+	// 为 0 时按 OutputPrice 计费
+	ReasoningPrice float64
+}
+
+// Cost 计算一条记录的成本（美元）
+//
+// 缓存命中、缓存写入和推理 Token 分别按各自价格计费，
+// 其余输入/输出 Token 按 InputPrice/OutputPrice 计费。
+func (p Pricing) Cost(r Record) float64 {
+	cachedPrice := p.CachedInputPrice
+	if cachedPrice == 0 {
+		cachedPrice = p.InputPrice
+	}
+	writePrice := p.CacheWritePrice
+	if writePrice == 0 {
+		writePrice = p.InputPrice
+	}
+	reasoningPrice := p.ReasoningPrice
+	if reasoningPrice == 0 {
+		reasoningPrice = p.OutputPrice
+	}
+
+	plainInput := max(r.InputTokens-r.CachedTokens-r.CacheWriteTokens, 0)
+	plainOutput := max(r.OutputTokens-r.ReasoningTokens, 0)
+
+	return (float64(plainInput)*p.InputPrice +
+		float64(r.CachedTokens)*cachedPrice +
+		float64(r.CacheWriteTokens)*writePrice +
+		float64(plainOutput)*p.OutputPrice +
+		float64(r.ReasoningTokens)*reasoningPrice) / 1_000_000
+}
+
+// Usage 描述单次请求的 Token 明细
+//
+// 字段口径与 streamx.Usage 一致：CachedTokens/CacheWriteTokens 包含在 InputTokens 中，
+// ReasoningTokens 包含在 OutputTokens 中。
+type Usage struct {
+	// InputTokens 输入 Token 总数
+	InputTokens int
+	// OutputTokens 输出 Token 总数
+	OutputTokens int
+	// CachedTokens 缓存命中的输入 Token 数
+	CachedTokens int
+	// CacheWriteTokens 缓存写入的输入 Token 数
+	CacheWriteTokens int
+	// ReasoningTokens 推理 Token 数
+	ReasoningTokens int
 }
 
 // defaultPricing 是预定义的主流模型定价表
@@ -76,14 +140,14 @@ type Pricing struct {
 var defaultPricing = map[string]Pricing{
 	"gpt-4":           {InputPrice: 30.0, OutputPrice: 60.0},
 	"gpt-4-turbo":     {InputPrice: 10.0, OutputPrice: 30.0},
-	"gpt-4o":          {InputPrice: 2.5, OutputPrice: 10.0},
-	"gpt-4o-mini":     {InputPrice: 0.15, OutputPrice: 0.6},
+	"gpt-4o":          {InputPrice: 2.5, OutputPrice: 10.0, CachedInputPrice: 1.25},
+	"gpt-4o-mini":     {InputPrice: 0.15, OutputPrice: 0.6, CachedInputPrice: 0.075},
 	"gpt-3.5-turbo":   {InputPrice: 0.5, OutputPrice: 1.5},
-	"claude-3-opus":   {InputPrice: 15.0, OutputPrice: 75.0},
-	"claude-3-sonnet": {InputPrice: 3.0, OutputPrice: 15.0},
-	"claude-3-haiku":  {InputPrice: 0.25, OutputPrice: 1.25},
+	"claude-3-opus":   {InputPrice: 15.0, OutputPrice: 75.0, CachedInputPrice: 1.5, CacheWritePrice: 18.75},
+	"claude-3-sonnet": {InputPrice: 3.0, OutputPrice: 15.0, CachedInputPrice: 0.3, CacheWritePrice: 3.75},
+	"claude-3-haiku":  {InputPrice: 0.25, OutputPrice: 1.25, CachedInputPrice: 0.03, CacheWritePrice: 0.3},
 	"gemini-pro":      {InputPrice: 0.5, OutputPrice: 1.5},
-	"deepseek":        {InputPrice: 0.14, OutputPrice: 0.28},
+	"deepseek":        {InputPrice: 0.14, OutputPrice: 0.28, CachedInputPrice: 0.014},
 }
 
 // DefaultMaxRecords 默认最大记录数
@@ -114,6 +178,8 @@ type Meter struct {
 	successRequests atomic.Int64 // 成功请求数
 	inputTokens     atomic.Int64 // 总输入 Token
 	outputTokens    atomic.Int64 // 总输出 Token
+	cachedTokens    atomic.Int64 // 总缓存命中 Token
+	reasoningTokens atomic.Int64 // 总推理 Token
 	totalLatency    atomic.Int64 // 总延迟（纳秒）
 	latencyCount    atomic.Int64 // 有延迟记录的请求数（用于计算平均值）
 	totalCost       atomic.Int64 // 累计成本（微美元，即 cost * 1_000_000）
@@ -192,7 +258,7 @@ func (m *Meter) RecordError(model string, inputTokens int, err error) {
 //
 // 线程安全
 func (m *Meter) RecordWithDetails(model string, inputTokens, outputTokens int, latency time.Duration, success bool, errStr string) {
-	record := Record{
+	m.addRecord(Record{
 		Model:        model,
 		InputTokens:  inputTokens,
 		OutputTokens: outputTokens,
@@ -200,8 +266,31 @@ func (m *Meter) RecordWithDetails(model string, inputTokens, outputTokens int, l
 		Latency:      latency,
 		Success:      success,
 		Error:        errStr,
-	}
+	})
+}
 
+// RecordUsage 记录一次带 Token 明细的成功请求
+//
+// 缓存命中和推理 Token 会按 Pricing 中的独立价格计费，
+// 适用于 OpenAI 缓存、DeepSeek KV 缓存、o 系列推理模型等场景。
+//
+// 线程安全
+func (m *Meter) RecordUsage(model string, usage Usage, latency time.Duration) {
+	m.addRecord(Record{
+		Model:            model,
+		InputTokens:      usage.InputTokens,
+		OutputTokens:     usage.OutputTokens,
+		CachedTokens:     usage.CachedTokens,
+		CacheWriteTokens: usage.CacheWriteTokens,
+		ReasoningTokens:  usage.ReasoningTokens,
+		Timestamp:        time.Now(),
+		Latency:          latency,
+		Success:          true,
+	})
+}
+
+// addRecord 追加记录并更新累计计数器
+func (m *Meter) addRecord(record Record) {
 	m.mu.Lock()
 	m.records = append(m.records, record)
 	// 自动清理：当超过限制时，删除最旧的 10% 记录
@@ -217,22 +306,22 @@ func (m *Meter) RecordWithDetails(model string, inputTokens, outputTokens int, l
 
 	// 更新计数器（累计统计，不受清理影响）
 	m.totalRequests.Add(1)
-	if success {
+	if record.Success {
 		m.successRequests.Add(1)
 	}
-	m.inputTokens.Add(int64(inputTokens))
-	m.outputTokens.Add(int64(outputTokens))
-	if latency > 0 {
-		m.totalLatency.Add(int64(latency))
+	m.inputTokens.Add(int64(record.InputTokens))
+	m.outputTokens.Add(int64(record.OutputTokens))
+	m.cachedTokens.Add(int64(record.CachedTokens))
+	m.reasoningTokens.Add(int64(record.ReasoningTokens))
+	if record.Latency > 0 {
+		m.totalLatency.Add(int64(record.Latency))
 		m.latencyCount.Add(1)
 	}
 
 	// 累计成本（存为微美元 int64，与 records 清理无关）
 	m.mu.RLock()
-	if pricing, ok := m.pricing[model]; ok {
-		costMicro := int64(float64(inputTokens)/1_000_000*pricing.InputPrice*1_000_000) +
-			int64(float64(outputTokens)/1_000_000*pricing.OutputPrice*1_000_000)
-		m.totalCost.Add(costMicro)
+	if pricing, ok := m.pricing[record.Model]; ok {
+		m.totalCost.Add(int64(pricing.Cost(record) * 1_000_000))
 	}
 	m.mu.RUnlock()
 }
@@ -256,6 +345,8 @@ func (m *Meter) Stats() Stats {
 		InputTokens:     m.inputTokens.Load(),
 		OutputTokens:    m.outputTokens.Load(),
 		TotalTokens:     m.inputTokens.Load() + m.outputTokens.Load(),
+		CachedTokens:    m.cachedTokens.Load(),
+		ReasoningTokens: m.reasoningTokens.Load(),
 	}
 
 	// Use cumulative atomic counter for cost (consistent with TotalRequests
@@ -311,6 +402,8 @@ func (m *Meter) statsByModelLocked(model string) Stats {
 		}
 		stats.InputTokens += int64(r.InputTokens)
 		stats.OutputTokens += int64(r.OutputTokens)
+		stats.CachedTokens += int64(r.CachedTokens)
+		stats.ReasoningTokens += int64(r.ReasoningTokens)
 
 		if r.Latency > 0 {
 			totalLatency += r.Latency
@@ -326,10 +419,13 @@ func (m *Meter) statsByModelLocked(model string) Stats {
 
 	stats.TotalTokens = stats.InputTokens + stats.OutputTokens
 
-	// 计算成本
+	// 计算成本（逐条计算，缓存/推理 Token 按独立价格计费）
 	if pricing, ok := m.pricing[model]; ok {
-		stats.EstimatedCost = float64(stats.InputTokens)/1_000_000*pricing.InputPrice +
-			float64(stats.OutputTokens)/1_000_000*pricing.OutputPrice
+		for _, r := range m.records {
+			if r.Model == model {
+				stats.EstimatedCost += pricing.Cost(r)
+			}
+		}
 	}
 
 	// 计算平均延迟
@@ -407,6 +503,8 @@ func (m *Meter) Clear() {
 	m.successRequests.Store(0)
 	m.inputTokens.Store(0)
 	m.outputTokens.Store(0)
+	m.cachedTokens.Store(0)
+	m.reasoningTokens.Store(0)
 	m.totalLatency.Store(0)
 	m.latencyCount.Store(0)
 	m.totalCost.Store(0)
@@ -434,6 +532,12 @@ func (m *Meter) Report() string {
 	fmt.Fprintf(&sb, "  Input Tokens:     %d\n", stats.InputTokens)
 	fmt.Fprintf(&sb, "  Output Tokens:    %d\n", stats.OutputTokens)
 	fmt.Fprintf(&sb, "  Total Tokens:     %d\n", stats.TotalTokens)
+	if stats.CachedTokens > 0 {
+		fmt.Fprintf(&sb, "  Cached Tokens:    %d\n", stats.CachedTokens)
+	}
+	if stats.ReasoningTokens > 0 {
+		fmt.Fprintf(&sb, "  Reasoning Tokens: %d\n", stats.ReasoningTokens)
+	}
 	fmt.Fprintf(&sb, "  Estimated Cost:   $%.4f\n", stats.EstimatedCost)
 	if stats.AvgLatency > 0 {
 		fmt.Fprintf(&sb, "  Avg Latency:      %v\n", stats.AvgLatency)
@@ -509,6 +613,8 @@ func (m *Meter) StatsInWindow(window time.Duration) WindowStats {
 		}
 		stats.InputTokens += int64(r.InputTokens)
 		stats.OutputTokens += int64(r.OutputTokens)
+		stats.CachedTokens += int64(r.CachedTokens)
+		stats.ReasoningTokens += int64(r.ReasoningTokens)
 
 		if r.Latency > 0 {
 			totalLatency += r.Latency
@@ -528,8 +634,7 @@ func (m *Meter) StatsInWindow(window time.Duration) WindowStats {
 	m.mu.RLock()
 	for _, r := range records {
 		if pricing, ok := m.pricing[r.Model]; ok {
-			stats.EstimatedCost += float64(r.InputTokens)/1_000_000*pricing.InputPrice +
-				float64(r.OutputTokens)/1_000_000*pricing.OutputPrice
+			stats.EstimatedCost += pricing.Cost(r)
 		}
 	}
 	m.mu.RUnlock()
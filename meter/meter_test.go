@@ -413,3 +413,39 @@ func TestMeter_NewWithOptions(t *testing.T) {
 		t.Errorf("expected cost $%.4f, got $%.4f", expectedCost, stats.EstimatedCost)
 	}
 }
+
+func TestMeter_RecordUsage_CachedAndReasoningPricing(t *testing.T) {
+	m := NewWithPricing(map[string]Pricing{
+		"o-test": {InputPrice: 2.0, OutputPrice: 8.0, CachedInputPrice: 0.5, ReasoningPrice: 10.0},
+	})
+
+	m.RecordUsage("o-test", Usage{
+		InputTokens:     1_000_000,
+		OutputTokens:    1_000_000,
+		CachedTokens:    400_000,
+		ReasoningTokens: 250_000,
+	}, 0)
+
+	// 600k*2 + 400k*0.5 + 750k*8 + 250k*10 = 1.2 + 0.2 + 6 + 2.5 = 9.9
+	want := 9.9
+	stats := m.Stats()
+	if diff := stats.EstimatedCost - want; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("EstimatedCost = %v, want %v", stats.EstimatedCost, want)
+	}
+	if stats.CachedTokens != 400_000 || stats.ReasoningTokens != 250_000 {
+		t.Errorf("unexpected breakdown: cached=%d reasoning=%d", stats.CachedTokens, stats.ReasoningTokens)
+	}
+
+	byModel := m.StatsByModel("o-test")
+	if diff := byModel.EstimatedCost - want; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("StatsByModel EstimatedCost = %v, want %v", byModel.EstimatedCost, want)
+	}
+}
+
+func TestPricing_CostFallsBackToBasePrices(t *testing.T) {
+	p := Pricing{InputPrice: 1.0, OutputPrice: 2.0}
+	cost := p.Cost(Record{InputTokens: 1_000_000, OutputTokens: 1_000_000, CachedTokens: 500_000, ReasoningTokens: 500_000})
+	if diff := cost - 3.0; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("Cost = %v, want 3.0", cost)
+	}
+}
@@ -0,0 +1,285 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GBNF 基础规则（与 llama.cpp json-schema-to-grammar 的写法一致）
+var gbnfPrimitives = map[string]string{
+	"ws":            `[ \t\n]{0,20}`,
+	"char":          `[^"\\\x7F\x00-\x1F] | [\\] (["\\/bfnrt] | "u" [0-9a-fA-F]{4})`,
+	"string":        `"\"" char* "\"" ws`,
+	"integral-part": `[0] | [1-9] [0-9]{0,15}`,
+	"integer":       `"-"? integral-part ws`,
+	"number":        `"-"? integral-part ("." [0-9]+)? ([eE] [-+]? [0-9]+)? ws`,
+	"boolean":       `("true" | "false") ws`,
+	"null":          `"null" ws`,
+	"value":         `object | array | string | number | boolean | null`,
+	"object":        `"{" ws (string ":" ws value ("," ws string ":" ws value)*)? "}" ws`,
+	"array":         `"[" ws (value ("," ws value)*)? "]" ws`,
+}
+
+// gbnfDeps 基础规则的依赖
+var gbnfDeps = map[string][]string{
+	"string":  {"char", "ws"},
+	"integer": {"integral-part", "ws"},
+	"number":  {"integral-part", "ws"},
+	"boolean": {"ws"},
+	"null":    {"ws"},
+	"value":   {"object", "array", "string", "number", "boolean", "null"},
+	"object":  {"string", "value", "ws"},
+	"array":   {"value", "ws"},
+}
+
+// ToGBNF 将 Schema 转换为 GBNF 语法，用于 llama.cpp 等支持语法约束解码的推理服务
+//
+// 支持的约束：type、properties/required（属性按 required 顺序、其余按名称排序输出）、
+// items、enum、minLength/maxLength。pattern、format、minimum/maximum 不会体现在语法中；
+// 未声明 properties 的 object 与未声明 type 的 Schema 接受任意 JSON 值。
+func ToGBNF(s *Schema) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("schema: nil schema")
+	}
+	g := &gbnfBuilder{rules: make(map[string]string), used: map[string]bool{"root": true}}
+	for name := range gbnfPrimitives {
+		g.used[name] = true
+	}
+	body, err := g.visit(s, "root")
+	if err != nil {
+		return "", err
+	}
+	g.rules["root"] = body
+	return g.String(), nil
+}
+
+// ChoiceGBNF 生成只允许输出给定文本之一的 GBNF 语法
+func ChoiceGBNF(choices []string) string {
+	alts := make([]string, len(choices))
+	for i, c := range choices {
+		alts[i] = gbnfLiteral(c)
+	}
+	return "root ::= " + strings.Join(alts, " | ") + "\n"
+}
+
+type gbnfBuilder struct {
+	rules map[string]string
+	// used 已分配的规则名（含 root 与基础规则），派生规则名冲突时追加序号
+	used map[string]bool
+}
+
+// String 输出语法：root 在前，其余规则按名称排序
+func (g *gbnfBuilder) String() string {
+	names := make([]string, 0, len(g.rules))
+	for name := range g.rules {
+		if name != "root" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "root ::= %s\n", g.rules["root"])
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s ::= %s\n", name, g.rules[name])
+	}
+	return b.String()
+}
+
+// primitive 引用基础规则（连同其依赖一并加入）
+func (g *gbnfBuilder) primitive(name string) string {
+	if _, ok := g.rules[name]; !ok {
+		g.rules[name] = gbnfPrimitives[name]
+		for _, dep := range gbnfDeps[name] {
+			g.primitive(dep)
+		}
+	}
+	return name
+}
+
+// unique 返回未被占用的规则名并登记：name 已占用时依次尝试 name-2、name-3……
+func (g *gbnfBuilder) unique(name string) string {
+	candidate := name
+	for i := 2; g.used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	g.used[candidate] = true
+	return candidate
+}
+
+// add 添加命名规则，返回规则名
+func (g *gbnfBuilder) add(name, body string) string {
+	g.rules[name] = body
+	return name
+}
+
+// visit 返回匹配 s 的规则体，name 为该位置的规则名（用于派生子规则名）
+func (g *gbnfBuilder) visit(s *Schema, name string) (string, error) {
+	if len(s.Enum) > 0 {
+		alts := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("schema: enum value %v: %w", v, err)
+			}
+			alts[i] = gbnfLiteral(string(data))
+		}
+		return "(" + strings.Join(alts, " | ") + ") " + g.primitive("ws"), nil
+	}
+
+	switch s.Type {
+	case "string":
+		if s.MinLength == nil && s.MaxLength == nil {
+			return g.primitive("string"), nil
+		}
+		g.primitive("string")
+		return `"\"" char` + repetition(s.MinLength, s.MaxLength) + ` "\"" ws`, nil
+	case "integer", "number", "boolean", "null":
+		return g.primitive(s.Type), nil
+	case "array":
+		if s.Items == nil {
+			return g.primitive("array"), nil
+		}
+		itemName := g.unique(name + "-item")
+		item, err := g.visit(s.Items, itemName)
+		if err != nil {
+			return "", err
+		}
+		item = g.add(itemName, item)
+		return `"[" ws (` + item + ` ("," ws ` + item + `)*)? "]" ` + g.primitive("ws"), nil
+	case "object":
+		if len(s.Properties) == 0 {
+			return g.primitive("object"), nil
+		}
+		return g.visitObject(s, name)
+	case "":
+		return g.primitive("value"), nil
+	default:
+		return "", fmt.Errorf("schema: unsupported type %q", s.Type)
+	}
+}
+
+// visitObject 生成 object 规则：必填属性依次出现，可选属性按顺序可省略
+func (g *gbnfBuilder) visitObject(s *Schema, name string) (string, error) {
+	required := make(map[string]bool, len(s.Required))
+	var order []string
+	for _, r := range s.Required {
+		if _, ok := s.Properties[r]; ok && !required[r] {
+			required[r] = true
+			order = append(order, r)
+		}
+	}
+	var optional []string
+	for prop := range s.Properties {
+		if !required[prop] {
+			optional = append(optional, prop)
+		}
+	}
+	sort.Strings(optional)
+
+	// kv 生成 "key": value 规则
+	kv := func(prop string) (string, error) {
+		ruleName := g.unique(name + "-" + gbnfName(prop))
+		kvName := g.unique(ruleName + "-kv")
+		value, err := g.visit(s.Properties[prop], ruleName)
+		if err != nil {
+			return "", err
+		}
+		value = g.add(ruleName, value)
+		data, _ := json.Marshal(prop)
+		return g.add(kvName, gbnfLiteral(string(data))+` ws ":" ws `+value), nil
+	}
+
+	reqRules := make([]string, len(order))
+	for i, prop := range order {
+		rule, err := kv(prop)
+		if err != nil {
+			return "", err
+		}
+		reqRules[i] = rule
+	}
+	optRules := make([]string, len(optional))
+	for i, prop := range optional {
+		rule, err := kv(prop)
+		if err != nil {
+			return "", err
+		}
+		optRules[i] = rule
+	}
+
+	g.primitive("ws")
+	var body strings.Builder
+	body.WriteString(`"{" ws `)
+	if len(reqRules) > 0 {
+		body.WriteString(strings.Join(reqRules, ` "," ws `))
+		for _, rule := range optRules {
+			body.WriteString(` ("," ws ` + rule + `)?`)
+		}
+	} else {
+		// 无必填属性：以第 i 个可选属性开头，其后的可选属性依次可省略
+		alts := make([]string, len(optRules))
+		for i, rule := range optRules {
+			alt := rule
+			for _, rest := range optRules[i+1:] {
+				alt += ` ("," ws ` + rest + `)?`
+			}
+			alts[i] = alt
+		}
+		body.WriteString("(" + strings.Join(alts, " | ") + ")?")
+	}
+	body.WriteString(` "}" ws`)
+	return body.String(), nil
+}
+
+// repetition 生成 GBNF 重复次数 {m,n}
+func repetition(minLen, maxLen *int) string {
+	lo := 0
+	if minLen != nil {
+		lo = *minLen
+	}
+	if maxLen == nil {
+		return fmt.Sprintf("{%d,}", lo)
+	}
+	return fmt.Sprintf("{%d,%d}", lo, *maxLen)
+}
+
+// gbnfLiteral 将文本转为 GBNF 字符串字面量
+func gbnfLiteral(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// gbnfName 将属性名转换为合法的规则名（仅字母、数字和连字符）
+// 其他字符编码为 -x<十六进制码点>（如 "a_b" → "a-x5fb"、"名称" → "-x540d-x79f0"），
+// 编码后仍可能重名的情况由 gbnfBuilder.unique 处理
+func gbnfName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, "-x%x", r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToGBNF_OptionalProperties(t *testing.T) {
+	s := NewBuilder().
+		Type("object").
+		Property("x", Integer(""), false).
+		Property("y", Boolean(""), false).
+		Build()
+
+	got, err := ToGBNF(s)
+	if err != nil {
+		t.Fatalf("ToGBNF error: %v", err)
+	}
+	want := `root ::= "{" ws (root-x-kv ("," ws root-y-kv)? | root-y-kv)? "}" ws
+boolean ::= ("true" | "false") ws
+integer ::= "-"? integral-part ws
+integral-part ::= [0] | [1-9] [0-9]{0,15}
+root-x ::= integer
+root-x-kv ::= "\"x\"" ws ":" ws root-x
+root-y ::= boolean
+root-y-kv ::= "\"y\"" ws ":" ws root-y
+ws ::= [ \t\n]{0,20}
+`
+	if got != want {
+		t.Errorf("grammar =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestToGBNF_Constraints(t *testing.T) {
+	maxLen := 5
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MaxLength: &maxLen},
+			"kind": {Type: "string", Enum: []any{"a", "b"}},
+			"tags": {Type: "array", Items: &Schema{Type: "string"}},
+			"meta": {Type: "object"},
+		},
+		Required: []string{"name"},
+	}
+	got, err := ToGBNF(s)
+	if err != nil {
+		t.Fatalf("ToGBNF error: %v", err)
+	}
+	for _, rule := range []string{
+		`root ::= "{" ws root-name-kv ("," ws root-kind-kv)? ("," ws root-meta-kv)? ("," ws root-tags-kv)? "}" ws`,
+		`root-name ::= "\"" char{0,5} "\"" ws`,
+		`root-kind ::= ("\"a\"" | "\"b\"") ws`,
+		`root-tags ::= "[" ws (root-tags-item ("," ws root-tags-item)*)? "]" ws`,
+		`root-meta ::= object`,
+		`value ::= object | array | string | number | boolean | null`,
+	} {
+		if !strings.Contains(got, rule+"\n") {
+			t.Errorf("missing rule %q in\n%s", rule, got)
+		}
+	}
+
+	if _, err := ToGBNF(&Schema{Type: "tuple"}); err == nil {
+		t.Error("unsupported type should fail")
+	}
+}
+
+func TestToGBNF_RuleNameCollisions(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"名称":  {Type: "string"},
+			"年龄":  {Type: "integer"},
+			"a_b": {Type: "string"},
+			"a.b": {Type: "integer"},
+			"x": {Type: "object", Properties: map[string]*Schema{
+				"kv": {Type: "boolean"},
+			}, Required: []string{"kv"}},
+		},
+		Required: []string{"名称", "年龄", "a_b", "a.b", "x"},
+	}
+	got, err := ToGBNF(s)
+	if err != nil {
+		t.Fatalf("ToGBNF error: %v", err)
+	}
+	for _, rule := range []string{
+		`root ::= "{" ws root--x540d-x79f0-kv "," ws root--x5e74-x9f84-kv "," ws root-a-x5fb-kv "," ws root-a-x2eb-kv "," ws root-x-kv "}" ws`,
+		`root--x540d-x79f0-kv ::= "\"名称\"" ws ":" ws root--x540d-x79f0`,
+		`root--x5e74-x9f84-kv ::= "\"年龄\"" ws ":" ws root--x5e74-x9f84`,
+		`root-a-x5fb ::= string`,
+		`root-a-x2eb ::= integer`,
+		`root-x ::= "{" ws root-x-kv-2-kv "}" ws`,
+		`root-x-kv ::= "\"x\"" ws ":" ws root-x`,
+		`root-x-kv-2 ::= boolean`,
+		`root-x-kv-2-kv ::= "\"kv\"" ws ":" ws root-x-kv-2`,
+	} {
+		if !strings.Contains(got, rule+"\n") {
+			t.Errorf("missing rule %q in\n%s", rule, got)
+		}
+	}
+
+	defined := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+		name, _, _ := strings.Cut(line, " ::= ")
+		if defined[name] {
+			t.Errorf("rule %q defined twice", name)
+		}
+		defined[name] = true
+	}
+}
+
+func TestChoiceGBNF(t *testing.T) {
+	got := ChoiceGBNF([]string{"yes", `say "no"`})
+	if want := `root ::= "yes" | "say \"no\""` + "\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,13 @@
 package vector
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
 
 // Embedder 向量生成器接口
 //
@@ -33,6 +40,26 @@ type Embedder interface {
 	Dimension() int
 }
 
+// QueryEmbedder 区分检索查询与文档编码的 Embedder（可选能力）
+//
+// 查询与文档使用不同编码的嵌入器（如 Qwen、Gemini 的查询类型，BM25 的 IDF 查询向量）实现此接口，
+// 检索时应优先使用 EmbedQuery 生成查询向量，未实现时退回 EmbedOne。
+type QueryEmbedder interface {
+	Embedder
+
+	// EmbedQuery 将检索查询转换为向量
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbedQuery 生成检索查询向量
+// embedder 实现 QueryEmbedder 时使用 EmbedQuery，否则使用 EmbedOne
+func EmbedQuery(ctx context.Context, embedder Embedder, text string) ([]float32, error) {
+	if qe, ok := embedder.(QueryEmbedder); ok {
+		return qe.EmbedQuery(ctx, text)
+	}
+	return embedder.EmbedOne(ctx, text)
+}
+
 // EmbedderFunc 函数式 Embedder
 //
 // EmbedderFunc 允许使用函数来创建 Embedder，
@@ -79,4 +106,118 @@ func (e *EmbedderFunc) Dimension() int {
 	return e.dimension
 }
 
-var _ Embedder = (*EmbedderFunc)(nil)
+// ProviderEmbedder 基于 llm.EmbeddingProvider 的 Embedder
+//
+// 以一个 llm.EmbedRequest 作为请求模板（模型、维度、编码、归一化等），每次调用只替换 Input。
+// 文档入库使用模板中的 InputType，EmbedQuery 以 EmbedInputQuery 生成查询向量。
+// Provider 未实现 llm.EmbedRequestProvider 时退回 EmbedWithModel，模板中仅 Model、BatchSize 与 Normalize 生效：
+//
+//	embedder := vector.NewProviderEmbedder(provider, llm.EmbedRequest{
+//	    Model:      "text-embedding-3-small",
+//	    Dimensions: 512,
+//	    InputType:  llm.EmbedInputDocument,
+//	    Normalize:  true,
+//	})
+type ProviderEmbedder struct {
+	provider llm.EmbeddingProvider
+	template llm.EmbedRequest
+
+	// dimension 向量维度：模板未指定 Dimensions 时由首次调用结果确定
+	dimension atomic.Int64
+
+	// usage 累计 Token 用量
+	mu    sync.Mutex
+	usage llm.Usage
+}
+
+// NewProviderEmbedder 创建基于 Provider 的 Embedder
+//
+// 参数:
+//   - provider: 支持向量嵌入的 Provider
+//   - template: 请求模板，Input 字段被忽略
+//
+// 返回:
+//   - *ProviderEmbedder: Embedder 实例
+func NewProviderEmbedder(provider llm.EmbeddingProvider, template llm.EmbedRequest) *ProviderEmbedder {
+	e := &ProviderEmbedder{provider: provider, template: template}
+	e.template.Input = nil
+	e.dimension.Store(int64(template.Dimensions))
+	return e
+}
+
+// Embed 将多个文本转换为向量
+func (e *ProviderEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return e.embed(ctx, texts, e.template.InputType)
+}
+
+// EmbedOne 将单个文本转换为向量
+func (e *ProviderEmbedder) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.embed(ctx, []string{text}, e.template.InputType)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedQuery 以检索查询类型生成向量
+// 区分查询与文档的模型（Qwen、Gemini）在检索时应使用此方法
+func (e *ProviderEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := e.embed(ctx, []string{text}, llm.EmbedInputQuery)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// Dimension 返回向量维度
+// 模板未指定 Dimensions 且尚未调用过时返回 0
+func (e *ProviderEmbedder) Dimension() int {
+	return int(e.dimension.Load())
+}
+
+// Usage 返回累计的 Token 用量
+func (e *ProviderEmbedder) Usage() llm.Usage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.usage
+}
+
+// embed 按模板发起请求并记录维度与用量
+func (e *ProviderEmbedder) embed(ctx context.Context, texts []string, inputType llm.EmbedInputType) ([][]float32, error) {
+	req := e.template
+	req.Input = texts
+	req.InputType = inputType
+
+	var resp *llm.EmbedResponse
+	var err error
+	if rp, ok := e.provider.(llm.EmbedRequestProvider); ok {
+		resp, err = rp.CreateEmbeddings(ctx, req)
+	} else {
+		resp, err = llm.EmbedBatches(ctx, req, 0, func(ctx context.Context, input []string) (*llm.EmbedResponse, error) {
+			embeddings, err := e.provider.EmbedWithModel(ctx, req.Model, input)
+			if err != nil {
+				return nil, err
+			}
+			return &llm.EmbedResponse{Model: req.Model, Embeddings: embeddings}, nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("vector: got %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	if len(resp.Embeddings) > 0 {
+		e.dimension.CompareAndSwap(0, int64(len(resp.Embeddings[0])))
+	}
+
+	e.mu.Lock()
+	e.usage.Add(resp.Usage)
+	e.mu.Unlock()
+	return resp.Embeddings, nil
+}
+
+var (
+	_ Embedder      = (*EmbedderFunc)(nil)
+	_ QueryEmbedder = (*ProviderEmbedder)(nil)
+)
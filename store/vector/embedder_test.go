@@ -0,0 +1,115 @@
+package vector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/llm"
+)
+
+// stubEmbeddingProvider 记录请求并返回固定维度向量
+type stubEmbeddingProvider struct {
+	llm.Provider
+	requests []llm.EmbedRequest
+}
+
+func (s *stubEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return s.EmbedWithModel(ctx, "", texts)
+}
+
+func (s *stubEmbeddingProvider) EmbedWithModel(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	resp, err := s.CreateEmbeddings(ctx, llm.EmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Embeddings, nil
+}
+
+func (s *stubEmbeddingProvider) CreateEmbeddings(_ context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	s.requests = append(s.requests, req)
+	resp := &llm.EmbedResponse{Usage: llm.Usage{PromptTokens: len(req.Input), TotalTokens: len(req.Input)}}
+	for range req.Input {
+		resp.Embeddings = append(resp.Embeddings, []float32{1, 0, 0})
+	}
+	return resp, nil
+}
+
+func TestProviderEmbedder(t *testing.T) {
+	stub := &stubEmbeddingProvider{}
+	e := NewProviderEmbedder(stub, llm.EmbedRequest{Model: "m", InputType: llm.EmbedInputDocument, Normalize: true})
+	ctx := context.Background()
+
+	if e.Dimension() != 0 {
+		t.Errorf("Dimension before first call = %d, want 0", e.Dimension())
+	}
+	if _, err := e.Embed(ctx, []string{"a", "b"}); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if _, err := e.EmbedQuery(ctx, "q"); err != nil {
+		t.Fatalf("EmbedQuery failed: %v", err)
+	}
+
+	if e.Dimension() != 3 {
+		t.Errorf("Dimension = %d, want 3", e.Dimension())
+	}
+	if got := e.Usage().TotalTokens; got != 3 {
+		t.Errorf("Usage.TotalTokens = %d, want 3", got)
+	}
+	if len(stub.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(stub.requests))
+	}
+	if r := stub.requests[0]; r.Model != "m" || r.InputType != llm.EmbedInputDocument || !r.Normalize {
+		t.Errorf("document request = %+v", r)
+	}
+	if r := stub.requests[1]; r.InputType != llm.EmbedInputQuery {
+		t.Errorf("query request InputType = %q, want query", r.InputType)
+	}
+}
+
+// plainEmbeddingProvider 仅实现 EmbedWithModel，不支持完整请求参数
+type plainEmbeddingProvider struct {
+	llm.Provider
+	models  []string
+	batches int
+}
+
+func (p *plainEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return p.EmbedWithModel(ctx, "", texts)
+}
+
+func (p *plainEmbeddingProvider) EmbedWithModel(_ context.Context, model string, texts []string) ([][]float32, error) {
+	p.models = append(p.models, model)
+	p.batches++
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{3, 4}
+	}
+	return out, nil
+}
+
+func TestProviderEmbedderFallback(t *testing.T) {
+	plain := &plainEmbeddingProvider{}
+	e := NewProviderEmbedder(plain, llm.EmbedRequest{Model: "m", Normalize: true, BatchSize: 2})
+
+	embeddings, err := e.Embed(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(embeddings) != 3 {
+		t.Fatalf("embeddings = %d, want 3", len(embeddings))
+	}
+	if v := embeddings[2]; v[0] != 0.6 || v[1] != 0.8 {
+		t.Errorf("normalized vector = %v, want [0.6 0.8]", v)
+	}
+	if plain.batches != 2 {
+		t.Errorf("batches = %d, want 2", plain.batches)
+	}
+	if plain.models[0] != "m" {
+		t.Errorf("model = %q, want m", plain.models[0])
+	}
+
+	q, err := EmbedQuery(context.Background(), e, "q")
+	if err != nil || len(q) != 2 {
+		t.Fatalf("EmbedQuery = %v, %v", q, err)
+	}
+}
@@ -0,0 +1,201 @@
+package local
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/store/vector"
+)
+
+// SparseVector 稀疏向量，Indices 升序且不重复
+type SparseVector struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// Dense 展开为指定维度的稠密向量
+func (s SparseVector) Dense(dimension int) []float32 {
+	vec := make([]float32, dimension)
+	for i, idx := range s.Indices {
+		vec[idx] = s.Values[i]
+	}
+	return vec
+}
+
+// BM25 稀疏编码器
+//
+// 文档向量的分量为 BM25 词频部分 tf·(k1+1)/(tf+k1·(1-b+b·dl/avgdl))，
+// 查询向量的分量为 IDF ln((N-df+0.5)/(df+0.5)+1)，二者点积即该文档的 BM25 分数。
+// 词项经哈希映射到 [0, dimension) 的下标，可直接写入支持稀疏向量的存储（EncodeDocument/EncodeQuery），
+// 也可展开为稠密向量作为 Embedder 使用：Embed/EmbedOne 按文档编码，查询须经 EmbedQuery（QueryEmbedder）编码，
+// 且存储应使用点积度量（vector.DistanceDot、memory.DistanceDot），余弦相似度会改变排序；
+// 点积分数未归一化，MinScore 阈值需按 BM25 分数设置。
+//
+// 未调用 Fit 时 IDF 恒为 1、avgdl 取文档自身长度（退化为饱和词频匹配）。
+type BM25 struct {
+	dimension int
+	k1        float64
+	b         float64
+
+	mu    sync.RWMutex
+	docs  int
+	avgdl float64
+	df    map[string]int
+}
+
+// BM25Option BM25 配置选项
+type BM25Option func(*BM25)
+
+// WithK1 设置词频饱和参数 k1（默认 1.2）
+func WithK1(k1 float64) BM25Option {
+	return func(m *BM25) {
+		m.k1 = k1
+	}
+}
+
+// WithB 设置文档长度归一化参数 b（默认 0.75）
+func WithB(b float64) BM25Option {
+	return func(m *BM25) {
+		m.b = b
+	}
+}
+
+// NewBM25 创建 BM25 稀疏编码器
+// dimension <= 0 时使用默认维度 1024
+func NewBM25(dimension int, opts ...BM25Option) *BM25 {
+	if dimension <= 0 {
+		dimension = defaultDimension
+	}
+	m := &BM25{dimension: dimension, k1: 1.2, b: 0.75}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Fit 从语料统计文档数、平均文档长度与文档频率，覆盖之前的拟合结果
+func (m *BM25) Fit(corpus []string) {
+	df := make(map[string]int)
+	var total int
+	for _, doc := range corpus {
+		tokens := terms(doc)
+		total += len(tokens)
+		_, keys := termCounts(tokens)
+		for _, term := range keys {
+			df[term]++
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs = len(corpus)
+	m.df = df
+	m.avgdl = 0
+	if len(corpus) > 0 {
+		m.avgdl = float64(total) / float64(len(corpus))
+	}
+}
+
+// EncodeDocument 编码被检索的文档
+func (m *BM25) EncodeDocument(text string) SparseVector {
+	tokens := terms(text)
+	counts, keys := termCounts(tokens)
+
+	m.mu.RLock()
+	avgdl := m.avgdl
+	m.mu.RUnlock()
+	dl := float64(len(tokens))
+	if avgdl == 0 {
+		avgdl = dl
+	}
+
+	weights := make(map[uint32]float64, len(keys))
+	for _, term := range keys {
+		tf := float64(counts[term])
+		norm := 1 - m.b
+		if avgdl > 0 {
+			norm += m.b * dl / avgdl
+		}
+		weights[m.index(term)] += tf * (m.k1 + 1) / (tf + m.k1*norm)
+	}
+	return toSparse(weights)
+}
+
+// EncodeQuery 编码检索查询，查询中重复的词项只计一次
+func (m *BM25) EncodeQuery(text string) SparseVector {
+	_, keys := termCounts(terms(text))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	weights := make(map[uint32]float64, len(keys))
+	for _, term := range keys {
+		weights[m.index(term)] += m.idf(term)
+	}
+	return toSparse(weights)
+}
+
+// Embed 将多个文本按文档编码并展开为稠密向量
+func (m *BM25) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = m.EncodeDocument(text).Dense(m.dimension)
+	}
+	return out, nil
+}
+
+// EmbedOne 将单个文本按文档编码并展开为稠密向量
+func (m *BM25) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.EncodeDocument(text).Dense(m.dimension), nil
+}
+
+// EmbedQuery 将文本按查询编码并展开为稠密向量，与 Embed 得到的文档向量点积即 BM25 分数
+func (m *BM25) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.EncodeQuery(text).Dense(m.dimension), nil
+}
+
+// Dimension 返回向量维度
+func (m *BM25) Dimension() int {
+	return m.dimension
+}
+
+// index 返回词项的哈希下标
+func (m *BM25) index(term string) uint32 {
+	return uint32(hashTerm(term) % uint64(m.dimension))
+}
+
+// idf 返回词项的 IDF（调用方持有读锁），未拟合时为 1
+func (m *BM25) idf(term string) float64 {
+	if m.docs == 0 {
+		return 1
+	}
+	n := float64(m.df[term])
+	return math.Log((float64(m.docs)-n+0.5)/(n+0.5) + 1)
+}
+
+// toSparse 将下标到权重的映射转换为按下标升序的稀疏向量
+func toSparse(weights map[uint32]float64) SparseVector {
+	indices := make([]uint32, 0, len(weights))
+	for idx := range weights {
+		indices = append(indices, idx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	values := make([]float32, len(indices))
+	for i, idx := range indices {
+		values[i] = float32(weights[idx])
+	}
+	return SparseVector{Indices: indices, Values: values}
+}
+
+var _ vector.QueryEmbedder = (*BM25)(nil)
@@ -0,0 +1,105 @@
+package local
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/store/vector"
+)
+
+// Hashing 特征哈希词袋嵌入器
+//
+// 将词项（及可选的词 n-gram）经 FNV-1a 哈希映射到固定维度，哈希的最高位决定符号以抵消碰撞偏差；
+// 词频取 1+ln(tf)，结果做 L2 归一化。无需训练，适合作为语义嵌入的离线替身。
+type Hashing struct {
+	dimension  int
+	wordNGrams int
+}
+
+// HashingOption Hashing 配置选项
+type HashingOption func(*Hashing)
+
+// WithWordNGrams 额外加入长度 2..n 的连续词 n-gram（默认 1，即仅单词与汉字二元组）
+func WithWordNGrams(n int) HashingOption {
+	return func(h *Hashing) {
+		h.wordNGrams = n
+	}
+}
+
+// NewHashing 创建特征哈希嵌入器
+// dimension <= 0 时使用默认维度 1024
+func NewHashing(dimension int, opts ...HashingOption) *Hashing {
+	if dimension <= 0 {
+		dimension = defaultDimension
+	}
+	h := &Hashing{dimension: dimension, wordNGrams: 1}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Embed 将多个文本转换为向量
+func (h *Hashing) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = h.vector(text)
+	}
+	return out, nil
+}
+
+// EmbedOne 将单个文本转换为向量
+func (h *Hashing) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return h.vector(text), nil
+}
+
+// Dimension 返回向量维度
+func (h *Hashing) Dimension() int {
+	return h.dimension
+}
+
+// vector 计算单个文本的哈希向量
+func (h *Hashing) vector(text string) []float32 {
+	counts, keys := termCounts(h.features(text))
+	vec := make([]float32, h.dimension)
+	for _, term := range keys {
+		hash := hashTerm(term)
+		weight := 1 + math.Log(float64(counts[term]))
+		if hash>>63 == 1 {
+			weight = -weight
+		}
+		vec[hash%uint64(h.dimension)] += float32(weight)
+	}
+	llm.NormalizeL2(vec)
+	return vec
+}
+
+// features 返回词项与词 n-gram 特征
+func (h *Hashing) features(text string) []string {
+	features := terms(text)
+	if h.wordNGrams < 2 {
+		return features
+	}
+	var words []string
+	for _, u := range splitUnits(text) {
+		if !u.cjk {
+			words = append(words, u.text)
+		}
+	}
+	for n := 2; n <= h.wordNGrams; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			features = append(features, strings.Join(words[i:i+n], " "))
+		}
+	}
+	return features
+}
+
+var _ vector.Embedder = (*Hashing)(nil)
@@ -0,0 +1,118 @@
+// Package local 提供无需调用外部 API 的纯 Go 向量嵌入器
+//
+// 适用于开发环境与 CI：输出完全确定（相同输入与配置得到逐位相同的向量），
+// 同时实现 store/vector.Embedder 与 memory.Embedder，可直接替换 LLM Embedding：
+//   - Hashing: 特征哈希词袋（无需训练，维度固定）
+//   - TFIDF: 基于语料拟合词表的 TF-IDF，词表可持久化
+//   - BM25: BM25 稀疏编码（文档与查询分别编码，点积即 BM25 分数）。查询向量只能由 EmbedQuery 生成，
+//     BM25 实现 vector.QueryEmbedder 与 memory.QueryEmbedder，经 vector.EmbedQuery 或 VectorMemory.Search
+//     检索时自动使用；直接对查询调用 Embed/EmbedOne 得到的是文档编码，不是 BM25 分数。
+//     存储须使用点积度量（vector.WithDistance(vector.DistanceDot)、memory.WithDistance(memory.DistanceDot)），
+//     默认的余弦相似度会改变排序
+//
+// 分词规则：文本转小写后，连续的字母/数字构成一个词；中日韩文字逐字切分，
+// 并额外生成相邻汉字的二元组（如 "向量检索" → 向、量、检、索、向量、量检、检索）。
+//
+// 使用示例:
+//
+//	mem := memory.NewVectorMemory(local.NewHashing(512), memory.WithDimension(512))
+//
+//	bm25 := local.NewBM25(1024)
+//	bm25.Fit(corpus)
+//	mem := memory.NewVectorMemory(bm25, memory.WithDistance(memory.DistanceDot), memory.WithMinScore(0.1))
+//
+//	tfidf := local.NewTFIDF()
+//	tfidf.Fit(corpus)
+//	err := tfidf.Save(file)
+package local
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// defaultDimension 哈希类嵌入器的默认维度
+const defaultDimension = 1024
+
+// unit 分词得到的基本单元
+type unit struct {
+	text string
+	cjk  bool // 中日韩单字
+	run  int  // 所在连续片段的序号，相邻且 run 相同的汉字生成二元组
+}
+
+// splitUnits 将文本切分为词与中日韩单字
+func splitUnits(text string) []unit {
+	var (
+		units []unit
+		word  strings.Builder
+		run   int
+	)
+	flush := func() {
+		if word.Len() > 0 {
+			units = append(units, unit{text: word.String(), run: run})
+			word.Reset()
+			run++
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flush()
+			units = append(units, unit{text: string(r), cjk: true, run: run})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+			run++
+		}
+	}
+	flush()
+	return units
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// terms 返回文本的检索词项：词、中日韩单字与相邻汉字二元组
+func terms(text string) []string {
+	units := splitUnits(text)
+	out := make([]string, 0, len(units)*2)
+	for i, u := range units {
+		out = append(out, u.text)
+		if u.cjk && i > 0 && units[i-1].cjk && units[i-1].run == u.run {
+			out = append(out, units[i-1].text+u.text)
+		}
+	}
+	return out
+}
+
+// Tokenize 返回文本的检索词项（所有嵌入器共用的分词结果），便于调试召回效果
+func Tokenize(text string) []string {
+	return terms(text)
+}
+
+// termCounts 统计词频，并返回按字典序排列的词项（保证累加顺序确定）
+func termCounts(tokens []string) (map[string]int, []string) {
+	counts := make(map[string]int, len(tokens))
+	for _, t := range tokens {
+		counts[t]++
+	}
+	keys := make([]string, 0, len(counts))
+	for t := range counts {
+		keys = append(keys, t)
+	}
+	sort.Strings(keys)
+	return counts, keys
+}
+
+// hashTerm 计算词项的 64 位 FNV-1a 哈希
+func hashTerm(term string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(term))
+	return h.Sum64()
+}
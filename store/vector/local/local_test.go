@@ -0,0 +1,190 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hexagon-codes/ai-core/memory"
+	"github.com/hexagon-codes/ai-core/store/vector"
+)
+
+var (
+	_ memory.Embedder      = (*Hashing)(nil)
+	_ memory.Embedder      = (*TFIDF)(nil)
+	_ memory.Embedder      = (*BM25)(nil)
+	_ memory.QueryEmbedder = (*BM25)(nil)
+)
+
+var corpus = []string{
+	"向量数据库支持相似度检索",
+	"今天天气很好，适合出门散步",
+	"Go 语言的并发模型基于 goroutine",
+	"BM25 is a ranking function used by search engines",
+}
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("向量检索 Go-1.22")
+	want := []string{"向", "量", "向量", "检", "量检", "索", "检索", "go", "1", "22"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestHashing(t *testing.T) {
+	ctx := context.Background()
+	h := NewHashing(256, WithWordNGrams(2))
+
+	vecs, err := h.Embed(ctx, corpus)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	again, _ := h.Embed(ctx, corpus)
+	if !reflect.DeepEqual(vecs, again) {
+		t.Error("Hashing output is not deterministic")
+	}
+	if len(vecs[0]) != h.Dimension() {
+		t.Errorf("len = %d, want %d", len(vecs[0]), h.Dimension())
+	}
+
+	query, _ := h.EmbedOne(ctx, "相似度检索")
+	if dot(query, vecs[0]) <= dot(query, vecs[1]) {
+		t.Error("relevant document should score higher than unrelated one")
+	}
+}
+
+func TestTFIDF_SaveLoad(t *testing.T) {
+	ctx := context.Background()
+	tfidf := NewTFIDF()
+	if _, err := tfidf.EmbedOne(ctx, "x"); !errors.Is(err, ErrNotFitted) {
+		t.Errorf("EmbedOne before Fit error = %v, want ErrNotFitted", err)
+	}
+
+	tfidf.Fit(corpus)
+	if tfidf.Dimension() == 0 {
+		t.Fatal("empty vocabulary after Fit")
+	}
+	vecs, err := tfidf.Embed(ctx, corpus)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tfidf.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadTFIDF(&buf)
+	if err != nil {
+		t.Fatalf("LoadTFIDF failed: %v", err)
+	}
+	reloaded, _ := loaded.Embed(ctx, corpus)
+	if !reflect.DeepEqual(vecs, reloaded) {
+		t.Error("vectors differ after Save/LoadTFIDF")
+	}
+
+	query, _ := loaded.EmbedOne(ctx, "goroutine 并发")
+	if dot(query, vecs[2]) <= dot(query, vecs[0]) {
+		t.Error("relevant document should score higher than unrelated one")
+	}
+}
+
+func TestTFIDF_MaxFeatures(t *testing.T) {
+	tfidf := NewTFIDF(WithMaxFeatures(5))
+	tfidf.Fit(corpus)
+	if tfidf.Dimension() != 5 {
+		t.Errorf("Dimension = %d, want 5", tfidf.Dimension())
+	}
+}
+
+func TestBM25(t *testing.T) {
+	ctx := context.Background()
+	bm25 := NewBM25(512)
+	bm25.Fit(corpus)
+
+	docs, err := bm25.Embed(ctx, corpus)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	query, _ := bm25.EmbedQuery(ctx, "search ranking")
+	if dot(query, docs[3]) <= 0 || dot(query, docs[0]) != 0 {
+		t.Errorf("unexpected BM25 scores: %v, %v", dot(query, docs[3]), dot(query, docs[0]))
+	}
+
+	viaHelper, err := vector.EmbedQuery(ctx, bm25, "search ranking")
+	if err != nil || !reflect.DeepEqual(viaHelper, query) {
+		t.Errorf("vector.EmbedQuery did not use BM25.EmbedQuery: %v", err)
+	}
+
+	sparse := bm25.EncodeDocument(corpus[3])
+	for i := 1; i < len(sparse.Indices); i++ {
+		if sparse.Indices[i] <= sparse.Indices[i-1] {
+			t.Fatalf("indices not strictly ascending: %v", sparse.Indices)
+		}
+	}
+	if !reflect.DeepEqual(sparse.Dense(bm25.Dimension()), docs[3]) {
+		t.Error("Dense(EncodeDocument) differs from Embed")
+	}
+}
+
+func TestBM25_VectorMemory(t *testing.T) {
+	ctx := context.Background()
+	bm25 := NewBM25(1024)
+	bm25.Fit(corpus)
+	mem := memory.NewVectorMemory(bm25,
+		memory.WithDimension(bm25.Dimension()),
+		memory.WithDistance(memory.DistanceDot),
+		memory.WithMinScore(0.1),
+	)
+
+	for _, text := range corpus {
+		if err := mem.Save(ctx, memory.Entry{Role: "user", Content: text}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	entries, err := mem.Search(ctx, memory.SearchQuery{Query: "search engines ranking", Limit: 3})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	// 无共同词项的文档 BM25 分数为 0，被 MinScore 过滤
+	if len(entries) != 1 || entries[0].Content != corpus[3] {
+		t.Fatalf("Search = %+v, want only the BM25 entry", entries)
+	}
+
+	// 分数为查询向量与文档向量的点积，即 BM25 分数
+	query, _ := bm25.EmbedQuery(ctx, "search engines ranking")
+	doc, _ := bm25.EmbedOne(ctx, corpus[3])
+	score, _ := entries[0].Metadata["_score"].(float32)
+	if want := dot(query, doc); math.Abs(float64(score)-want) > 1e-4 {
+		t.Errorf("_score = %v, want BM25 score %v", score, want)
+	}
+}
+
+func TestHashing_VectorStore(t *testing.T) {
+	ctx := context.Background()
+	var embedder vector.Embedder = NewHashing(128)
+	mem := memory.NewVectorMemory(embedder, memory.WithDimension(embedder.Dimension()), memory.WithMinScore(0))
+
+	for _, text := range corpus {
+		if err := mem.Save(ctx, memory.Entry{Role: "user", Content: text}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	entries, err := mem.Search(ctx, memory.SearchQuery{Query: "天气怎么样", Limit: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Content != corpus[1] {
+		t.Errorf("Search = %+v, want weather entry", entries)
+	}
+}
@@ -0,0 +1,200 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/hexagon-codes/ai-core/llm"
+	"github.com/hexagon-codes/ai-core/store/vector"
+)
+
+// ErrNotFitted 嵌入器尚未拟合语料
+var ErrNotFitted = errors.New("local: embedder is not fitted")
+
+// TFIDF 基于拟合词表的 TF-IDF 嵌入器
+//
+// Fit 从语料统计文档频率并建立词表，向量维度等于词表大小；
+// IDF 采用平滑公式 ln((1+N)/(1+df))+1，词频取 1+ln(tf)，结果做 L2 归一化。
+// 词表外的词项被忽略。拟合结果可通过 Save/LoadTFIDF 持久化，保证入库与查询使用同一词表。
+type TFIDF struct {
+	minDF       int
+	maxFeatures int
+
+	mu    sync.RWMutex
+	docs  int
+	index map[string]int
+	terms []string
+	idf   []float64
+}
+
+// TFIDFOption TFIDF 配置选项
+type TFIDFOption func(*TFIDF)
+
+// WithMinDF 忽略文档频率低于 n 的词项（默认 1）
+func WithMinDF(n int) TFIDFOption {
+	return func(t *TFIDF) {
+		t.minDF = n
+	}
+}
+
+// WithMaxFeatures 词表最多保留 n 个文档频率最高的词项（默认不限）
+func WithMaxFeatures(n int) TFIDFOption {
+	return func(t *TFIDF) {
+		t.maxFeatures = n
+	}
+}
+
+// NewTFIDF 创建 TF-IDF 嵌入器，使用前需调用 Fit 或通过 LoadTFIDF 加载词表
+func NewTFIDF(opts ...TFIDFOption) *TFIDF {
+	t := &TFIDF{minDF: 1}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Fit 从语料拟合词表与 IDF，覆盖之前的拟合结果
+// 词表按文档频率降序、同频按字典序编号，相同语料得到相同词表
+func (t *TFIDF) Fit(corpus []string) {
+	df := make(map[string]int)
+	for _, doc := range corpus {
+		_, keys := termCounts(terms(doc))
+		for _, term := range keys {
+			df[term]++
+		}
+	}
+
+	vocab := make([]string, 0, len(df))
+	for term, n := range df {
+		if n >= t.minDF {
+			vocab = append(vocab, term)
+		}
+	}
+	sort.Slice(vocab, func(i, j int) bool {
+		if df[vocab[i]] != df[vocab[j]] {
+			return df[vocab[i]] > df[vocab[j]]
+		}
+		return vocab[i] < vocab[j]
+	})
+	if t.maxFeatures > 0 && len(vocab) > t.maxFeatures {
+		vocab = vocab[:t.maxFeatures]
+	}
+
+	idf := make([]float64, len(vocab))
+	for i, term := range vocab {
+		idf[i] = math.Log(float64(1+len(corpus))/float64(1+df[term])) + 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.set(len(corpus), vocab, idf)
+}
+
+// set 设置拟合结果（调用方持有写锁）
+func (t *TFIDF) set(docs int, vocab []string, idf []float64) {
+	t.docs = docs
+	t.terms = vocab
+	t.idf = idf
+	t.index = make(map[string]int, len(vocab))
+	for i, term := range vocab {
+		t.index[term] = i
+	}
+}
+
+// Embed 将多个文本转换为向量，未拟合时返回 ErrNotFitted
+func (t *TFIDF) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.index == nil {
+		return nil, ErrNotFitted
+	}
+
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = t.vector(text)
+	}
+	return out, nil
+}
+
+// EmbedOne 将单个文本转换为向量，未拟合时返回 ErrNotFitted
+func (t *TFIDF) EmbedOne(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := t.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// Dimension 返回向量维度（词表大小），未拟合时为 0
+func (t *TFIDF) Dimension() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.terms)
+}
+
+// vector 计算单个文本的 TF-IDF 向量（调用方持有读锁）
+func (t *TFIDF) vector(text string) []float32 {
+	counts, keys := termCounts(terms(text))
+	vec := make([]float32, len(t.terms))
+	for _, term := range keys {
+		i, ok := t.index[term]
+		if !ok {
+			continue
+		}
+		vec[i] = float32((1 + math.Log(float64(counts[term]))) * t.idf[i])
+	}
+	llm.NormalizeL2(vec)
+	return vec
+}
+
+// tfidfState TF-IDF 持久化格式
+type tfidfState struct {
+	Version int       `json:"version"`
+	Docs    int       `json:"docs"`
+	Terms   []string  `json:"terms"`
+	IDF     []float64 `json:"idf"`
+}
+
+// Save 将拟合结果以 JSON 写入 w，未拟合时返回 ErrNotFitted
+func (t *TFIDF) Save(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.index == nil {
+		return ErrNotFitted
+	}
+	return json.NewEncoder(w).Encode(tfidfState{
+		Version: 1,
+		Docs:    t.docs,
+		Terms:   t.terms,
+		IDF:     t.idf,
+	})
+}
+
+// LoadTFIDF 从 Save 写出的 JSON 恢复 TF-IDF 嵌入器
+func LoadTFIDF(r io.Reader) (*TFIDF, error) {
+	var state tfidfState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("local: decode tfidf state: %w", err)
+	}
+	if state.Version != 1 {
+		return nil, fmt.Errorf("local: unsupported tfidf state version %d", state.Version)
+	}
+	if len(state.Terms) != len(state.IDF) {
+		return nil, fmt.Errorf("local: tfidf state has %d terms but %d idf values", len(state.Terms), len(state.IDF))
+	}
+
+	t := NewTFIDF()
+	t.set(state.Docs, state.Terms, state.IDF)
+	return t, nil
+}
+
+var _ vector.Embedder = (*TFIDF)(nil)
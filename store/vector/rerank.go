@@ -0,0 +1,118 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Reranker 重排序器接口
+//
+// Reranker 对向量检索召回的候选文档按与查询的相关性重新打分。
+// 典型实现为 Cross-Encoder 模型服务（如 llm.RerankProvider），可通过 RerankerFunc 适配。
+type Reranker interface {
+	// Rerank 对候选文档重排序
+	//
+	// 参数:
+	//   - ctx: 上下文
+	//   - query: 查询文本
+	//   - documents: 候选文档内容
+	//
+	// 返回:
+	//   - []RerankScore: 各文档的相关性分数（Index 对应 documents 下标，顺序不限）
+	//   - error: 重排序失败时返回错误
+	Rerank(ctx context.Context, query string, documents []string) ([]RerankScore, error)
+}
+
+// RerankScore 单个文档的重排序分数
+type RerankScore struct {
+	// Index 文档在候选列表中的下标
+	Index int
+
+	// Score 相关性分数，越大越相关
+	Score float32
+}
+
+// RerankerFunc 函数式 Reranker
+//
+// 使用示例（适配 llm.RerankProvider）:
+//
+//	reranker := vector.RerankerFunc(func(ctx context.Context, query string, docs []string) ([]vector.RerankScore, error) {
+//	    resp, err := provider.Rerank(ctx, llm.RerankRequest{Query: query, Documents: docs})
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    scores := make([]vector.RerankScore, len(resp.Results))
+//	    for i, r := range resp.Results {
+//	        scores[i] = vector.RerankScore{Index: r.Index, Score: float32(r.RelevanceScore)}
+//	    }
+//	    return scores, nil
+//	})
+type RerankerFunc func(ctx context.Context, query string, documents []string) ([]RerankScore, error)
+
+// Rerank 调用函数本身
+func (f RerankerFunc) Rerank(ctx context.Context, query string, documents []string) ([]RerankScore, error) {
+	return f(ctx, query, documents)
+}
+
+// SearchWithRerank 先向量检索召回候选，再重排序取前 k 个
+//
+// 向量相似度适合粗召回但精度有限，先多召回 candidates 个候选再用重排序模型精排，
+// 可显著提升 RAG 的命中率。返回文档的 Score 为重排序分数。
+//
+// 参数:
+//   - ctx: 上下文
+//   - store: 向量存储
+//   - reranker: 重排序器
+//   - query: 查询文本（用于重排序）
+//   - queryEmbedding: 查询向量（用于召回）
+//   - k: 最终返回的文档数
+//   - candidates: 召回的候选数，<= k 时默认取 4*k
+//   - opts: 召回阶段的搜索选项
+//
+// 返回:
+//   - []Document: 按重排序分数降序的文档列表
+//   - error: 召回或重排序失败时返回错误
+func SearchWithRerank(ctx context.Context, store Store, reranker Reranker, query string, queryEmbedding []float32, k, candidates int, opts ...SearchOption) ([]Document, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	if candidates <= k {
+		candidates = 4 * k
+	}
+
+	docs, err := store.Search(ctx, queryEmbedding, candidates, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	contents := make([]string, len(docs))
+	for i, doc := range docs {
+		contents[i] = doc.Content
+	}
+	scores, err := reranker.Rerank(ctx, query, contents)
+	if err != nil {
+		return nil, fmt.Errorf("rerank failed: %w", err)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	results := make([]Document, 0, min(k, len(scores)))
+	for _, s := range scores {
+		if len(results) == k {
+			break
+		}
+		if s.Index < 0 || s.Index >= len(docs) {
+			return nil, fmt.Errorf("rerank returned out-of-range index %d", s.Index)
+		}
+		doc := docs[s.Index]
+		doc.Score = s.Score
+		results = append(results, doc)
+	}
+	return results, nil
+}
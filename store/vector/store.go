@@ -168,6 +168,17 @@ func WithMetadata(include bool) SearchOption {
 
 // ============== 内存实现 ==============
 
+// Distance 相似度度量方式
+type Distance string
+
+const (
+	// DistanceCosine 余弦相似度（默认），分数范围 [-1, 1]
+	DistanceCosine Distance = "cosine"
+	// DistanceDot 点积，分数不做归一化
+	// 用于向量模长本身有意义的嵌入（如 local.BM25 的文档与查询向量，点积即 BM25 分数）
+	DistanceDot Distance = "dot"
+)
+
 // MemoryStore 内存向量存储
 //
 // MemoryStore 是一个简单的内存向量存储实现，
@@ -176,20 +187,38 @@ type MemoryStore struct {
 	docs      map[string]Document
 	mu        sync.RWMutex
 	dimension int
+	distance  Distance
+}
+
+// MemoryStoreOption 内存向量存储配置选项
+type MemoryStoreOption func(*MemoryStore)
+
+// WithDistance 设置相似度度量方式（默认 DistanceCosine）
+// 使用 DistanceDot 时 WithMinScore 的阈值作用于原始点积分数
+func WithDistance(distance Distance) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.distance = distance
+	}
 }
 
 // NewMemoryStore 创建内存向量存储
 //
 // 参数:
 //   - dimension: 向量维度
+//   - opts: 配置选项
 //
 // 返回:
 //   - *MemoryStore: 内存向量存储实例
-func NewMemoryStore(dimension int) *MemoryStore {
-	return &MemoryStore{
+func NewMemoryStore(dimension int, opts ...MemoryStoreOption) *MemoryStore {
+	s := &MemoryStore{
 		docs:      make(map[string]Document),
 		dimension: dimension,
+		distance:  DistanceCosine,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Add 添加文档
@@ -246,8 +275,12 @@ func (s *MemoryStore) Search(ctx context.Context, query []float32, k int, opts .
 			continue
 		}
 
-		// 余弦相似度
-		score := cosineSimilarity(query, doc.Embedding)
+		var score float32
+		if s.distance == DistanceDot {
+			score = dotProduct(query, doc.Embedding)
+		} else {
+			score = cosineSimilarity(query, doc.Embedding)
+		}
 
 		if cfg.MinScore > 0 && score < cfg.MinScore {
 			continue
@@ -361,6 +394,20 @@ func cosineSimilarity(a, b []float32) float32 {
 	return float32(dotProduct / (math.Sqrt(normA) * math.Sqrt(normB)))
 }
 
+// dotProduct 计算点积
+// 使用 float64 进行中间计算以获得更好的精度
+func dotProduct(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(sum)
+}
+
 // matchFilter 检查元数据是否匹配过滤条件
 func matchFilter(metadata, filter map[string]any) bool {
 	if metadata == nil {
@@ -102,6 +102,29 @@ func TestMemoryStoreSearch(t *testing.T) {
 	}
 }
 
+func TestMemoryStoreSearchDotDistance(t *testing.T) {
+	ctx := context.Background()
+	docs := []Document{
+		{ID: "short", Embedding: []float32{1.0, 0.0}},
+		{ID: "long", Embedding: []float32{3.0, 3.0}},
+	}
+	query := []float32{1.0, 0.2}
+
+	cosine := NewMemoryStore(2)
+	cosine.Add(ctx, docs)
+	results, _ := cosine.Search(ctx, query, 1)
+	if len(results) != 1 || results[0].ID != "short" {
+		t.Errorf("cosine top result = %+v, want short", results)
+	}
+
+	dot := NewMemoryStore(2, WithDistance(DistanceDot))
+	dot.Add(ctx, docs)
+	results, _ = dot.Search(ctx, query, 2, WithMinScore(2))
+	if len(results) != 1 || results[0].ID != "long" || results[0].Score < 3.59 || results[0].Score > 3.61 {
+		t.Errorf("dot results = %+v, want long with score 3.6", results)
+	}
+}
+
 func TestMemoryStoreSearchWithFilter(t *testing.T) {
 	store := NewMemoryStore(3)
 	ctx := context.Background()
@@ -306,3 +329,41 @@ func TestDocument(t *testing.T) {
 		t.Errorf("expected Score 0.95, got %f", doc.Score)
 	}
 }
+
+func TestSearchWithRerank(t *testing.T) {
+	store := NewMemoryStore(2)
+	ctx := context.Background()
+
+	_ = store.Add(ctx, []Document{
+		{ID: "1", Content: "apple pie", Embedding: []float32{1, 0}},
+		{ID: "2", Content: "golang generics", Embedding: []float32{0.9, 0.1}},
+		{ID: "3", Content: "go concurrency", Embedding: []float32{0.8, 0.2}},
+	})
+
+	// 只认包含 "go" 的文档，且越短越相关
+	reranker := RerankerFunc(func(ctx context.Context, query string, docs []string) ([]RerankScore, error) {
+		var scores []RerankScore
+		for i, d := range docs {
+			var score float32
+			if len(d) >= 2 && d[:2] == "go" {
+				score = 1 / float32(len(d))
+			}
+			scores = append(scores, RerankScore{Index: i, Score: score})
+		}
+		return scores, nil
+	})
+
+	results, err := SearchWithRerank(ctx, store, reranker, "go", []float32{1, 0}, 2, 0)
+	if err != nil {
+		t.Fatalf("SearchWithRerank failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ID != "3" || results[1].ID != "2" {
+		t.Errorf("unexpected order: %s, %s", results[0].ID, results[1].ID)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("scores should be rerank scores in descending order: %v, %v", results[0].Score, results[1].Score)
+	}
+}
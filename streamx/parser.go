@@ -2,6 +2,7 @@ package streamx
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 )
 
@@ -37,13 +38,66 @@ type openAIChunk struct {
 				} `json:"function,omitempty"`
 			} `json:"tool_calls,omitempty"`
 		} `json:"delta"`
-		FinishReason string `json:"finish_reason,omitempty"`
+		Logprobs     *OpenAILogprobs `json:"logprobs,omitempty"`
+		FinishReason string          `json:"finish_reason,omitempty"`
 	} `json:"choices"`
-	Usage *struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage,omitempty"`
+	Usage *OpenAIUsage `json:"usage,omitempty"`
+}
+
+// OpenAILogprobs 是 OpenAI 兼容 API 中 choices[].logprobs 的 JSON 结构
+// 非流式响应与流式响应共用此结构
+type OpenAILogprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
+// OpenAIUsage 是 OpenAI 兼容 API 的 usage JSON 结构
+//
+// 除标准的 prompt/completion/total 外，还包含各厂商扩展的明细字段：
+//   - prompt_tokens_details: OpenAI/Qwen/Ark 的缓存命中与音频输入 Token
+//   - completion_tokens_details: OpenAI/DeepSeek 的推理与音频输出 Token
+//   - prompt_cache_hit_tokens/prompt_cache_miss_tokens: DeepSeek 的 KV 缓存统计
+//
+// 非流式响应与流式响应共用此结构，Provider 可直接复用
+type OpenAIUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+		AudioTokens  int `json:"audio_tokens"`
+	} `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+		AudioTokens     int `json:"audio_tokens"`
+	} `json:"completion_tokens_details,omitempty"`
+	PromptCacheHitTokens  int `json:"prompt_cache_hit_tokens,omitempty"`
+	PromptCacheMissTokens int `json:"prompt_cache_miss_tokens,omitempty"`
+}
+
+// ToUsage 转换为统一的 Usage 结构
+func (u *OpenAIUsage) ToUsage() Usage {
+	usage := Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+		CacheMissTokens:  u.PromptCacheMissTokens,
+	}
+	if d := u.PromptTokensDetails; d != nil {
+		usage.CachedTokens = d.CachedTokens
+		usage.AudioPromptTokens = d.AudioTokens
+	}
+	if d := u.CompletionTokensDetails; d != nil {
+		usage.ReasoningTokens = d.ReasoningTokens
+		usage.AudioCompletionTokens = d.AudioTokens
+	}
+	// DeepSeek 使用顶层字段返回缓存命中数
+	if u.PromptCacheHitTokens > 0 {
+		usage.CachedTokens = u.PromptCacheHitTokens
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
 }
 
 // Parse 解析 OpenAI 格式的 JSON 数据为 Chunk
@@ -60,12 +114,21 @@ func (p *OpenAIParser) Parse(data []byte) (*Chunk, error) {
 		Raw:   data,
 	}
 
+	// 开启 stream_options.include_usage 后，最后一个块的 choices 为空且携带 usage
+	if oai.Usage != nil {
+		usage := oai.Usage.ToUsage()
+		chunk.Usage = &usage
+	}
+
 	if len(oai.Choices) > 0 {
 		choice := oai.Choices[0]
 		chunk.Index = choice.Index
 		chunk.Role = choice.Delta.Role
 		chunk.Content = choice.Delta.Content
 		chunk.FinishReason = choice.FinishReason
+		if choice.Logprobs != nil {
+			chunk.Logprobs = choice.Logprobs.Content
+		}
 
 		// 提取推理内容（Qwen3 用 reasoning，DeepSeek/OpenAI 用 reasoning_content）
 		if choice.Delta.Reasoning != "" {
@@ -118,21 +181,43 @@ type claudeEvent struct {
 	Delta *struct {
 		Type       string `json:"type,omitempty"`
 		Text       string `json:"text,omitempty"`
+		Thinking   string `json:"thinking,omitempty"` // extended thinking 的 thinking_delta
 		StopReason string `json:"stop_reason,omitempty"`
 	} `json:"delta,omitempty"`
-	Usage *struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage,omitempty"`
+	Usage *ClaudeUsage `json:"usage,omitempty"`
 }
 
 type claudeMessage struct {
-	ID           string `json:"id"`
-	Type         string `json:"type"`
-	Role         string `json:"role"`
-	Model        string `json:"model"`
-	StopReason   string `json:"stop_reason,omitempty"`
-	StopSequence string `json:"stop_sequence,omitempty"`
+	ID           string       `json:"id"`
+	Type         string       `json:"type"`
+	Role         string       `json:"role"`
+	Model        string       `json:"model"`
+	StopReason   string       `json:"stop_reason,omitempty"`
+	StopSequence string       `json:"stop_sequence,omitempty"`
+	Usage        *ClaudeUsage `json:"usage,omitempty"`
+}
+
+// ClaudeUsage 是 Anthropic Messages API 的 usage JSON 结构
+//
+// 注意 input_tokens 不包含缓存读取和缓存写入的 Token，
+// ToUsage 会将三者合计为 PromptTokens，与 OpenAI 口径保持一致
+type ClaudeUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// ToUsage 转换为统一的 Usage 结构
+func (u *ClaudeUsage) ToUsage() Usage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return Usage{
+		PromptTokens:        prompt,
+		CompletionTokens:    u.OutputTokens,
+		TotalTokens:         prompt + u.OutputTokens,
+		CachedTokens:        u.CacheReadInputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+	}
 }
 
 // Parse 解析 Claude 格式的事件数据为 Chunk
@@ -156,17 +241,30 @@ func (p *ClaudeParser) Parse(data []byte) (*Chunk, error) {
 			chunk.ID = evt.Message.ID
 			chunk.Role = evt.Message.Role
 			chunk.Model = evt.Message.Model
+			if evt.Message.Usage != nil {
+				usage := evt.Message.Usage.ToUsage()
+				chunk.Usage = &usage
+			}
 		}
 
 	case "content_block_delta":
 		if evt.Delta != nil {
-			chunk.Content = evt.Delta.Text
+			switch evt.Delta.Type {
+			case "thinking_delta":
+				chunk.Reasoning = evt.Delta.Thinking
+			default:
+				chunk.Content = evt.Delta.Text
+			}
 		}
 
 	case "message_delta":
 		if evt.Delta != nil {
 			chunk.FinishReason = evt.Delta.StopReason
 		}
+		// message_delta 的 usage 只包含累计的 output_tokens
+		if evt.Usage != nil {
+			chunk.Usage = &Usage{CompletionTokens: evt.Usage.OutputTokens}
+		}
 
 	case "message_stop":
 		chunk.FinishReason = "stop"
@@ -199,7 +297,8 @@ type geminiChunk struct {
 	Candidates []struct {
 		Content struct {
 			Parts []struct {
-				Text string `json:"text"`
+				Text    string `json:"text"`
+				Thought bool   `json:"thought,omitempty"` // 开启 includeThoughts 后的思考摘要
 			} `json:"parts"`
 			Role string `json:"role"`
 		} `json:"content"`
@@ -209,11 +308,35 @@ type geminiChunk struct {
 			Probability string `json:"probability"`
 		} `json:"safetyRatings,omitempty"`
 	} `json:"candidates"`
-	UsageMetadata *struct {
-		PromptTokenCount     int `json:"promptTokenCount"`
-		CandidatesTokenCount int `json:"candidatesTokenCount"`
-		TotalTokenCount      int `json:"totalTokenCount"`
-	} `json:"usageMetadata,omitempty"`
+	UsageMetadata *GeminiUsage `json:"usageMetadata,omitempty"`
+}
+
+// GeminiUsage 是 Gemini API 的 usageMetadata JSON 结构
+//
+// 注意 candidatesTokenCount 不包含思考 Token（thoughtsTokenCount），
+// ToUsage 会将两者合计为 CompletionTokens，与 OpenAI 口径保持一致
+type GeminiUsage struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+	ThoughtsTokenCount      int `json:"thoughtsTokenCount,omitempty"`
+}
+
+// ToUsage 转换为统一的 Usage 结构
+func (u *GeminiUsage) ToUsage() Usage {
+	completion := u.CandidatesTokenCount + u.ThoughtsTokenCount
+	total := u.TotalTokenCount
+	if total == 0 {
+		total = u.PromptTokenCount + completion
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokenCount,
+		CompletionTokens: completion,
+		TotalTokens:      total,
+		CachedTokens:     u.CachedContentTokenCount,
+		ReasoningTokens:  u.ThoughtsTokenCount,
+	}
 }
 
 // Parse 解析 Gemini 格式的 JSON 数据为 Chunk
@@ -228,17 +351,27 @@ func (p *GeminiParser) Parse(data []byte) (*Chunk, error) {
 		Raw: data,
 	}
 
+	if gem.UsageMetadata != nil {
+		usage := gem.UsageMetadata.ToUsage()
+		chunk.Usage = &usage
+	}
+
 	if len(gem.Candidates) > 0 {
 		candidate := gem.Candidates[0]
 		chunk.Role = candidate.Content.Role
 		chunk.FinishReason = candidate.FinishReason
 
-		// 合并所有文本部分
-		var content strings.Builder
+		// 合并所有文本部分，思考部分单独归入 Reasoning
+		var content, reasoning strings.Builder
 		for _, part := range candidate.Content.Parts {
+			if part.Thought {
+				reasoning.WriteString(part.Text)
+				continue
+			}
 			content.WriteString(part.Text)
 		}
 		chunk.Content = content.String()
+		chunk.Reasoning = reasoning.String()
 	}
 
 	return chunk, nil
@@ -257,6 +390,237 @@ func (p *GeminiParser) IsDone(data []byte) bool {
 	return false
 }
 
+// ============== OpenAI Responses 解析器 ==============
+
+// ResponsesParser 实现 OpenAI Responses API（/v1/responses）流式响应格式的解析
+// 与 Chat Completions 不同，Responses API 按语义事件推送，data 中的 type 字段标识事件类型：
+//   - response.created: 响应创建，包含 ID、模型
+//   - response.output_text.delta: 文本增量
+//   - response.reasoning_summary_text.delta: 推理摘要增量
+//   - response.output_item.added: 新输出项（函数调用在此给出 call_id 和名称）
+//   - response.function_call_arguments.delta: 函数调用参数增量（按 item_id 关联）
+//   - response.completed / response.incomplete: 响应结束，包含 usage
+//
+// 参数增量只携带 item_id，解析器需记录 item_id 到 call_id 的映射，因此每个流应使用独立实例。
+type ResponsesParser struct {
+	callIDs      map[string]string // item_id -> call_id
+	hasToolCalls bool
+}
+
+// responsesEvent 是 Responses API 流式事件的 JSON 结构
+type responsesEvent struct {
+	Type   string `json:"type"`
+	Delta  string `json:"delta,omitempty"`
+	ItemID string `json:"item_id,omitempty"`
+	Item   *struct {
+		Type      string `json:"type"`
+		ID        string `json:"id"`
+		CallID    string `json:"call_id,omitempty"`
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"item,omitempty"`
+	Response *struct {
+		ID                string `json:"id"`
+		Model             string `json:"model"`
+		Status            string `json:"status"`
+		IncompleteDetails *struct {
+			Reason string `json:"reason"`
+		} `json:"incomplete_details,omitempty"`
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error,omitempty"`
+		Usage *ResponsesUsage `json:"usage,omitempty"`
+	} `json:"response,omitempty"`
+	// error 事件的字段位于顶层
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ResponsesUsage 是 OpenAI Responses API 的 usage JSON 结构
+// 非流式响应与流式响应共用此结构，Provider 可直接复用
+type ResponsesUsage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details,omitempty"`
+	OutputTokensDetails *struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"output_tokens_details,omitempty"`
+}
+
+// ToUsage 转换为统一的 Usage 结构
+func (u *ResponsesUsage) ToUsage() Usage {
+	usage := Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if d := u.InputTokensDetails; d != nil {
+		usage.CachedTokens = d.CachedTokens
+	}
+	if d := u.OutputTokensDetails; d != nil {
+		usage.ReasoningTokens = d.ReasoningTokens
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
+
+// ResponsesFinishReason 将 Responses API 的响应状态映射为 Chat Completions 风格的结束原因
+//
+// status 为 incomplete 时按 incomplete_details.reason 区分 length 与 content_filter；
+// 正常完成且包含函数调用时返回 tool_calls。
+func ResponsesFinishReason(status, incompleteReason string, hasToolCalls bool) string {
+	switch status {
+	case "incomplete":
+		if incompleteReason == "content_filter" {
+			return "content_filter"
+		}
+		return "length"
+	case "failed", "cancelled":
+		return status
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+// Parse 解析 Responses API 的事件数据为 Chunk
+// 未关注的事件（如 content_part.added）返回只包含 Raw 的空块
+func (p *ResponsesParser) Parse(data []byte) (*Chunk, error) {
+	var evt responsesEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil, err
+	}
+
+	chunk := &Chunk{
+		Raw: data,
+	}
+
+	switch evt.Type {
+	case "response.created":
+		if evt.Response != nil {
+			chunk.ID = evt.Response.ID
+			chunk.Model = evt.Response.Model
+			chunk.Role = "assistant"
+		}
+
+	case "response.output_text.delta":
+		chunk.Content = evt.Delta
+
+	case "response.reasoning_summary_text.delta":
+		chunk.Reasoning = evt.Delta
+
+	case "response.output_item.added":
+		if evt.Item != nil && evt.Item.Type == "function_call" {
+			if p.callIDs == nil {
+				p.callIDs = make(map[string]string)
+			}
+			p.callIDs[evt.Item.ID] = evt.Item.CallID
+			p.hasToolCalls = true
+			chunk.ToolCalls = []ToolCall{{
+				ID:        evt.Item.CallID,
+				Type:      "function",
+				Name:      evt.Item.Name,
+				Arguments: evt.Item.Arguments,
+			}}
+		}
+
+	case "response.function_call_arguments.delta":
+		if callID, ok := p.callIDs[evt.ItemID]; ok {
+			chunk.ToolCalls = []ToolCall{{ID: callID, Arguments: evt.Delta}}
+		}
+
+	case "response.completed", "response.incomplete":
+		if evt.Response != nil {
+			reason := ""
+			if evt.Response.IncompleteDetails != nil {
+				reason = evt.Response.IncompleteDetails.Reason
+			}
+			chunk.FinishReason = ResponsesFinishReason(evt.Response.Status, reason, p.hasToolCalls)
+			if evt.Response.Usage != nil {
+				usage := evt.Response.Usage.ToUsage()
+				chunk.Usage = &usage
+			}
+		}
+
+	case "response.failed":
+		if evt.Response != nil && evt.Response.Error != nil {
+			return nil, fmt.Errorf("responses stream failed: %s: %s", evt.Response.Error.Code, evt.Response.Error.Message)
+		}
+		return nil, fmt.Errorf("responses stream failed")
+
+	case "error":
+		return nil, fmt.Errorf("responses stream error: %s: %s", evt.Code, evt.Message)
+	}
+
+	return chunk, nil
+}
+
+// IsDone 检查是否为 Responses API 的流结束事件
+// response.failed 不视为正常结束，以便错误经 Errors 通道返回
+func (p *ResponsesParser) IsDone(data []byte) bool {
+	var evt responsesEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return false
+	}
+	return evt.Type == "response.completed" || evt.Type == "response.incomplete"
+}
+
+// ============== 文本补全解析器 ==============
+
+// TextCompletionParser 实现 legacy 文本补全接口（/v1/completions）流式响应格式的解析
+// 与 Chat Completions 相同使用 SSE 与 "[DONE]" 结束标记，
+// 但增量文本位于 choices[].text 而非 choices[].delta.content
+type TextCompletionParser struct{}
+
+// textCompletionChunk 是文本补全流式响应的 JSON 结构
+type textCompletionChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int    `json:"index"`
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason,omitempty"`
+	} `json:"choices"`
+	Usage *OpenAIUsage `json:"usage,omitempty"`
+}
+
+// Parse 解析文本补全格式的 JSON 数据为 Chunk
+func (p *TextCompletionParser) Parse(data []byte) (*Chunk, error) {
+	var tc textCompletionChunk
+	if err := json.Unmarshal(data, &tc); err != nil {
+		return nil, err
+	}
+
+	chunk := &Chunk{
+		ID:    tc.ID,
+		Model: tc.Model,
+		Raw:   data,
+	}
+	if tc.Usage != nil {
+		usage := tc.Usage.ToUsage()
+		chunk.Usage = &usage
+	}
+	if len(tc.Choices) > 0 {
+		choice := tc.Choices[0]
+		chunk.Index = choice.Index
+		chunk.Content = choice.Text
+		chunk.FinishReason = choice.FinishReason
+	}
+	return chunk, nil
+}
+
+// IsDone 检查是否为流结束标记 "[DONE]"
+func (p *TextCompletionParser) IsDone(data []byte) bool {
+	return strings.TrimSpace(string(data)) == "[DONE]"
+}
+
 // ============== 通用 JSON 解析器 ==============
 
 // JSONParser 提供可配置的通用 JSON 解析器
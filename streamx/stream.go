@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -39,6 +40,15 @@ const (
 	// CustomFormat 自定义格式
 	// 需要配合 SetParser 方法使用自定义解析器
 	CustomFormat
+
+	// ResponsesFormat OpenAI Responses API 流式格式
+	// 使用 SSE，data 中的 type 字段标识事件：response.output_text.delta、
+	// response.function_call_arguments.delta、response.completed 等
+	ResponsesFormat
+
+	// TextCompletionFormat OpenAI 兼容的文本补全（/v1/completions）流式格式
+	// 使用 SSE，增量文本位于 choices[].text，结束标记为 "data: [DONE]"
+	TextCompletionFormat
 )
 
 // Chunk 表示流式响应中的单个数据块
@@ -68,6 +78,12 @@ type Chunk struct {
 	// Index 多选项时的索引号
 	// 当请求 n>1 时，用于区分不同的生成结果
 	Index int `json:"index,omitempty"`
+	// Logprobs 本块输出 Token 的对数概率（请求开启 logprobs 时返回）
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+	// Usage Token 使用统计
+	// 通常只在最后一个块中返回（OpenAI 需开启 stream_options.include_usage），
+	// Claude 分别在 message_start 和 message_delta 中返回输入和输出部分
+	Usage *Usage `json:"usage,omitempty"`
 	// Raw 原始 JSON 数据
 	// 保留原始数据以便需要时进行自定义解析
 	Raw json.RawMessage `json:"raw,omitempty"`
@@ -88,6 +104,25 @@ type ToolCall struct {
 	Arguments string `json:"arguments,omitempty"`
 }
 
+// TokenLogprob 表示单个输出 Token 的对数概率
+type TokenLogprob struct {
+	// Token 输出的 Token 文本
+	Token string `json:"token"`
+	// Logprob 该 Token 的对数概率
+	Logprob float64 `json:"logprob"`
+	// Bytes Token 的 UTF-8 字节表示（多字节字符可能跨 Token 拆分）
+	Bytes []int `json:"bytes,omitempty"`
+	// TopLogprobs 该位置概率最高的候选 Token
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
+// TopLogprob 表示某位置的一个候选 Token 及其对数概率
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
 // Usage 记录本次请求的 Token 使用统计
 // 用于计费和配额管理
 type Usage struct {
@@ -97,6 +132,64 @@ type Usage struct {
 	CompletionTokens int `json:"completion_tokens,omitempty"`
 	// TotalTokens 总计消耗的 Token 数（输入+输出）
 	TotalTokens int `json:"total_tokens,omitempty"`
+
+	// CachedTokens 命中提示词缓存的输入 Token 数（包含在 PromptTokens 中）
+	// 对应 OpenAI/Qwen/Ark 的 prompt_tokens_details.cached_tokens、
+	// Anthropic 的 cache_read_input_tokens、DeepSeek 的 prompt_cache_hit_tokens、
+	// Gemini 的 cachedContentTokenCount
+	CachedTokens int `json:"cached_tokens,omitempty"`
+	// CacheCreationTokens 写入提示词缓存的输入 Token 数（包含在 PromptTokens 中）
+	// 目前仅 Anthropic 返回（cache_creation_input_tokens）
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+	// CacheMissTokens 未命中缓存的输入 Token 数（包含在 PromptTokens 中）
+	// 目前仅 DeepSeek 返回（prompt_cache_miss_tokens）
+	CacheMissTokens int `json:"cache_miss_tokens,omitempty"`
+	// ReasoningTokens 推理/思考过程消耗的 Token 数（包含在 CompletionTokens 中）
+	// 对应 OpenAI 的 completion_tokens_details.reasoning_tokens、Gemini 的 thoughtsTokenCount
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+	// AudioPromptTokens 输入中的音频 Token 数（包含在 PromptTokens 中）
+	AudioPromptTokens int `json:"audio_prompt_tokens,omitempty"`
+	// AudioCompletionTokens 输出中的音频 Token 数（包含在 CompletionTokens 中）
+	AudioCompletionTokens int `json:"audio_completion_tokens,omitempty"`
+}
+
+// Merge 将另一份 Usage 中的非零字段合并到当前 Usage
+//
+// 流式响应中 Usage 可能分多次返回（如 Claude 在 message_start 中返回输入 Token，
+// 在 message_delta 中返回输出 Token），后到的非零值覆盖先到的值。
+// 若 other 未提供 TotalTokens，则按 PromptTokens + CompletionTokens 重新计算。
+func (u *Usage) Merge(other Usage) {
+	mergeInt := func(dst *int, src int) {
+		if src > 0 {
+			*dst = src
+		}
+	}
+	mergeInt(&u.PromptTokens, other.PromptTokens)
+	mergeInt(&u.CompletionTokens, other.CompletionTokens)
+	mergeInt(&u.CachedTokens, other.CachedTokens)
+	mergeInt(&u.CacheCreationTokens, other.CacheCreationTokens)
+	mergeInt(&u.CacheMissTokens, other.CacheMissTokens)
+	mergeInt(&u.ReasoningTokens, other.ReasoningTokens)
+	mergeInt(&u.AudioPromptTokens, other.AudioPromptTokens)
+	mergeInt(&u.AudioCompletionTokens, other.AudioCompletionTokens)
+	if other.TotalTokens > 0 {
+		u.TotalTokens = other.TotalTokens
+	} else {
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+}
+
+// Add 累加另一份 Usage（用于汇总多次请求）
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.CachedTokens += other.CachedTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
+	u.CacheMissTokens += other.CacheMissTokens
+	u.ReasoningTokens += other.ReasoningTokens
+	u.AudioPromptTokens += other.AudioPromptTokens
+	u.AudioCompletionTokens += other.AudioCompletionTokens
 }
 
 // Result 表示流式响应处理完成后的完整结果
@@ -106,6 +199,8 @@ type Result struct {
 	ID string `json:"id,omitempty"`
 	// Content 所有块拼接后的完整文本内容
 	Content string `json:"content,omitempty"`
+	// Reasoning 所有块拼接后的完整推理/思考内容
+	Reasoning string `json:"reasoning,omitempty"`
 	// Role 消息角色
 	Role string `json:"role,omitempty"`
 	// Model 使用的模型名称
@@ -115,12 +210,58 @@ type Result struct {
 	// ToolCalls 合并后的完整工具调用列表
 	// 工具调用的参数已从多个块中合并完成
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Logprobs 合并后的输出 Token 对数概率
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+	// Choices 按 Chunk.Index 分别累积的各选项结果（n>1 时有多个）
+	// 顶层的 Content/Reasoning/FinishReason/ToolCalls/Logprobs 与 Choices[0] 一致
+	Choices []ChoiceResult `json:"choices,omitempty"`
 	// Usage Token 使用统计（如果 API 返回）
 	Usage Usage `json:"usage,omitempty"`
 	// Chunks 保存所有原始块，用于调试或重放
 	Chunks []*Chunk `json:"chunks,omitempty"`
 }
 
+// ChoiceResult 表示单个选项合并后的完整结果
+type ChoiceResult struct {
+	// Index 选项索引
+	Index int `json:"index"`
+	// Content 该选项拼接后的完整文本内容
+	Content string `json:"content,omitempty"`
+	// Reasoning 该选项拼接后的完整推理内容
+	Reasoning string `json:"reasoning,omitempty"`
+	// FinishReason 该选项的结束原因
+	FinishReason string `json:"finish_reason,omitempty"`
+	// ToolCalls 该选项合并后的工具调用列表
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Logprobs 该选项输出 Token 的对数概率（请求开启 logprobs 时返回）
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+}
+
+// maxChoices 聚合的选项数上限，防止异常的 index 导致按索引扩容时占用大量内存
+const maxChoices = 128
+
+// validChoice 判断选项索引是否在可聚合范围 [0, maxChoices) 内
+func validChoice(index int) bool {
+	return index >= 0 && index < maxChoices
+}
+
+// choice 返回指定索引的选项，不存在时按索引扩容；索引越界时返回 nil
+func (r *Result) choice(index int) *ChoiceResult {
+	if !validChoice(index) {
+		return nil
+	}
+	for len(r.Choices) <= index {
+		r.Choices = append(r.Choices, ChoiceResult{Index: len(r.Choices)})
+	}
+	return &r.Choices[index]
+}
+
+// choiceBuffer 单个选项的增量文本缓冲
+type choiceBuffer struct {
+	content   bytes.Buffer
+	reasoning bytes.Buffer
+}
+
 // Stream 是流式响应的核心处理器
 // 负责从 io.Reader 读取数据，解析为 Chunk，并提供多种消费方式
 //
@@ -196,6 +337,10 @@ func NewStream(r io.Reader, format Format) *Stream {
 		s.parser = &ClaudeParser{}
 	case GeminiFormat:
 		s.parser = &GeminiParser{}
+	case ResponsesFormat:
+		s.parser = &ResponsesParser{}
+	case TextCompletionFormat:
+		s.parser = &TextCompletionParser{}
 	default:
 		s.parser = &OpenAIParser{}
 	}
@@ -408,7 +553,7 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 	defer close(s.chunks)
 	defer close(s.done)
 
-	var contentBuf bytes.Buffer
+	buffers := make(map[int]*choiceBuffer)
 
 	for {
 		select {
@@ -422,13 +567,7 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 			if err != io.EOF {
 				s.sendErrorWithCallback(err, onError)
 			}
-			s.mu.Lock()
-			s.result.Content = contentBuf.String()
-			result := s.result
-			s.mu.Unlock()
-			if onDone != nil {
-				onDone(result)
-			}
+			s.finish(buffers, onDone)
 			return
 		}
 
@@ -448,13 +587,7 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 			if err != nil {
 				// 如果解析失败且是结束标记，则正常结束
 				if s.parser.IsDone([]byte(data)) {
-					s.mu.Lock()
-					s.result.Content = contentBuf.String()
-					result := s.result
-					s.mu.Unlock()
-					if onDone != nil {
-						onDone(result)
-					}
+					s.finish(buffers, onDone)
 					return
 				}
 				s.sendErrorWithCallback(err, onError)
@@ -462,7 +595,16 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 			}
 
 			if chunk != nil {
-				contentBuf.WriteString(chunk.Content)
+				// 越界索引的块仍会下发，但不参与按选项的聚合
+				if validChoice(chunk.Index) {
+					buf := buffers[chunk.Index]
+					if buf == nil {
+						buf = &choiceBuffer{}
+						buffers[chunk.Index] = buf
+					}
+					buf.content.WriteString(chunk.Content)
+					buf.reasoning.WriteString(chunk.Reasoning)
+				}
 
 				// 更新结果（加锁保护）
 				s.mu.Lock()
@@ -476,11 +618,17 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 				if chunk.Model != "" && s.result.Model == "" {
 					s.result.Model = chunk.Model
 				}
-				if chunk.FinishReason != "" {
-					s.result.FinishReason = chunk.FinishReason
+				if choice := s.result.choice(chunk.Index); choice != nil {
+					if chunk.FinishReason != "" {
+						choice.FinishReason = chunk.FinishReason
+					}
+					if len(chunk.ToolCalls) > 0 {
+						choice.ToolCalls = mergeToolCalls(choice.ToolCalls, chunk.ToolCalls)
+					}
+					choice.Logprobs = append(choice.Logprobs, chunk.Logprobs...)
 				}
-				if len(chunk.ToolCalls) > 0 {
-					s.result.ToolCalls = mergeToolCalls(s.result.ToolCalls, chunk.ToolCalls)
+				if chunk.Usage != nil {
+					s.result.Usage.Merge(*chunk.Usage)
 				}
 				s.mu.Unlock()
 
@@ -499,13 +647,7 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 				// 在发送 chunk 后检查是否结束
 				// 这确保了最后一个有内容的 chunk 被正确处理
 				if s.parser.IsDone([]byte(data)) {
-					s.mu.Lock()
-					s.result.Content = contentBuf.String()
-					result := s.result
-					s.mu.Unlock()
-					if onDone != nil {
-						onDone(result)
-					}
+					s.finish(buffers, onDone)
 					return
 				}
 			}
@@ -513,6 +655,30 @@ func (s *Stream) processLoop(onChunk func(*Chunk), onDone func(*Result), onError
 	}
 }
 
+// finish 将各选项的文本缓冲写入结果并触发完成回调
+// 顶层字段取自索引 0 的选项，保持单选项场景的使用方式不变
+func (s *Stream) finish(buffers map[int]*choiceBuffer, onDone func(*Result)) {
+	s.mu.Lock()
+	for index, buf := range buffers {
+		choice := s.result.choice(index)
+		choice.Content = buf.content.String()
+		choice.Reasoning = buf.reasoning.String()
+	}
+	if len(s.result.Choices) > 0 {
+		first := s.result.Choices[0]
+		s.result.Content = first.Content
+		s.result.Reasoning = first.Reasoning
+		s.result.FinishReason = first.FinishReason
+		s.result.ToolCalls = first.ToolCalls
+		s.result.Logprobs = first.Logprobs
+	}
+	result := s.result
+	s.mu.Unlock()
+	if onDone != nil {
+		onDone(result)
+	}
+}
+
 // sendErrorWithCallback 发送错误到错误通道并触发回调
 // 错误通道有缓冲但不阻塞，如果通道满则丢弃
 func (s *Stream) sendErrorWithCallback(err error, onError func(error)) {
@@ -560,6 +726,58 @@ func mergeToolCalls(existing, new []ToolCall) []ToolCall {
 	return existing
 }
 
+// ============== 生成式流 ==============
+
+// NewChunkStream 创建由代码逐块产生数据的流
+//
+// 用于中间件改写上游流（内容审核、格式转换等）或非 SSE 协议的适配：
+// produce 在独立 goroutine 中运行，通过 emit 依次发送块，
+// 返回非 nil 错误时作为流错误上报。消费方关闭流后 emit 返回错误，produce 应尽快退出。
+func NewChunkStream(ctx context.Context, produce func(emit func(*Chunk) error) error) *Stream {
+	pr, pw := io.Pipe()
+	s := NewStreamWithContext(ctx, pr, CustomFormat)
+	s.parser = chunkParser{}
+
+	// 管道读取不感知 context，流取消时主动关闭以解除 processLoop 的阻塞读
+	context.AfterFunc(s.ctx, func() {
+		pr.CloseWithError(context.Canceled)
+	})
+
+	go func() {
+		emit := func(chunk *Chunk) error {
+			data, err := json.Marshal(chunk)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(pw, "data: %s\n\n", data)
+			return err
+		}
+		if err := produce(emit); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		_, _ = io.WriteString(pw, "data: [DONE]\n\n")
+		pw.Close()
+	}()
+
+	return s
+}
+
+// chunkParser 解析 NewChunkStream 写入的 Chunk JSON
+type chunkParser struct{}
+
+func (chunkParser) Parse(data []byte) (*Chunk, error) {
+	var chunk Chunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+func (chunkParser) IsDone(data []byte) bool {
+	return string(data) == "[DONE]"
+}
+
 // ============== 便捷函数 ==============
 
 // CollectContent 收集流式响应的完整内容
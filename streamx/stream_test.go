@@ -373,3 +373,294 @@ data: END
 		t.Errorf("expected 'Custom', got '%s'", result.Content)
 	}
 }
+
+func TestStream_OpenAIUsageDetails(t *testing.T) {
+	input := `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"}}]}
+
+data: {"id":"1","choices":[],"usage":{"prompt_tokens":100,"completion_tokens":50,"total_tokens":150,"prompt_tokens_details":{"cached_tokens":80},"completion_tokens_details":{"reasoning_tokens":30}}}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), OpenAIFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	u := result.Usage
+	if u.PromptTokens != 100 || u.CompletionTokens != 50 || u.TotalTokens != 150 {
+		t.Errorf("unexpected usage totals: %+v", u)
+	}
+	if u.CachedTokens != 80 {
+		t.Errorf("CachedTokens = %d, want 80", u.CachedTokens)
+	}
+	if u.ReasoningTokens != 30 {
+		t.Errorf("ReasoningTokens = %d, want 30", u.ReasoningTokens)
+	}
+}
+
+func TestStream_ClaudeUsageMerge(t *testing.T) {
+	input := `data: {"type":"message_start","message":{"id":"msg_1","role":"assistant","model":"claude","usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":90,"cache_creation_input_tokens":5}}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hi"}}
+
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":20}}
+
+data: {"type":"message_stop"}
+
+`
+	result, err := NewStream(strings.NewReader(input), ClaudeFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	u := result.Usage
+	if u.PromptTokens != 105 {
+		t.Errorf("PromptTokens = %d, want 105", u.PromptTokens)
+	}
+	if u.CompletionTokens != 20 {
+		t.Errorf("CompletionTokens = %d, want 20", u.CompletionTokens)
+	}
+	if u.TotalTokens != 125 {
+		t.Errorf("TotalTokens = %d, want 125", u.TotalTokens)
+	}
+	if u.CachedTokens != 90 || u.CacheCreationTokens != 5 {
+		t.Errorf("unexpected cache breakdown: %+v", u)
+	}
+}
+
+func TestGeminiParser_Usage(t *testing.T) {
+	parser := &GeminiParser{}
+	chunk, err := parser.Parse([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":5,"thoughtsTokenCount":7,"totalTokenCount":22,"cachedContentTokenCount":4}}`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if chunk.Usage == nil {
+		t.Fatal("expected usage")
+	}
+	if chunk.Usage.CompletionTokens != 12 || chunk.Usage.ReasoningTokens != 7 || chunk.Usage.CachedTokens != 4 {
+		t.Errorf("unexpected usage: %+v", *chunk.Usage)
+	}
+}
+
+func TestStream_ReasoningAccumulated(t *testing.T) {
+	input := `data: {"id":"1","choices":[{"delta":{"reasoning_content":"先想"}}]}
+
+data: {"id":"1","choices":[{"delta":{"reasoning_content":"一下"}}]}
+
+data: {"id":"1","choices":[{"delta":{"content":"答案"},"finish_reason":"stop"}]}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), OpenAIFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	if result.Reasoning != "先想一下" {
+		t.Errorf("Reasoning = %q, want %q", result.Reasoning, "先想一下")
+	}
+	if result.Content != "答案" {
+		t.Errorf("Content = %q, want %q", result.Content, "答案")
+	}
+}
+
+func TestClaudeParser_ThinkingDelta(t *testing.T) {
+	parser := &ClaudeParser{}
+	chunk, err := parser.Parse([]byte(`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"hmm"}}`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if chunk.Reasoning != "hmm" || chunk.Content != "" {
+		t.Errorf("unexpected chunk: reasoning=%q content=%q", chunk.Reasoning, chunk.Content)
+	}
+}
+
+func TestGeminiParser_ThoughtParts(t *testing.T) {
+	parser := &GeminiParser{}
+	chunk, err := parser.Parse([]byte(`{"candidates":[{"content":{"parts":[{"text":"plan","thought":true},{"text":"ok"}]}}]}`))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if chunk.Reasoning != "plan" || chunk.Content != "ok" {
+		t.Errorf("unexpected chunk: reasoning=%q content=%q", chunk.Reasoning, chunk.Content)
+	}
+}
+
+func TestStream_MultipleChoices(t *testing.T) {
+	input := `data: {"id":"1","choices":[{"index":0,"delta":{"content":"A"}}]}
+
+data: {"id":"1","choices":[{"index":1,"delta":{"content":"B"}}]}
+
+data: {"id":"1","choices":[{"index":0,"delta":{"content":"a"},"finish_reason":"stop"}]}
+
+data: {"id":"1","choices":[{"index":1,"delta":{"content":"b"},"finish_reason":"length"}]}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), OpenAIFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	if len(result.Choices) != 2 {
+		t.Fatalf("len(Choices) = %d, want 2", len(result.Choices))
+	}
+	if result.Choices[0].Content != "Aa" || result.Choices[0].FinishReason != "stop" {
+		t.Errorf("Choices[0] = %+v", result.Choices[0])
+	}
+	if result.Choices[1].Content != "Bb" || result.Choices[1].FinishReason != "length" {
+		t.Errorf("Choices[1] = %+v", result.Choices[1])
+	}
+	if result.Content != "Aa" || result.FinishReason != "stop" {
+		t.Errorf("top-level fields should mirror choice 0, got content=%q finish=%q", result.Content, result.FinishReason)
+	}
+}
+
+func TestStream_InvalidChoiceIndex(t *testing.T) {
+	input := `data: {"id":"1","choices":[{"index":0,"delta":{"content":"A"}}]}
+
+data: {"id":"1","choices":[{"index":-1,"delta":{"content":"X"}}]}
+
+data: {"id":"1","choices":[{"index":1000000000,"delta":{"content":"Y"},"finish_reason":"stop"}]}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), OpenAIFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	if len(result.Choices) != 1 || result.Content != "A" {
+		t.Errorf("out-of-range indexes should be ignored, got %d choices, content %q", len(result.Choices), result.Content)
+	}
+}
+
+func TestStream_OpenAILogprobs(t *testing.T) {
+	input := `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"},"logprobs":{"content":[{"token":"Hi","logprob":-0.1,"bytes":[72,105],"top_logprobs":[{"token":"Hi","logprob":-0.1},{"token":"Hello","logprob":-2.3}]}]}}]}
+
+data: {"id":"1","choices":[{"index":0,"delta":{"content":"!"},"logprobs":{"content":[{"token":"!","logprob":-0.5}]},"finish_reason":"stop"}]}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), OpenAIFormat).Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	if len(result.Logprobs) != 2 {
+		t.Fatalf("len(Logprobs) = %d, want 2", len(result.Logprobs))
+	}
+	first := result.Logprobs[0]
+	if first.Token != "Hi" || first.Logprob != -0.1 || len(first.TopLogprobs) != 2 {
+		t.Errorf("unexpected first logprob: %+v", first)
+	}
+	if result.Logprobs[1].Token != "!" {
+		t.Errorf("unexpected second token: %q", result.Logprobs[1].Token)
+	}
+}
+
+func TestNewChunkStream(t *testing.T) {
+	stream := NewChunkStream(context.Background(), func(emit func(*Chunk) error) error {
+		for _, s := range []string{"a", "b"} {
+			if err := emit(&Chunk{Content: s}); err != nil {
+				return err
+			}
+		}
+		return emit(&Chunk{FinishReason: "stop", Usage: &Usage{PromptTokens: 1, CompletionTokens: 2}})
+	})
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	if result.Content != "ab" || result.FinishReason != "stop" || result.Usage.TotalTokens != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestNewChunkStream_CloseUnblocksProducer(t *testing.T) {
+	released := make(chan struct{})
+	stream := NewChunkStream(context.Background(), func(emit func(*Chunk) error) error {
+		defer close(released)
+		for {
+			if err := emit(&Chunk{Content: "x"}); err != nil {
+				return err
+			}
+		}
+	})
+	<-stream.Chunks()
+	stream.Close()
+	select {
+	case <-released:
+	case <-time.After(2 * time.Second):
+		t.Fatal("producer not released after Close")
+	}
+}
+
+func TestStream_Responses(t *testing.T) {
+	input := `event: response.created
+data: {"type":"response.created","response":{"id":"resp_1","model":"gpt-4o","status":"in_progress"}}
+
+event: response.output_item.added
+data: {"type":"response.output_item.added","output_index":0,"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"search","arguments":""}}
+
+event: response.function_call_arguments.delta
+data: {"type":"response.function_call_arguments.delta","item_id":"fc_1","output_index":0,"delta":"{\"q\":"}
+
+event: response.function_call_arguments.delta
+data: {"type":"response.function_call_arguments.delta","item_id":"fc_1","output_index":0,"delta":"\"go\"}"}
+
+event: response.output_text.delta
+data: {"type":"response.output_text.delta","item_id":"msg_1","output_index":1,"content_index":0,"delta":"Hi"}
+
+event: response.completed
+data: {"type":"response.completed","response":{"id":"resp_1","status":"completed","usage":{"input_tokens":10,"output_tokens":5,"total_tokens":15,"output_tokens_details":{"reasoning_tokens":2}}}}
+`
+	result, err := NewStream(strings.NewReader(input), ResponsesFormat).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.ID != "resp_1" || result.Model != "gpt-4o" || result.Content != "Hi" {
+		t.Errorf("unexpected result: id=%q model=%q content=%q", result.ID, result.Model, result.Content)
+	}
+	if result.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want tool_calls", result.FinishReason)
+	}
+	if len(result.ToolCalls) != 1 || result.ToolCalls[0].ID != "call_1" || result.ToolCalls[0].Arguments != `{"q":"go"}` {
+		t.Errorf("unexpected tool calls: %+v", result.ToolCalls)
+	}
+	if result.Usage.TotalTokens != 15 || result.Usage.ReasoningTokens != 2 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
+
+func TestResponsesParser_Failed(t *testing.T) {
+	p := &ResponsesParser{}
+	data := []byte(`{"type":"response.failed","response":{"status":"failed","error":{"code":"server_error","message":"boom"}}}`)
+	if _, err := p.Parse(data); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Parse error = %v, want failure message", err)
+	}
+	if p.IsDone(data) {
+		t.Error("response.failed should not be treated as normal completion")
+	}
+}
+
+func TestStream_TextCompletion(t *testing.T) {
+	input := `data: {"id":"cmpl-1","object":"text_completion","model":"deepseek-chat","choices":[{"index":0,"text":"return ","finish_reason":null}]}
+
+data: {"id":"cmpl-1","object":"text_completion","model":"deepseek-chat","choices":[{"index":0,"text":"a + b","finish_reason":"stop"}]}
+
+data: {"id":"cmpl-1","object":"text_completion","model":"deepseek-chat","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":4,"total_tokens":16}}
+
+data: [DONE]
+
+`
+	result, err := NewStream(strings.NewReader(input), TextCompletionFormat).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if result.ID != "cmpl-1" || result.Content != "return a + b" || result.FinishReason != "stop" {
+		t.Errorf("unexpected result: id=%q content=%q finish=%q", result.ID, result.Content, result.FinishReason)
+	}
+	if result.Usage.TotalTokens != 16 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
@@ -57,6 +57,9 @@ type Message struct {
 	ToolCallID string `json:"tool_call_id,omitempty"`
 	// ToolCalls 工具调用列表（当 Role=RoleAssistant 且 LLM 请求工具调用时填充）
 	ToolCalls []ToolCallRef `json:"tool_calls,omitempty"`
+	// Reasoning 推理/思考过程（仅 Role=RoleAssistant 时有意义）
+	// 是否在后续轮次中回传给模型由调用方的推理历史策略决定
+	Reasoning string `json:"reasoning,omitempty"`
 }
 
 // ToolCallRef 轻量工具调用引用，存储在 Message 中